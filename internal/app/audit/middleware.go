@@ -0,0 +1,91 @@
+package audit
+
+import (
+	"context"
+
+	"shadow-id/internal/app/auth"
+	"shadow-id/internal/app/pipeline"
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/repositories"
+	"shadow-id/pkg/errors"
+	"shadow-id/pkg/logger"
+)
+
+// Middleware appends an audit entry for every command, whether it succeeded or
+// failed. Successful commands record how their target changed; their entry is
+// written in the command's transaction, so a command whose entry cannot be
+// written is rolled back. Failures are recorded afterwards, and an entry that
+// cannot be written is only logged since the command failed anyway.
+func Middleware(repo repositories.AuditRepository, snapshotter *Snapshotter, log logger.Logger) pipeline.Middleware {
+	return func(next pipeline.Next) pipeline.Next {
+		return func(ctx context.Context, req *pipeline.Request) (interface{}, error) {
+			if req.Kind != pipeline.KindCommand {
+				return next(ctx, req)
+			}
+
+			// Capture the target before the command changes it
+			target := req.AuditTarget(nil)
+			before := snapshot(ctx, snapshotter, target, log)
+
+			recorded := false
+			recordSuccess := func(ctx context.Context, result interface{}) error {
+				entry := newEntry(ctx, req)
+				succeededTarget := target
+				if resolved := req.AuditTarget(result); !resolved.IsEmpty() {
+					succeededTarget = resolved
+				}
+				after := snapshot(ctx, snapshotter, succeededTarget, log)
+				entry.Succeed(succeededTarget, entities.DiffAuditSnapshots(before, after))
+
+				if err := repo.Append(ctx, entry); err != nil {
+					return errors.Wrap(err, "failed to write audit entry")
+				}
+				recorded = true
+				return nil
+			}
+
+			result, err := next(pipeline.WithCommitHook(ctx, recordSuccess), req)
+			if err == nil {
+				// Without a transaction in the chain the hook never ran
+				if !recorded {
+					if appendErr := recordSuccess(context.WithoutCancel(ctx), result); appendErr != nil {
+						log.Error("Failed to write audit entry",
+							"action", req.Name, "correlation_id", pipeline.CorrelationID(ctx), "error", appendErr)
+					}
+				}
+				return result, nil
+			}
+
+			// Record the failure even if the command ran out of time
+			entry := newEntry(ctx, req)
+			entry.Fail(target, err)
+			if appendErr := repo.Append(context.WithoutCancel(ctx), entry); appendErr != nil {
+				log.Error("Failed to write audit entry",
+					"action", entry.Action, "target_id", entry.Target.ID,
+					"correlation_id", entry.CorrelationID, "error", appendErr)
+			}
+			return result, err
+		}
+	}
+}
+
+// newEntry starts an audit entry for a request made by the context's principal
+func newEntry(ctx context.Context, req *pipeline.Request) *entities.AuditEntry {
+	return entities.NewAuditEntry(
+		auth.PrincipalFromContext(ctx).ActorID(),
+		req.Name,
+		pipeline.CorrelationID(ctx),
+	)
+}
+
+// snapshot captures the target's state, logging rather than failing the
+// command when it cannot be loaded
+func snapshot(ctx context.Context, snapshotter *Snapshotter, target entities.AuditTarget, log logger.Logger) map[string]interface{} {
+	state, err := snapshotter.Snapshot(context.WithoutCancel(ctx), target)
+	if err != nil {
+		log.Warn("Failed to snapshot audit target",
+			"type", target.Type, "id", target.ID, "error", err)
+		return nil
+	}
+	return state
+}
@@ -0,0 +1,77 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/repositories"
+)
+
+// Snapshotter captures the state of audit targets so their changes can be
+// diffed. Snapshots are built from the entities' JSON form, which leaves out
+// secrets such as TOTP seeds and recovery code hashes.
+type Snapshotter struct {
+	userRepo      repositories.UserRepository
+	deviceRepo    repositories.DeviceRepository
+	twoFactorRepo repositories.TwoFactorRepository
+}
+
+// NewSnapshotter creates a new snapshotter
+func NewSnapshotter(
+	userRepo repositories.UserRepository,
+	deviceRepo repositories.DeviceRepository,
+	twoFactorRepo repositories.TwoFactorRepository,
+) *Snapshotter {
+	return &Snapshotter{
+		userRepo:      userRepo,
+		deviceRepo:    deviceRepo,
+		twoFactorRepo: twoFactorRepo,
+	}
+}
+
+// Snapshot returns the current state of the target, or nil if it does not
+// exist or its type is not snapshotted
+func (s *Snapshotter) Snapshot(ctx context.Context, target entities.AuditTarget) (map[string]interface{}, error) {
+	if target.IsEmpty() {
+		return nil, nil
+	}
+
+	var entity interface{}
+	switch target.Type {
+	case entities.AuditTargetUser:
+		user, err := s.userRepo.GetByID(ctx, target.ID)
+		if err == nil && user == nil {
+			// Soft deletion and restoration show up as a change of deleted_at
+			user, err = s.userRepo.GetDeleted(ctx, target.ID)
+		}
+		if err != nil || user == nil {
+			return nil, err
+		}
+		entity = user
+	case entities.AuditTargetDevice:
+		device, err := s.deviceRepo.GetByID(ctx, target.ID)
+		if err != nil || device == nil {
+			return nil, err
+		}
+		entity = device
+	case entities.AuditTargetTwoFactor:
+		twoFactor, err := s.twoFactorRepo.GetByUserID(ctx, target.ID)
+		if err != nil || twoFactor == nil {
+			return nil, err
+		}
+		entity = twoFactor
+	default:
+		return nil, nil
+	}
+
+	encoded, err := json.Marshal(entity)
+	if err != nil {
+		return nil, err
+	}
+	var snapshot map[string]interface{}
+	if err := json.Unmarshal(encoded, &snapshot); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
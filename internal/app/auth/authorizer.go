@@ -0,0 +1,166 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/repositories"
+	"shadow-id/internal/domain/services"
+	"shadow-id/pkg/errors"
+)
+
+// Authorizer checks the principal in a context against a required permission
+// and, for requests targeting a resource, against attribute-based policies
+type Authorizer struct {
+	userRepo repositories.UserRepository
+	roleRepo repositories.RoleRepository
+	policies services.PolicyEngine
+}
+
+// NewAuthorizer creates a new authorizer. The policy engine may be nil, in
+// which case only role-based checks and the ownership policies apply.
+func NewAuthorizer(
+	userRepo repositories.UserRepository,
+	roleRepo repositories.RoleRepository,
+	policies services.PolicyEngine,
+) *Authorizer {
+	return &Authorizer{
+		userRepo: userRepo,
+		roleRepo: roleRepo,
+		policies: policies,
+	}
+}
+
+// Authorize returns an error unless the principal in the context holds the permission
+func (a *Authorizer) Authorize(ctx context.Context, permission entities.Permission) error {
+	if permission == entities.PermissionNone {
+		return nil
+	}
+
+	principal := PrincipalFromContext(ctx)
+	if principal.IsAnonymous() {
+		return errors.NewUnauthorizedError("authentication required")
+	}
+
+	roles, err := a.Roles(ctx, principal)
+	if err != nil {
+		return err
+	}
+	for _, role := range roles {
+		if role.HasPermission(permission) {
+			return nil
+		}
+	}
+
+	return errors.NewForbiddenError("permission denied").WithDetail("permission", string(permission))
+}
+
+// Roles resolves the roles held by a principal. Roles of a user principal are
+// loaded on every call so assignments take effect immediately.
+func (a *Authorizer) Roles(ctx context.Context, principal Principal) ([]*entities.Role, error) {
+	names := principal.Roles
+	if !principal.UserID.IsEmpty() {
+		user, err := a.userRepo.GetByID(ctx, principal.UserID)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get user")
+		}
+		if user == nil {
+			return nil, errors.NewUnauthorizedError("principal no longer exists")
+		}
+		names = append(append([]string(nil), names...), user.Roles...)
+	}
+
+	roles := make([]*entities.Role, 0, len(names))
+	for _, name := range names {
+		role, err := a.roleRepo.GetByName(ctx, name)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get role")
+		}
+		if role != nil {
+			roles = append(roles, role)
+		}
+	}
+	return roles, nil
+}
+
+// AuthorizeResource evaluates the policies for an action on a resource by the
+// principal in the context and returns an error if they deny it
+func (a *Authorizer) AuthorizeResource(ctx context.Context, action entities.Permission, resource entities.PolicyAttributes) error {
+	decision, err := a.Explain(ctx, PrincipalFromContext(ctx), action, resource, nil)
+	if err != nil {
+		return err
+	}
+	if !decision.Allowed {
+		return errors.WrapWithType(entities.ErrPolicyDenied, errors.ErrorTypeForbidden, "permission denied").
+			WithDetail("policy", decision.PolicyID).
+			WithDetail("reason", decision.Reason)
+	}
+	return nil
+}
+
+// Explain evaluates the policies for an action on a resource by a principal
+// without enforcing the outcome
+func (a *Authorizer) Explain(
+	ctx context.Context,
+	principal Principal,
+	action entities.Permission,
+	resource entities.PolicyAttributes,
+	attributes entities.PolicyAttributes,
+) (services.PolicyDecision, error) {
+	subject, err := a.subject(ctx, principal)
+	if err != nil {
+		return services.PolicyDecision{}, err
+	}
+	requestContext := entities.PolicyAttributes{
+		"time": time.Now().UTC().Format(time.RFC3339),
+	}
+	for key, value := range attributes {
+		requestContext[key] = value
+	}
+	request := entities.PolicyRequest{
+		Subject:  subject,
+		Resource: resource,
+		Action:   string(action),
+		Context:  requestContext,
+	}
+
+	// Ownership is decided before the configured policies, which cannot relax it
+	evaluations, denied := evaluateOwnership(request)
+	if denied != nil {
+		return *denied, nil
+	}
+	if a.policies == nil {
+		return services.PolicyDecision{
+			Allowed:     true,
+			Effect:      entities.PolicyEffectNotApplicable,
+			Reason:      "no policy engine configured",
+			Evaluations: evaluations,
+		}, nil
+	}
+
+	decision := a.policies.Evaluate(ctx, request)
+	decision.Evaluations = append(evaluations, decision.Evaluations...)
+	return decision, nil
+}
+
+// subject builds the policy attributes of a principal
+func (a *Authorizer) subject(ctx context.Context, principal Principal) (entities.PolicyAttributes, error) {
+	roles, err := a.Roles(ctx, principal)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(roles))
+	for i, role := range roles {
+		names[i] = role.Name
+	}
+
+	subject := entities.PolicyAttributes{
+		"roles":     names,
+		"anonymous": principal.IsAnonymous(),
+	}
+	if !principal.UserID.IsEmpty() {
+		subject["id"] = principal.UserID.String()
+	}
+	return subject, nil
+}
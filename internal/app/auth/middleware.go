@@ -0,0 +1,32 @@
+package auth
+
+import (
+	"context"
+
+	"shadow-id/internal/app/pipeline"
+)
+
+// Middleware authorizes every request against the permission its handler
+// declares and, for requests targeting a resource, against the policies
+func Middleware(authorizer *Authorizer) pipeline.Middleware {
+	return func(next pipeline.Next) pipeline.Next {
+		return func(ctx context.Context, req *pipeline.Request) (interface{}, error) {
+			if err := authorizer.Authorize(ctx, req.Permission); err != nil {
+				return nil, err
+			}
+
+			// Apply resource policies on top of the role check
+			resource, ok, err := req.Resource(ctx)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				if err := authorizer.AuthorizeResource(ctx, req.Permission, resource); err != nil {
+					return nil, err
+				}
+			}
+
+			return next(ctx, req)
+		}
+	}
+}
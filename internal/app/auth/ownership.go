@@ -0,0 +1,91 @@
+package auth
+
+import (
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/services"
+)
+
+// ownershipPolicies keep users from acting on each other's accounts. They are
+// enforced in code rather than loaded from the policy file, so they hold when
+// the file is missing and cannot be switched to dry-run mode.
+var ownershipPolicies = []entities.Policy{
+	{
+		ID:          "users-edit-self",
+		Description: "Users may only edit their own profile unless they are administrators",
+		Effect:      entities.PolicyEffectDeny,
+		Actions:     []string{string(entities.PermissionUsersUpdate)},
+		Conditions:  neitherOwnerNorAdmin("resource.id"),
+	},
+	{
+		ID:          "two-factor-managed-by-owner",
+		Description: "Users may only manage their own two-factor authentication unless they are administrators",
+		Effect:      entities.PolicyEffectDeny,
+		Actions:     []string{string(entities.PermissionTwoFactorManage)},
+		Conditions:  neitherOwnerNorAdmin("resource.owner_id"),
+	},
+	{
+		ID:          "passkeys-managed-by-owner",
+		Description: "Users may only register passkeys for their own account unless they are administrators",
+		Effect:      entities.PolicyEffectDeny,
+		Actions:     []string{string(entities.PermissionPasskeysManage)},
+		Conditions:  neitherOwnerNorAdmin("resource.owner_id"),
+	},
+	{
+		ID:          "devices-registered-by-owner",
+		Description: "Users may only register and match devices of their own account unless they are administrators",
+		Effect:      entities.PolicyEffectDeny,
+		Actions:     []string{string(entities.PermissionDevicesRegister)},
+		Conditions:  neitherOwnerNorAdmin("resource.owner_id"),
+	},
+	{
+		ID:          "devices-revoked-by-owner",
+		Description: "Devices can only be revoked by their owner or an administrator; principals without a user, such as background jobs, need the admin role",
+		Effect:      entities.PolicyEffectDeny,
+		Actions:     []string{string(entities.PermissionDevicesRevoke)},
+		Conditions:  neitherOwnerNorAdmin("resource.owner_id"),
+	},
+	{
+		ID:          "devices-heartbeat-by-owner",
+		Description: "Only a device's owner may report heartbeats for it",
+		Effect:      entities.PolicyEffectDeny,
+		Actions:     []string{string(entities.PermissionDevicesHeartbeat)},
+		Conditions: []entities.PolicyCondition{
+			{Attribute: "subject.id", Operator: entities.OperatorNotEquals, Ref: "resource.owner_id"},
+		},
+	},
+}
+
+// neitherOwnerNorAdmin holds when the subject neither owns the resource, as
+// named by the owner attribute, nor is an administrator
+func neitherOwnerNorAdmin(owner string) []entities.PolicyCondition {
+	return []entities.PolicyCondition{
+		{Attribute: "subject.id", Operator: entities.OperatorNotEquals, Ref: owner},
+		{Attribute: "subject.roles", Operator: entities.OperatorNotContains, Value: entities.RoleAdmin},
+	}
+}
+
+// evaluateOwnership evaluates the ownership policies and returns how each
+// responded, along with the denial when one of them matched
+func evaluateOwnership(request entities.PolicyRequest) ([]services.PolicyEvaluation, *services.PolicyDecision) {
+	evaluations := make([]services.PolicyEvaluation, 0, len(ownershipPolicies))
+	for i := range ownershipPolicies {
+		policy := &ownershipPolicies[i]
+		matched, reason := policy.Matches(request)
+		evaluations = append(evaluations, services.PolicyEvaluation{
+			PolicyID: policy.ID,
+			Effect:   policy.Effect,
+			Matched:  matched,
+			Reason:   reason,
+		})
+		if matched {
+			return evaluations, &services.PolicyDecision{
+				Allowed:     false,
+				Effect:      entities.PolicyEffectDeny,
+				PolicyID:    policy.ID,
+				Reason:      policy.Description + " (" + reason + ")",
+				Evaluations: evaluations,
+			}
+		}
+	}
+	return evaluations, nil
+}
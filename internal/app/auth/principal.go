@@ -0,0 +1,69 @@
+package auth
+
+import (
+	"context"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/pkg/types"
+)
+
+// Principal identifies the caller an operation runs on behalf of
+type Principal struct {
+	UserID types.ID `json:"user_id,omitempty"`
+
+	// Roles are granted directly instead of being loaded from the user record.
+	// They are used for the bootstrap and system principals that have no user.
+	Roles []string `json:"roles,omitempty"`
+
+	// Actor names a principal that has no user, so what it does can still be
+	// attributed
+	Actor string `json:"actor,omitempty"`
+}
+
+// Actors of the principals that have no user
+const (
+	ActorBootstrap = "bootstrap"
+	ActorSystem    = "system"
+)
+
+// principalKey is the context key for the current principal
+type principalKey struct{}
+
+// BootstrapPrincipal returns the principal used before any user exists so the
+// first administrator can be created
+func BootstrapPrincipal() Principal {
+	return Principal{Roles: []string{entities.RoleAdmin}, Actor: ActorBootstrap}
+}
+
+// SystemPrincipal returns the principal background jobs run as
+func SystemPrincipal() Principal {
+	return Principal{Roles: []string{entities.RoleAdmin}, Actor: ActorSystem}
+}
+
+// ActorID returns the ID actions of the principal are attributed to: its user,
+// or its actor name if it has no user
+func (p Principal) ActorID() types.ID {
+	if p.UserID.IsEmpty() {
+		return types.ID(p.Actor)
+	}
+	return p.UserID
+}
+
+// IsAnonymous checks if the principal carries no identity or roles
+func (p Principal) IsAnonymous() bool {
+	return p.UserID.IsEmpty() && len(p.Roles) == 0
+}
+
+// WithPrincipal returns a context carrying the principal
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext returns the principal carried by the context, or an
+// anonymous principal if there is none
+func PrincipalFromContext(ctx context.Context) Principal {
+	if principal, ok := ctx.Value(principalKey{}).(Principal); ok {
+		return principal
+	}
+	return Principal{}
+}
@@ -0,0 +1,120 @@
+package commands
+
+import (
+	"context"
+
+	"shadow-id/internal/app/auth"
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/repositories"
+	"shadow-id/pkg/errors"
+	"shadow-id/pkg/types"
+)
+
+// ApproveDeviceCommand represents the command to trust a pending or suspended
+// device. A non-zero version must match the device's profile version.
+type ApproveDeviceCommand struct {
+	DeviceID types.ID `json:"device_id" validate:"required"`
+	Version  int64    `json:"version" validate:"min=0"`
+}
+
+// DeviceTrustResult represents a device after its trust state changed
+type DeviceTrustResult struct {
+	DeviceID       types.ID `json:"device_id"`
+	UserID         types.ID `json:"user_id"`
+	TrustState     string   `json:"trust_state"`
+	TrustChangedAt string   `json:"trust_changed_at"`
+	ApprovedBy     types.ID `json:"approved_by,omitempty"`
+	Version        int64    `json:"version"`
+}
+
+// ApproveDeviceHandler handles the approve device command
+type ApproveDeviceHandler struct {
+	deviceRepo repositories.DeviceRepository
+}
+
+// NewApproveDeviceHandler creates a new approve device handler
+func NewApproveDeviceHandler(deviceRepo repositories.DeviceRepository) *ApproveDeviceHandler {
+	return &ApproveDeviceHandler{
+		deviceRepo: deviceRepo,
+	}
+}
+
+// RequiredPermission returns the permission needed to execute the command
+func (h *ApproveDeviceHandler) RequiredPermission() entities.Permission {
+	return entities.PermissionDevicesApprove
+}
+
+// PolicyResource describes the device being approved for policy evaluation
+func (h *ApproveDeviceHandler) PolicyResource(ctx context.Context, cmd ApproveDeviceCommand) (entities.PolicyAttributes, error) {
+	device, err := loadTrustDevice(ctx, h.deviceRepo, cmd.DeviceID, "device approval failed")
+	if err != nil {
+		return nil, err
+	}
+	return devicePolicyAttributes(device), nil
+}
+
+// AuditTarget names the device being approved
+func (h *ApproveDeviceHandler) AuditTarget(cmd ApproveDeviceCommand, res *DeviceTrustResult) entities.AuditTarget {
+	return entities.AuditTarget{Type: entities.AuditTargetDevice, ID: cmd.DeviceID}
+}
+
+// Handle executes the approve device command
+func (h *ApproveDeviceHandler) Handle(ctx context.Context, cmd ApproveDeviceCommand) (*DeviceTrustResult, error) {
+	// Load device
+	device, err := loadTrustDevice(ctx, h.deviceRepo, cmd.DeviceID, "device approval failed")
+	if err != nil {
+		return nil, err
+	}
+	if err := checkExpectedVersion(cmd.Version, device.ProfileVersion); err != nil {
+		return nil, err
+	}
+
+	// Approve on behalf of the caller
+	if err := device.Approve(auth.PrincipalFromContext(ctx).UserID); err != nil {
+		return nil, errors.WrapWithType(err, errors.ErrorTypeConflict, "device approval failed")
+	}
+
+	// Save device
+	if err := h.deviceRepo.Update(ctx, device); err != nil {
+		return nil, errors.Wrap(err, "failed to update device")
+	}
+
+	// Return result
+	return newDeviceTrustResult(device), nil
+}
+
+// loadTrustDevice loads a device whose trust state is about to change or
+// returns a not found error
+func loadTrustDevice(ctx context.Context, deviceRepo repositories.DeviceRepository, deviceID types.ID, failure string) (*entities.Device, error) {
+	device, err := deviceRepo.GetByID(ctx, deviceID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get device")
+	}
+	if device == nil {
+		return nil, errors.WrapWithType(entities.ErrDeviceNotFound, errors.ErrorTypeNotFound, failure)
+	}
+	return device, nil
+}
+
+// devicePolicyAttributes describes a device for policy evaluation
+func devicePolicyAttributes(device *entities.Device) entities.PolicyAttributes {
+	return entities.PolicyAttributes{
+		"type":        "device",
+		"id":          device.ID.String(),
+		"owner_id":    device.UserID.String(),
+		"platform":    device.Platform,
+		"trust_state": string(device.TrustState),
+	}
+}
+
+// newDeviceTrustResult creates the result of a trust state change
+func newDeviceTrustResult(device *entities.Device) *DeviceTrustResult {
+	return &DeviceTrustResult{
+		DeviceID:       device.ID,
+		UserID:         device.UserID,
+		TrustState:     string(device.TrustState),
+		TrustChangedAt: device.TrustChangedAt.Format("2006-01-02T15:04:05Z07:00"),
+		ApprovedBy:     device.ApprovedBy,
+		Version:        device.ProfileVersion,
+	}
+}
@@ -0,0 +1,88 @@
+package commands
+
+import (
+	"context"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/repositories"
+	"shadow-id/pkg/errors"
+	"shadow-id/pkg/types"
+)
+
+// AssignRoleCommand represents the command to assign a role to a user
+type AssignRoleCommand struct {
+	UserID types.ID `json:"user_id" validate:"required"`
+	Role   string   `json:"role" validate:"required"`
+}
+
+// AssignRoleResult represents a user's roles after an assignment change
+type AssignRoleResult struct {
+	UserID    types.ID `json:"user_id"`
+	Roles     []string `json:"roles"`
+	UpdatedAt string   `json:"updated_at"`
+	Version   int64    `json:"version"`
+}
+
+// AssignRoleHandler handles the assign role command
+type AssignRoleHandler struct {
+	userRepo repositories.UserRepository
+	roleRepo repositories.RoleRepository
+}
+
+// NewAssignRoleHandler creates a new assign role handler
+func NewAssignRoleHandler(
+	userRepo repositories.UserRepository,
+	roleRepo repositories.RoleRepository,
+) *AssignRoleHandler {
+	return &AssignRoleHandler{
+		userRepo: userRepo,
+		roleRepo: roleRepo,
+	}
+}
+
+// RequiredPermission returns the permission needed to execute the command
+func (h *AssignRoleHandler) RequiredPermission() entities.Permission {
+	return entities.PermissionRolesAssign
+}
+
+// AuditTarget names the user whose roles change
+func (h *AssignRoleHandler) AuditTarget(cmd AssignRoleCommand, res *AssignRoleResult) entities.AuditTarget {
+	return entities.AuditTarget{Type: entities.AuditTargetUser, ID: cmd.UserID}
+}
+
+// Handle executes the assign role command
+func (h *AssignRoleHandler) Handle(ctx context.Context, cmd AssignRoleCommand) (*AssignRoleResult, error) {
+	// Load role
+	role, err := h.roleRepo.GetByName(ctx, cmd.Role)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get role")
+	}
+	if role == nil {
+		return nil, errors.WrapWithType(entities.ErrRoleNotFound, errors.ErrorTypeNotFound, "role assignment failed")
+	}
+
+	// Load user
+	user, err := h.userRepo.GetByID(ctx, cmd.UserID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get user")
+	}
+	if user == nil {
+		return nil, errors.NewNotFoundError("user not found")
+	}
+
+	// Assign role
+	if err := user.AssignRole(role.Name); err != nil {
+		return nil, errors.WrapWithType(err, errors.ErrorTypeConflict, "role assignment failed")
+	}
+	if err := h.userRepo.Update(ctx, user); err != nil {
+		return nil, errors.Wrap(err, "failed to update user")
+	}
+
+	// Return result
+	return &AssignRoleResult{
+		UserID:    user.ID,
+		Roles:     user.Roles,
+		UpdatedAt: user.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		Version:   user.ProfileVersion,
+	}, nil
+}
@@ -0,0 +1,115 @@
+package commands
+
+import (
+	"context"
+	"encoding/base64"
+	"time"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/repositories"
+	"shadow-id/internal/domain/services"
+	"shadow-id/pkg/errors"
+	"shadow-id/pkg/types"
+)
+
+// BeginPasskeyLoginCommand represents the command to start a WebAuthn authentication
+// ceremony. Without a user ID the ceremony relies on discoverable credentials.
+type BeginPasskeyLoginCommand struct {
+	UserID types.ID `json:"user_id"`
+}
+
+// BeginPasskeyLoginResult represents the options passed to navigator.credentials.get
+type BeginPasskeyLoginResult struct {
+	SessionID types.ID                          `json:"session_id"`
+	PublicKey PublicKeyCredentialRequestOptions `json:"public_key"`
+}
+
+// PublicKeyCredentialRequestOptions mirrors the WebAuthn request options
+// dictionary; binary values are base64url encoded
+type PublicKeyCredentialRequestOptions struct {
+	Challenge        string                          `json:"challenge"`
+	Timeout          int                             `json:"timeout"`
+	RPID             string                          `json:"rpId"`
+	AllowCredentials []PublicKeyCredentialDescriptor `json:"allowCredentials"`
+	UserVerification string                          `json:"userVerification"`
+}
+
+// BeginPasskeyLoginHandler handles the begin passkey login command
+type BeginPasskeyLoginHandler struct {
+	userRepo        repositories.UserRepository
+	credentialRepo  repositories.WebAuthnCredentialRepository
+	sessionRepo     repositories.WebAuthnSessionRepository
+	webauthnService services.WebAuthnService
+}
+
+// NewBeginPasskeyLoginHandler creates a new begin passkey login handler
+func NewBeginPasskeyLoginHandler(
+	userRepo repositories.UserRepository,
+	credentialRepo repositories.WebAuthnCredentialRepository,
+	sessionRepo repositories.WebAuthnSessionRepository,
+	webauthnService services.WebAuthnService,
+) *BeginPasskeyLoginHandler {
+	return &BeginPasskeyLoginHandler{
+		userRepo:        userRepo,
+		credentialRepo:  credentialRepo,
+		sessionRepo:     sessionRepo,
+		webauthnService: webauthnService,
+	}
+}
+
+// RequiredPermission returns the permission needed to execute the command
+func (h *BeginPasskeyLoginHandler) RequiredPermission() entities.Permission {
+	return entities.PermissionNone
+}
+
+// AuditTarget names the user signing in, if known up front
+func (h *BeginPasskeyLoginHandler) AuditTarget(cmd BeginPasskeyLoginCommand, res *BeginPasskeyLoginResult) entities.AuditTarget {
+	return entities.AuditTarget{Type: entities.AuditTargetUser, ID: cmd.UserID}
+}
+
+// Handle executes the begin passkey login command
+func (h *BeginPasskeyLoginHandler) Handle(ctx context.Context, cmd BeginPasskeyLoginCommand) (*BeginPasskeyLoginResult, error) {
+	// Restrict to the user's credentials when the user is known
+	allowCredentials := []PublicKeyCredentialDescriptor{}
+	if !cmd.UserID.IsEmpty() {
+		user, err := h.userRepo.GetByID(ctx, cmd.UserID)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get user")
+		}
+		if user == nil {
+			return nil, errors.NewNotFoundError("user not found")
+		}
+
+		credentials, err := h.credentialRepo.ListByUserID(ctx, user.ID)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list credentials")
+		}
+		if len(credentials) == 0 {
+			return nil, errors.WrapWithType(entities.ErrCredentialNotFound, errors.ErrorTypeNotFound, "passkey login failed")
+		}
+		allowCredentials = credentialDescriptors(credentials)
+	}
+
+	// Start ceremony
+	challenge, err := h.webauthnService.NewChallenge()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate challenge")
+	}
+	rp := h.webauthnService.RelyingParty()
+	session := entities.NewWebAuthnSession(entities.CeremonyAuthentication, cmd.UserID, "", challenge, time.Duration(rp.TimeoutSeconds)*time.Second)
+	if err := h.sessionRepo.Create(ctx, session); err != nil {
+		return nil, errors.Wrap(err, "failed to save webauthn session")
+	}
+
+	// Return result
+	return &BeginPasskeyLoginResult{
+		SessionID: session.ID,
+		PublicKey: PublicKeyCredentialRequestOptions{
+			Challenge:        base64.RawURLEncoding.EncodeToString(challenge),
+			Timeout:          rp.TimeoutSeconds * 1000,
+			RPID:             rp.ID,
+			AllowCredentials: allowCredentials,
+			UserVerification: "preferred",
+		},
+	}, nil
+}
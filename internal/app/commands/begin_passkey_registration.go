@@ -0,0 +1,195 @@
+package commands
+
+import (
+	"context"
+	"encoding/base64"
+	"time"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/repositories"
+	"shadow-id/internal/domain/services"
+	"shadow-id/pkg/errors"
+	"shadow-id/pkg/types"
+)
+
+// BeginPasskeyRegistrationCommand represents the command to start a WebAuthn registration ceremony
+type BeginPasskeyRegistrationCommand struct {
+	UserID   types.ID `json:"user_id" validate:"required"`
+	DeviceID types.ID `json:"device_id"`
+}
+
+// BeginPasskeyRegistrationResult represents the options passed to navigator.credentials.create
+type BeginPasskeyRegistrationResult struct {
+	SessionID types.ID                           `json:"session_id"`
+	PublicKey PublicKeyCredentialCreationOptions `json:"public_key"`
+}
+
+// PublicKeyCredentialCreationOptions mirrors the WebAuthn creation options
+// dictionary; binary values are base64url encoded
+type PublicKeyCredentialCreationOptions struct {
+	Challenge              string                          `json:"challenge"`
+	RP                     PublicKeyCredentialRPEntity     `json:"rp"`
+	User                   PublicKeyCredentialUserEntity   `json:"user"`
+	PubKeyCredParams       []PublicKeyCredentialParameters `json:"pubKeyCredParams"`
+	Timeout                int                             `json:"timeout"`
+	Attestation            string                          `json:"attestation"`
+	ExcludeCredentials     []PublicKeyCredentialDescriptor `json:"excludeCredentials"`
+	AuthenticatorSelection AuthenticatorSelectionCriteria  `json:"authenticatorSelection"`
+}
+
+// PublicKeyCredentialRPEntity describes the relying party
+type PublicKeyCredentialRPEntity struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// PublicKeyCredentialUserEntity describes the user account being registered
+type PublicKeyCredentialUserEntity struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+}
+
+// PublicKeyCredentialParameters describes an acceptable credential algorithm
+type PublicKeyCredentialParameters struct {
+	Type string `json:"type"`
+	Alg  int64  `json:"alg"`
+}
+
+// PublicKeyCredentialDescriptor identifies an existing credential
+type PublicKeyCredentialDescriptor struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+// AuthenticatorSelectionCriteria describes authenticator requirements
+type AuthenticatorSelectionCriteria struct {
+	ResidentKey      string `json:"residentKey"`
+	UserVerification string `json:"userVerification"`
+}
+
+// BeginPasskeyRegistrationHandler handles the begin passkey registration command
+type BeginPasskeyRegistrationHandler struct {
+	userRepo        repositories.UserRepository
+	deviceRepo      repositories.DeviceRepository
+	credentialRepo  repositories.WebAuthnCredentialRepository
+	sessionRepo     repositories.WebAuthnSessionRepository
+	webauthnService services.WebAuthnService
+}
+
+// NewBeginPasskeyRegistrationHandler creates a new begin passkey registration handler
+func NewBeginPasskeyRegistrationHandler(
+	userRepo repositories.UserRepository,
+	deviceRepo repositories.DeviceRepository,
+	credentialRepo repositories.WebAuthnCredentialRepository,
+	sessionRepo repositories.WebAuthnSessionRepository,
+	webauthnService services.WebAuthnService,
+) *BeginPasskeyRegistrationHandler {
+	return &BeginPasskeyRegistrationHandler{
+		userRepo:        userRepo,
+		deviceRepo:      deviceRepo,
+		credentialRepo:  credentialRepo,
+		sessionRepo:     sessionRepo,
+		webauthnService: webauthnService,
+	}
+}
+
+// RequiredPermission returns the permission needed to execute the command
+func (h *BeginPasskeyRegistrationHandler) RequiredPermission() entities.Permission {
+	return entities.PermissionPasskeysManage
+}
+
+// PolicyResource describes the user registering a passkey for policy evaluation
+func (h *BeginPasskeyRegistrationHandler) PolicyResource(ctx context.Context, cmd BeginPasskeyRegistrationCommand) (entities.PolicyAttributes, error) {
+	return userPolicyAttributes(cmd.UserID), nil
+}
+
+// AuditTarget names the user registering a passkey
+func (h *BeginPasskeyRegistrationHandler) AuditTarget(cmd BeginPasskeyRegistrationCommand, res *BeginPasskeyRegistrationResult) entities.AuditTarget {
+	return entities.AuditTarget{Type: entities.AuditTargetUser, ID: cmd.UserID}
+}
+
+// Handle executes the begin passkey registration command
+func (h *BeginPasskeyRegistrationHandler) Handle(ctx context.Context, cmd BeginPasskeyRegistrationCommand) (*BeginPasskeyRegistrationResult, error) {
+	// Load user
+	user, err := h.userRepo.GetByID(ctx, cmd.UserID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get user")
+	}
+	if user == nil {
+		return nil, errors.NewNotFoundError("user not found")
+	}
+
+	// Optional device the passkey will be bound to must belong to the user and
+	// not be suspended or revoked
+	if !cmd.DeviceID.IsEmpty() {
+		device, err := h.deviceRepo.GetByID(ctx, cmd.DeviceID)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get device")
+		}
+		if device == nil {
+			return nil, errors.NewNotFoundError("device not found")
+		}
+		if device.UserID != user.ID {
+			return nil, errors.WrapWithType(entities.ErrDeviceOwnerMismatch, errors.ErrorTypeValidation, "passkey registration failed")
+		}
+		if !device.CanAuthenticate() {
+			return nil, errors.WrapWithType(entities.ErrDeviceNotTrusted, errors.ErrorTypeForbidden, "passkey registration failed")
+		}
+	}
+
+	// Exclude credentials the user already has so authenticators don't register twice
+	existing, err := h.credentialRepo.ListByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list credentials")
+	}
+
+	// Start ceremony
+	challenge, err := h.webauthnService.NewChallenge()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate challenge")
+	}
+	rp := h.webauthnService.RelyingParty()
+	session := entities.NewWebAuthnSession(entities.CeremonyRegistration, user.ID, cmd.DeviceID, challenge, time.Duration(rp.TimeoutSeconds)*time.Second)
+	if err := h.sessionRepo.Create(ctx, session); err != nil {
+		return nil, errors.Wrap(err, "failed to save webauthn session")
+	}
+
+	// Return result
+	params := make([]PublicKeyCredentialParameters, len(rp.Algorithms))
+	for i, alg := range rp.Algorithms {
+		params[i] = PublicKeyCredentialParameters{Type: "public-key", Alg: alg}
+	}
+	return &BeginPasskeyRegistrationResult{
+		SessionID: session.ID,
+		PublicKey: PublicKeyCredentialCreationOptions{
+			Challenge: base64.RawURLEncoding.EncodeToString(challenge),
+			RP:        PublicKeyCredentialRPEntity{ID: rp.ID, Name: rp.Name},
+			User: PublicKeyCredentialUserEntity{
+				ID:          base64.RawURLEncoding.EncodeToString([]byte(user.ID)),
+				Name:        user.Email,
+				DisplayName: user.Name,
+			},
+			PubKeyCredParams:   params,
+			Timeout:            rp.TimeoutSeconds * 1000,
+			Attestation:        "direct",
+			ExcludeCredentials: credentialDescriptors(existing),
+			AuthenticatorSelection: AuthenticatorSelectionCriteria{
+				ResidentKey:      "preferred",
+				UserVerification: "preferred",
+			},
+		},
+	}, nil
+}
+
+// credentialDescriptors converts stored credentials to descriptors
+func credentialDescriptors(credentials []*entities.WebAuthnCredential) []PublicKeyCredentialDescriptor {
+	descriptors := make([]PublicKeyCredentialDescriptor, len(credentials))
+	for i, credential := range credentials {
+		descriptors[i] = PublicKeyCredentialDescriptor{
+			Type: "public-key",
+			ID:   base64.RawURLEncoding.EncodeToString(credential.CredentialID),
+		}
+	}
+	return descriptors
+}
@@ -0,0 +1,37 @@
+package commands
+
+// All returns a zero value of every command. The bus is verified against this
+// list at startup, so a new command must be added here and given a handler.
+func All() []interface{} {
+	return []interface{}{
+		ApproveDeviceCommand{},
+		AssignRoleCommand{},
+		BeginPasskeyLoginCommand{},
+		BeginPasskeyRegistrationCommand{},
+		ConfirmTwoFactorCommand{},
+		CreateUserCommand{},
+		DeleteUserCommand{},
+		DisableTwoFactorCommand{},
+		EnrollTwoFactorCommand{},
+		FinishPasskeyLoginCommand{},
+		FinishPasskeyRegistrationCommand{},
+		IssueDeviceChallengeCommand{},
+		MarkStaleDevicesCommand{},
+		MatchDeviceCommand{},
+		PurgeDeletedUsersCommand{},
+		RebuildProjectionsCommand{},
+		RecordDeviceHeartbeatCommand{},
+		RegenerateRecoveryCodesCommand{},
+		RegisterDeviceCommand{},
+		RestoreUserCommand{},
+		RevokeDeviceCommand{},
+		RevokeRoleCommand{},
+		SetTwoFactorRequirementCommand{},
+		SignOutCommand{},
+		SuspendDeviceCommand{},
+		UnlockAccountCommand{},
+		UpdateUserCommand{},
+		VerifyDeviceChallengeCommand{},
+		VerifyTwoFactorCommand{},
+	}
+}
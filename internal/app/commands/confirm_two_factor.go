@@ -0,0 +1,93 @@
+package commands
+
+import (
+	"context"
+	"time"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/repositories"
+	"shadow-id/internal/domain/services"
+	"shadow-id/pkg/errors"
+	"shadow-id/pkg/types"
+)
+
+// ConfirmTwoFactorCommand represents the command to confirm a pending TOTP enrollment
+type ConfirmTwoFactorCommand struct {
+	UserID types.ID `json:"user_id" validate:"required"`
+	Code   string   `json:"code" validate:"required"`
+}
+
+// ConfirmTwoFactorResult represents the result of confirming TOTP enrollment
+type ConfirmTwoFactorResult struct {
+	UserID      types.ID `json:"user_id"`
+	Enabled     bool     `json:"enabled"`
+	ConfirmedAt string   `json:"confirmed_at"`
+}
+
+// ConfirmTwoFactorHandler handles the confirm two-factor command
+type ConfirmTwoFactorHandler struct {
+	twoFactorRepo repositories.TwoFactorRepository
+	totpService   services.TOTPService
+}
+
+// NewConfirmTwoFactorHandler creates a new confirm two-factor handler
+func NewConfirmTwoFactorHandler(
+	twoFactorRepo repositories.TwoFactorRepository,
+	totpService services.TOTPService,
+) *ConfirmTwoFactorHandler {
+	return &ConfirmTwoFactorHandler{
+		twoFactorRepo: twoFactorRepo,
+		totpService:   totpService,
+	}
+}
+
+// RequiredPermission returns the permission needed to execute the command
+func (h *ConfirmTwoFactorHandler) RequiredPermission() entities.Permission {
+	return entities.PermissionTwoFactorManage
+}
+
+// PolicyResource describes the user confirming two-factor enrollment for policy evaluation
+func (h *ConfirmTwoFactorHandler) PolicyResource(ctx context.Context, cmd ConfirmTwoFactorCommand) (entities.PolicyAttributes, error) {
+	return userPolicyAttributes(cmd.UserID), nil
+}
+
+// AuditTarget names the enrollment being confirmed
+func (h *ConfirmTwoFactorHandler) AuditTarget(cmd ConfirmTwoFactorCommand, res *ConfirmTwoFactorResult) entities.AuditTarget {
+	return entities.AuditTarget{Type: entities.AuditTargetTwoFactor, ID: cmd.UserID}
+}
+
+// Handle executes the confirm two-factor command
+func (h *ConfirmTwoFactorHandler) Handle(ctx context.Context, cmd ConfirmTwoFactorCommand) (*ConfirmTwoFactorResult, error) {
+	// Load pending enrollment
+	twoFactor, err := h.twoFactorRepo.GetByUserID(ctx, cmd.UserID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get two-factor enrollment")
+	}
+	if twoFactor == nil {
+		return nil, errors.WrapWithType(entities.ErrTwoFactorNotEnrolled, errors.ErrorTypeNotFound, "two-factor confirmation failed")
+	}
+	if twoFactor.Confirmed {
+		return nil, errors.WrapWithType(entities.ErrTwoFactorAlreadyEnabled, errors.ErrorTypeConflict, "two-factor confirmation failed")
+	}
+
+	// Only a TOTP code can confirm enrollment
+	step, ok := h.totpService.Validate(twoFactor.Secret, cmd.Code, time.Now())
+	if !ok {
+		return nil, errors.WrapWithType(entities.ErrInvalidTwoFactorCode, errors.ErrorTypeValidation, "two-factor confirmation failed")
+	}
+	if err := twoFactor.Confirm(step); err != nil {
+		return nil, errors.WrapWithType(err, errors.ErrorTypeValidation, "two-factor confirmation failed")
+	}
+
+	// Save enrollment
+	if err := h.twoFactorRepo.Update(ctx, twoFactor); err != nil {
+		return nil, errors.Wrap(err, "failed to save two-factor enrollment")
+	}
+
+	// Return result
+	return &ConfirmTwoFactorResult{
+		UserID:      twoFactor.UserID,
+		Enabled:     true,
+		ConfirmedAt: twoFactor.ConfirmedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}, nil
+}
@@ -10,10 +10,15 @@ import (
 	"shadow-id/pkg/types"
 )
 
-// CreateUserCommand represents the command to create a user
+// CreateUserCommand represents the command to create a user. When a device
+// name is given the user's first device is registered along with the user,
+// bound to the base64url encoded public key the client generated for it.
 type CreateUserCommand struct {
-	Name  string `json:"name" validate:"required,min=2,max=100"`
-	Email string `json:"email" validate:"required,email"`
+	Name            string `json:"name" validate:"required,min=2,max=100"`
+	Email           string `json:"email" validate:"required,email"`
+	DeviceName      string `json:"device_name" validate:"max=100"`
+	DevicePlatform  string `json:"device_platform" validate:"max=50"`
+	DevicePublicKey string `json:"device_public_key" validate:"max=100"`
 }
 
 // CreateUserResult represents the result of creating a user
@@ -21,41 +26,64 @@ type CreateUserResult struct {
 	ID        types.ID `json:"id"`
 	Name      string   `json:"name"`
 	Email     string   `json:"email"`
+	Roles     []string `json:"roles"`
 	CreatedAt string   `json:"created_at"`
+	Version   int64    `json:"version"`
+	DeviceID  types.ID `json:"device_id,omitempty"`
 }
 
 // CreateUserHandler handles the create user command
 type CreateUserHandler struct {
 	userRepo    repositories.UserRepository
+	deviceRepo  repositories.DeviceRepository
+	uow         repositories.UnitOfWork
 	userService services.UserService
 }
 
 // NewCreateUserHandler creates a new create user handler
 func NewCreateUserHandler(
 	userRepo repositories.UserRepository,
+	deviceRepo repositories.DeviceRepository,
+	uow repositories.UnitOfWork,
 	userService services.UserService,
 ) *CreateUserHandler {
 	return &CreateUserHandler{
 		userRepo:    userRepo,
+		deviceRepo:  deviceRepo,
+		uow:         uow,
 		userService: userService,
 	}
 }
 
+// RequiredPermission returns the permission needed to execute the command
+func (h *CreateUserHandler) RequiredPermission() entities.Permission {
+	return entities.PermissionUsersCreate
+}
+
+// AuditTarget names the user the command acted on
+func (h *CreateUserHandler) AuditTarget(cmd CreateUserCommand, res *CreateUserResult) entities.AuditTarget {
+	// The user only has an ID once it was created
+	if res == nil {
+		return entities.AuditTarget{Type: entities.AuditTargetUser}
+	}
+	return entities.AuditTarget{Type: entities.AuditTargetUser, ID: res.ID}
+}
+
 // Handle executes the create user command
 func (h *CreateUserHandler) Handle(ctx context.Context, cmd CreateUserCommand) (*CreateUserResult, error) {
 	// Create user entity
 	user := entities.NewUser(cmd.Name, cmd.Email)
-	
+
 	// Validate user
 	if err := user.Validate(); err != nil {
 		return nil, errors.Wrap(err, "invalid user data")
 	}
-	
+
 	// Validate business rules
 	if err := h.userService.ValidateUserCreation(ctx, user); err != nil {
 		return nil, errors.Wrap(err, "user creation validation failed")
 	}
-	
+
 	// Check email uniqueness
 	isUnique, err := h.userService.IsEmailUnique(ctx, user.Email, "")
 	if err != nil {
@@ -64,17 +92,59 @@ func (h *CreateUserHandler) Handle(ctx context.Context, cmd CreateUserCommand) (
 	if !isUnique {
 		return nil, entities.ErrUserAlreadyExists
 	}
-	
-	// Save user
-	if err := h.userRepo.Create(ctx, user); err != nil {
-		return nil, errors.Wrap(err, "failed to create user")
+
+	// Create the first device entity
+	var device *entities.Device
+	if cmd.DeviceName != "" {
+		device = entities.NewDevice(user.ID, cmd.DeviceName, cmd.DevicePlatform)
+		if err := device.Validate(); err != nil {
+			return nil, errors.WrapWithType(err, errors.ErrorTypeValidation, "invalid device data")
+		}
+		if err := attachDeviceKey(device, cmd.DevicePublicKey); err != nil {
+			return nil, err
+		}
+	}
+
+	// Save the user and device together
+	err = h.uow.WithinTx(ctx, func(ctx context.Context) error {
+		// The first user administers the installation; everyone else starts with the default role
+		userCount, err := h.userRepo.Count(ctx)
+		if err != nil {
+			return errors.Wrap(err, "failed to count users")
+		}
+		role := entities.RoleUser
+		if userCount == 0 {
+			role = entities.RoleAdmin
+		}
+		if err := user.AssignRole(role); err != nil {
+			return errors.Wrap(err, "failed to assign role")
+		}
+
+		if err := h.userRepo.Create(ctx, user); err != nil {
+			return errors.Wrap(err, "failed to create user")
+		}
+		if device != nil {
+			if err := h.deviceRepo.Create(ctx, device); err != nil {
+				return errors.Wrap(err, "failed to register device")
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
-	
+
 	// Return result
-	return &CreateUserResult{
+	result := &CreateUserResult{
 		ID:        user.ID,
 		Name:      user.Name,
 		Email:     user.Email,
+		Roles:     user.Roles,
 		CreatedAt: user.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-	}, nil
+		Version:   user.ProfileVersion,
+	}
+	if device != nil {
+		result.DeviceID = device.ID
+	}
+	return result, nil
 }
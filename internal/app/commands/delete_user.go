@@ -0,0 +1,96 @@
+package commands
+
+import (
+	"context"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/repositories"
+	"shadow-id/pkg/errors"
+	"shadow-id/pkg/types"
+)
+
+// DeleteUserCommand represents the command to delete a user. A non-zero
+// version must match the user's current version.
+type DeleteUserCommand struct {
+	ID      types.ID `json:"id" validate:"required"`
+	Version int64    `json:"version" validate:"min=0"`
+}
+
+// DeleteUserResult represents the result of deleting a user
+type DeleteUserResult struct {
+	ID        types.ID `json:"id"`
+	DeletedAt string   `json:"deleted_at"`
+	Version   int64    `json:"version"`
+}
+
+// DeleteUserHandler handles the delete user command. Users are soft-deleted:
+// they keep their devices and credentials until RestoreUser brings them back
+// or PurgeDeletedUsers removes them after the retention period.
+type DeleteUserHandler struct {
+	userRepo repositories.UserRepository
+}
+
+// NewDeleteUserHandler creates a new delete user handler
+func NewDeleteUserHandler(userRepo repositories.UserRepository) *DeleteUserHandler {
+	return &DeleteUserHandler{
+		userRepo: userRepo,
+	}
+}
+
+// RequiredPermission returns the permission needed to execute the command
+func (h *DeleteUserHandler) RequiredPermission() entities.Permission {
+	return entities.PermissionUsersDelete
+}
+
+// PolicyResource describes the user being deleted for policy evaluation
+func (h *DeleteUserHandler) PolicyResource(ctx context.Context, cmd DeleteUserCommand) (entities.PolicyAttributes, error) {
+	return entities.PolicyAttributes{
+		"type":     "user",
+		"id":       cmd.ID.String(),
+		"owner_id": cmd.ID.String(),
+	}, nil
+}
+
+// AuditTarget names the user being deleted
+func (h *DeleteUserHandler) AuditTarget(cmd DeleteUserCommand, res *DeleteUserResult) entities.AuditTarget {
+	return entities.AuditTarget{Type: entities.AuditTargetUser, ID: cmd.ID}
+}
+
+// Handle executes the delete user command
+func (h *DeleteUserHandler) Handle(ctx context.Context, cmd DeleteUserCommand) (*DeleteUserResult, error) {
+	// Load user
+	user, err := h.userRepo.GetByID(ctx, cmd.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get user")
+	}
+	if user == nil {
+		return nil, errors.NewNotFoundError("user not found")
+	}
+	if err := checkExpectedVersion(cmd.Version, user.ProfileVersion); err != nil {
+		return nil, err
+	}
+
+	// Never leave the installation without an administrator
+	if user.HasRole(entities.RoleAdmin) {
+		admins, err := countAdmins(ctx, h.userRepo)
+		if err != nil {
+			return nil, err
+		}
+		if admins <= 1 {
+			return nil, errors.WrapWithType(entities.ErrLastAdmin, errors.ErrorTypeConflict, "user deletion failed")
+		}
+	}
+
+	// Delete user
+	user.MarkDeleted()
+	if err := h.userRepo.Delete(ctx, user); err != nil {
+		return nil, errors.Wrap(err, "failed to delete user")
+	}
+
+	// Return result
+	return &DeleteUserResult{
+		ID:        user.ID,
+		DeletedAt: user.DeletedAt.Format("2006-01-02T15:04:05Z07:00"),
+		Version:   user.ProfileVersion,
+	}, nil
+}
@@ -0,0 +1,98 @@
+package commands
+
+import (
+	"context"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/repositories"
+	"shadow-id/internal/domain/services"
+	"shadow-id/pkg/errors"
+	"shadow-id/pkg/types"
+)
+
+// DisableTwoFactorCommand represents the command to remove a user's TOTP enrollment
+type DisableTwoFactorCommand struct {
+	UserID types.ID `json:"user_id" validate:"required"`
+	Code   string   `json:"code" validate:"required"`
+}
+
+// DisableTwoFactorResult represents the result of disabling two-factor authentication
+type DisableTwoFactorResult struct {
+	UserID   types.ID `json:"user_id"`
+	Disabled bool     `json:"disabled"`
+}
+
+// DisableTwoFactorHandler handles the disable two-factor command
+type DisableTwoFactorHandler struct {
+	userRepo      repositories.UserRepository
+	twoFactorRepo repositories.TwoFactorRepository
+	totpService   services.TOTPService
+	guard         services.AuthenticationGuard
+}
+
+// NewDisableTwoFactorHandler creates a new disable two-factor handler
+func NewDisableTwoFactorHandler(
+	userRepo repositories.UserRepository,
+	twoFactorRepo repositories.TwoFactorRepository,
+	totpService services.TOTPService,
+	guard services.AuthenticationGuard,
+) *DisableTwoFactorHandler {
+	return &DisableTwoFactorHandler{
+		userRepo:      userRepo,
+		twoFactorRepo: twoFactorRepo,
+		totpService:   totpService,
+		guard:         guard,
+	}
+}
+
+// RequiredPermission returns the permission needed to execute the command
+func (h *DisableTwoFactorHandler) RequiredPermission() entities.Permission {
+	return entities.PermissionTwoFactorManage
+}
+
+// PolicyResource describes the user disabling two-factor authentication for policy evaluation
+func (h *DisableTwoFactorHandler) PolicyResource(ctx context.Context, cmd DisableTwoFactorCommand) (entities.PolicyAttributes, error) {
+	return userPolicyAttributes(cmd.UserID), nil
+}
+
+// AuditTarget names the enrollment being removed
+func (h *DisableTwoFactorHandler) AuditTarget(cmd DisableTwoFactorCommand, res *DisableTwoFactorResult) entities.AuditTarget {
+	return entities.AuditTarget{Type: entities.AuditTargetTwoFactor, ID: cmd.UserID}
+}
+
+// Handle executes the disable two-factor command
+func (h *DisableTwoFactorHandler) Handle(ctx context.Context, cmd DisableTwoFactorCommand) (*DisableTwoFactorResult, error) {
+	// Load user
+	user, err := h.userRepo.GetByID(ctx, cmd.UserID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get user")
+	}
+	if user == nil {
+		return nil, errors.NewNotFoundError("user not found")
+	}
+
+	// Users under a 2FA policy cannot opt out
+	if user.TwoFactorRequired {
+		return nil, errors.WrapWithType(entities.ErrTwoFactorRequired, errors.ErrorTypeConflict, "two-factor disable failed")
+	}
+
+	// Load confirmed enrollment and require a valid second factor
+	twoFactor, err := loadConfirmedTwoFactor(ctx, h.twoFactorRepo, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := guardedVerifyTwoFactorCode(ctx, h.guard, h.totpService, twoFactor, "", cmd.Code, "two-factor disable failed"); err != nil {
+		return nil, err
+	}
+
+	// Delete enrollment
+	if err := h.twoFactorRepo.Delete(ctx, user.ID); err != nil {
+		return nil, errors.Wrap(err, "failed to delete two-factor enrollment")
+	}
+
+	// Return result
+	return &DisableTwoFactorResult{
+		UserID:   user.ID,
+		Disabled: true,
+	}, nil
+}
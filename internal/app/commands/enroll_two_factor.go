@@ -0,0 +1,118 @@
+package commands
+
+import (
+	"context"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/repositories"
+	"shadow-id/internal/domain/services"
+	"shadow-id/pkg/errors"
+	"shadow-id/pkg/types"
+)
+
+// EnrollTwoFactorCommand represents the command to start TOTP enrollment for a user
+type EnrollTwoFactorCommand struct {
+	UserID types.ID `json:"user_id" validate:"required"`
+}
+
+// EnrollTwoFactorResult represents the result of starting TOTP enrollment.
+// ProvisioningURI is the otpauth:// URI and QRPayload is the content the
+// frontend renders as a QR code for authenticator apps to scan.
+type EnrollTwoFactorResult struct {
+	UserID          types.ID `json:"user_id"`
+	Secret          string   `json:"secret"`
+	ProvisioningURI string   `json:"provisioning_uri"`
+	QRPayload       string   `json:"qr_payload"`
+	RecoveryCodes   []string `json:"recovery_codes"`
+}
+
+// EnrollTwoFactorHandler handles the enroll two-factor command
+type EnrollTwoFactorHandler struct {
+	userRepo      repositories.UserRepository
+	twoFactorRepo repositories.TwoFactorRepository
+	totpService   services.TOTPService
+}
+
+// NewEnrollTwoFactorHandler creates a new enroll two-factor handler
+func NewEnrollTwoFactorHandler(
+	userRepo repositories.UserRepository,
+	twoFactorRepo repositories.TwoFactorRepository,
+	totpService services.TOTPService,
+) *EnrollTwoFactorHandler {
+	return &EnrollTwoFactorHandler{
+		userRepo:      userRepo,
+		twoFactorRepo: twoFactorRepo,
+		totpService:   totpService,
+	}
+}
+
+// RequiredPermission returns the permission needed to execute the command
+func (h *EnrollTwoFactorHandler) RequiredPermission() entities.Permission {
+	return entities.PermissionTwoFactorManage
+}
+
+// PolicyResource describes the user enrolling in two-factor authentication for policy evaluation
+func (h *EnrollTwoFactorHandler) PolicyResource(ctx context.Context, cmd EnrollTwoFactorCommand) (entities.PolicyAttributes, error) {
+	return userPolicyAttributes(cmd.UserID), nil
+}
+
+// AuditTarget names the enrollment being started
+func (h *EnrollTwoFactorHandler) AuditTarget(cmd EnrollTwoFactorCommand, res *EnrollTwoFactorResult) entities.AuditTarget {
+	return entities.AuditTarget{Type: entities.AuditTargetTwoFactor, ID: cmd.UserID}
+}
+
+// Handle executes the enroll two-factor command
+func (h *EnrollTwoFactorHandler) Handle(ctx context.Context, cmd EnrollTwoFactorCommand) (*EnrollTwoFactorResult, error) {
+	// Load user
+	user, err := h.userRepo.GetByID(ctx, cmd.UserID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get user")
+	}
+	if user == nil {
+		return nil, errors.NewNotFoundError("user not found")
+	}
+
+	// Reject enrollment when a confirmed one already exists
+	existing, err := h.twoFactorRepo.GetByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get two-factor enrollment")
+	}
+	if existing != nil && existing.Confirmed {
+		return nil, errors.WrapWithType(entities.ErrTwoFactorAlreadyEnabled, errors.ErrorTypeConflict, "two-factor enrollment failed")
+	}
+
+	// Generate secret and recovery codes
+	secret, err := h.totpService.GenerateSecret()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate secret")
+	}
+	recoveryCodes, err := h.totpService.GenerateRecoveryCodes()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate recovery codes")
+	}
+
+	// Save pending enrollment
+	twoFactor := entities.NewTwoFactor(user.ID, secret, hashRecoveryCodes(h.totpService, recoveryCodes))
+	if err := h.twoFactorRepo.Create(ctx, twoFactor); err != nil {
+		return nil, errors.Wrap(err, "failed to save two-factor enrollment")
+	}
+
+	// Return result
+	uri := h.totpService.ProvisioningURI(secret, user.Email)
+	return &EnrollTwoFactorResult{
+		UserID:          user.ID,
+		Secret:          secret,
+		ProvisioningURI: uri,
+		QRPayload:       uri,
+		RecoveryCodes:   recoveryCodes,
+	}, nil
+}
+
+// hashRecoveryCodes hashes plaintext recovery codes for storage
+func hashRecoveryCodes(totpService services.TOTPService, codes []string) []string {
+	hashes := make([]string, len(codes))
+	for i, code := range codes {
+		hashes[i] = totpService.HashRecoveryCode(code)
+	}
+	return hashes
+}
@@ -0,0 +1,245 @@
+package commands
+
+import (
+	"context"
+	"encoding/base64"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/repositories"
+	"shadow-id/internal/domain/services"
+	"shadow-id/pkg/errors"
+	"shadow-id/pkg/types"
+)
+
+// LoginMethodPasskey identifies a sign-in completed with a passkey alone
+const LoginMethodPasskey = "passkey"
+
+// FinishPasskeyLoginCommand represents the authenticator response to an
+// authentication ceremony; binary values are base64url encoded
+type FinishPasskeyLoginCommand struct {
+	SessionID         types.ID `json:"session_id" validate:"required"`
+	CredentialID      string   `json:"credential_id" validate:"required"`
+	ClientDataJSON    string   `json:"client_data_json" validate:"required"`
+	AuthenticatorData string   `json:"authenticator_data" validate:"required"`
+	Signature         string   `json:"signature" validate:"required"`
+	UserHandle        string   `json:"user_handle"`
+}
+
+// FinishPasskeyLoginResult represents the result of a passkey login
+type FinishPasskeyLoginResult struct {
+	UserID       types.ID `json:"user_id"`
+	DeviceID     types.ID `json:"device_id,omitempty"`
+	CredentialID string   `json:"credential_id"`
+	SignCount    uint32   `json:"sign_count"`
+	UserVerified bool     `json:"user_verified"`
+
+	// SecondFactorRequired reports that the user must still pass two-factor
+	// verification before the login is complete
+	SecondFactorRequired bool `json:"second_factor_required"`
+
+	// CloneDetected reports that the login was rejected because the
+	// authenticator's signature counter did not advance, which suggests it was
+	// cloned. Nobody is signed in; the credential stays flagged.
+	CloneDetected bool `json:"clone_detected,omitempty"`
+}
+
+// FinishPasskeyLoginHandler handles the finish passkey login command
+type FinishPasskeyLoginHandler struct {
+	userRepo        repositories.UserRepository
+	twoFactorRepo   repositories.TwoFactorRepository
+	credentialRepo  repositories.WebAuthnCredentialRepository
+	deviceRepo      repositories.DeviceRepository
+	sessionRepo     repositories.WebAuthnSessionRepository
+	webauthnService services.WebAuthnService
+	guard           services.AuthenticationGuard
+}
+
+// NewFinishPasskeyLoginHandler creates a new finish passkey login handler
+func NewFinishPasskeyLoginHandler(
+	userRepo repositories.UserRepository,
+	twoFactorRepo repositories.TwoFactorRepository,
+	credentialRepo repositories.WebAuthnCredentialRepository,
+	deviceRepo repositories.DeviceRepository,
+	sessionRepo repositories.WebAuthnSessionRepository,
+	webauthnService services.WebAuthnService,
+	guard services.AuthenticationGuard,
+) *FinishPasskeyLoginHandler {
+	return &FinishPasskeyLoginHandler{
+		userRepo:        userRepo,
+		twoFactorRepo:   twoFactorRepo,
+		credentialRepo:  credentialRepo,
+		deviceRepo:      deviceRepo,
+		sessionRepo:     sessionRepo,
+		webauthnService: webauthnService,
+		guard:           guard,
+	}
+}
+
+// RequiredPermission returns the permission needed to execute the command
+func (h *FinishPasskeyLoginHandler) RequiredPermission() entities.Permission {
+	return entities.PermissionNone
+}
+
+// AuditTarget names the user the command acted on
+func (h *FinishPasskeyLoginHandler) AuditTarget(cmd FinishPasskeyLoginCommand, res *FinishPasskeyLoginResult) entities.AuditTarget {
+	// The user is only known once the assertion was verified
+	if res == nil {
+		return entities.AuditTarget{Type: entities.AuditTargetUser}
+	}
+	return entities.AuditTarget{Type: entities.AuditTargetUser, ID: res.UserID}
+}
+
+// Handle executes the finish passkey login command
+func (h *FinishPasskeyLoginHandler) Handle(ctx context.Context, cmd FinishPasskeyLoginCommand) (*FinishPasskeyLoginResult, error) {
+	// Consume ceremony session
+	session, err := takeWebAuthnSession(ctx, h.sessionRepo, cmd.SessionID, entities.CeremonyAuthentication)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode authenticator response
+	response, credentialID, userHandle, err := decodeAssertion(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	// Resolve credential and check it belongs to the expected user
+	credential, err := h.credentialRepo.GetByCredentialID(ctx, credentialID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get credential")
+	}
+	if credential == nil {
+		return nil, errors.WrapWithType(entities.ErrCredentialNotFound, errors.ErrorTypeNotFound, "passkey login failed")
+	}
+	if !session.UserID.IsEmpty() && credential.UserID != session.UserID {
+		return nil, errors.WrapWithType(entities.ErrCredentialNotFound, errors.ErrorTypeValidation, "passkey login failed")
+	}
+	if len(userHandle) > 0 && types.ID(userHandle) != credential.UserID {
+		return nil, errors.NewValidationError("user handle does not match credential")
+	}
+
+	// Reject attempts while the account or device is throttled
+	if err := h.guard.Check(ctx, credential.UserID, credential.DeviceID); err != nil {
+		return nil, err
+	}
+
+	// Passkeys bound to a suspended or revoked device cannot sign in
+	if !credential.DeviceID.IsEmpty() {
+		device, err := h.deviceRepo.GetByID(ctx, credential.DeviceID)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get device")
+		}
+		if device != nil && !device.CanAuthenticate() {
+			return nil, errors.WrapWithType(entities.ErrDeviceNotTrusted, errors.ErrorTypeForbidden, "passkey login failed")
+		}
+	}
+
+	// Verify assertion signature
+	assertion, err := h.webauthnService.VerifyAssertion(session.Challenge, credential.PublicKey, response)
+	if err != nil {
+		if recordErr := h.guard.RecordFailure(ctx, credential.UserID, credential.DeviceID); recordErr != nil {
+			return nil, errors.Wrap(recordErr, "failed to record failed attempt")
+		}
+		return nil, errors.WrapWithType(err, errors.ErrorTypeValidation, "passkey login failed")
+	}
+
+	// Apply clone detection. The rejection is a result rather than an error so
+	// the command commits and the flag is kept.
+	if err := credential.RecordAssertion(assertion.SignCount); err != nil {
+		if updateErr := h.credentialRepo.Update(ctx, credential); updateErr != nil {
+			return nil, errors.Wrap(updateErr, "failed to save credential")
+		}
+		if recordErr := h.guard.RecordFailure(ctx, credential.UserID, credential.DeviceID); recordErr != nil {
+			return nil, errors.Wrap(recordErr, "failed to record failed attempt")
+		}
+		return &FinishPasskeyLoginResult{
+			UserID:        credential.UserID,
+			DeviceID:      credential.DeviceID,
+			CredentialID:  cmd.CredentialID,
+			SignCount:     credential.SignCount,
+			UserVerified:  assertion.UserVerified,
+			CloneDetected: true,
+		}, nil
+	}
+	if err := h.credentialRepo.Update(ctx, credential); err != nil {
+		return nil, errors.Wrap(err, "failed to save credential")
+	}
+	if err := h.guard.RecordSuccess(ctx, credential.UserID, credential.DeviceID); err != nil {
+		return nil, errors.Wrap(err, "failed to clear failed attempts")
+	}
+
+	// Determine whether a second factor is still owed
+	user, err := h.userRepo.GetByID(ctx, credential.UserID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get user")
+	}
+	if user == nil {
+		return nil, errors.NewNotFoundError("user not found")
+	}
+	secondFactorRequired, err := h.secondFactorRequired(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	// Record the sign-in unless it still has to be completed with a second factor
+	if !secondFactorRequired {
+		user.RecordLogin(LoginMethodPasskey, credential.DeviceID)
+		if err := h.userRepo.Update(ctx, user); err != nil {
+			return nil, errors.Wrap(err, "failed to record login")
+		}
+	}
+
+	// Return result
+	return &FinishPasskeyLoginResult{
+		UserID:               credential.UserID,
+		DeviceID:             credential.DeviceID,
+		CredentialID:         cmd.CredentialID,
+		SignCount:            credential.SignCount,
+		UserVerified:         assertion.UserVerified,
+		SecondFactorRequired: secondFactorRequired,
+	}, nil
+}
+
+// secondFactorRequired checks if the user is required to or has chosen to use two-factor
+func (h *FinishPasskeyLoginHandler) secondFactorRequired(ctx context.Context, user *entities.User) (bool, error) {
+	if user.TwoFactorRequired {
+		return true, nil
+	}
+
+	twoFactor, err := h.twoFactorRepo.GetByUserID(ctx, user.ID)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get two-factor enrollment")
+	}
+	return twoFactor != nil && twoFactor.Confirmed, nil
+}
+
+// decodeAssertion decodes the base64url fields of an assertion response
+func decodeAssertion(cmd FinishPasskeyLoginCommand) (services.WebAuthnAssertionResponse, []byte, []byte, error) {
+	var response services.WebAuthnAssertionResponse
+	fields := []struct {
+		name  string
+		value string
+		dest  *[]byte
+	}{
+		{"client data", cmd.ClientDataJSON, &response.ClientDataJSON},
+		{"authenticator data", cmd.AuthenticatorData, &response.AuthenticatorData},
+		{"signature", cmd.Signature, &response.Signature},
+	}
+	for _, field := range fields {
+		decoded, err := base64.RawURLEncoding.DecodeString(field.value)
+		if err != nil {
+			return response, nil, nil, errors.NewValidationError(field.name + " is not valid base64url")
+		}
+		*field.dest = decoded
+	}
+
+	credentialID, err := base64.RawURLEncoding.DecodeString(cmd.CredentialID)
+	if err != nil {
+		return response, nil, nil, errors.NewValidationError("credential ID is not valid base64url")
+	}
+	userHandle, err := base64.RawURLEncoding.DecodeString(cmd.UserHandle)
+	if err != nil {
+		return response, nil, nil, errors.NewValidationError("user handle is not valid base64url")
+	}
+	return response, credentialID, userHandle, nil
+}
@@ -0,0 +1,153 @@
+package commands
+
+import (
+	"context"
+	"encoding/base64"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/repositories"
+	"shadow-id/internal/domain/services"
+	"shadow-id/pkg/errors"
+	"shadow-id/pkg/types"
+)
+
+// FinishPasskeyRegistrationCommand represents the authenticator response to a
+// registration ceremony; binary values are base64url encoded
+type FinishPasskeyRegistrationCommand struct {
+	SessionID         types.ID `json:"session_id" validate:"required"`
+	Name              string   `json:"name" validate:"max=100"`
+	ClientDataJSON    string   `json:"client_data_json" validate:"required"`
+	AttestationObject string   `json:"attestation_object" validate:"required"`
+}
+
+// FinishPasskeyRegistrationResult represents the result of registering a passkey
+type FinishPasskeyRegistrationResult struct {
+	ID                types.ID `json:"id"`
+	UserID            types.ID `json:"user_id"`
+	DeviceID          types.ID `json:"device_id,omitempty"`
+	CredentialID      string   `json:"credential_id"`
+	AttestationFormat string   `json:"attestation_format"`
+	CreatedAt         string   `json:"created_at"`
+}
+
+// FinishPasskeyRegistrationHandler handles the finish passkey registration command
+type FinishPasskeyRegistrationHandler struct {
+	credentialRepo  repositories.WebAuthnCredentialRepository
+	sessionRepo     repositories.WebAuthnSessionRepository
+	webauthnService services.WebAuthnService
+}
+
+// NewFinishPasskeyRegistrationHandler creates a new finish passkey registration handler
+func NewFinishPasskeyRegistrationHandler(
+	credentialRepo repositories.WebAuthnCredentialRepository,
+	sessionRepo repositories.WebAuthnSessionRepository,
+	webauthnService services.WebAuthnService,
+) *FinishPasskeyRegistrationHandler {
+	return &FinishPasskeyRegistrationHandler{
+		credentialRepo:  credentialRepo,
+		sessionRepo:     sessionRepo,
+		webauthnService: webauthnService,
+	}
+}
+
+// RequiredPermission returns the permission needed to execute the command
+func (h *FinishPasskeyRegistrationHandler) RequiredPermission() entities.Permission {
+	return entities.PermissionPasskeysManage
+}
+
+// PolicyResource describes the user the ceremony registers a passkey for, for
+// policy evaluation. The session is only consumed once the command runs.
+func (h *FinishPasskeyRegistrationHandler) PolicyResource(ctx context.Context, cmd FinishPasskeyRegistrationCommand) (entities.PolicyAttributes, error) {
+	session, err := h.sessionRepo.GetByID(ctx, cmd.SessionID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get webauthn session")
+	}
+	if session == nil || session.Ceremony != entities.CeremonyRegistration {
+		return nil, errors.WrapWithType(entities.ErrWebAuthnSessionNotFound, errors.ErrorTypeNotFound, "webauthn ceremony failed")
+	}
+	return userPolicyAttributes(session.UserID), nil
+}
+
+// AuditTarget names the credential the command acted on
+func (h *FinishPasskeyRegistrationHandler) AuditTarget(cmd FinishPasskeyRegistrationCommand, res *FinishPasskeyRegistrationResult) entities.AuditTarget {
+	// The credential only has an ID once it was stored
+	if res == nil {
+		return entities.AuditTarget{Type: entities.AuditTargetCredential}
+	}
+	return entities.AuditTarget{Type: entities.AuditTargetCredential, ID: res.ID}
+}
+
+// Handle executes the finish passkey registration command
+func (h *FinishPasskeyRegistrationHandler) Handle(ctx context.Context, cmd FinishPasskeyRegistrationCommand) (*FinishPasskeyRegistrationResult, error) {
+	// Consume ceremony session
+	session, err := takeWebAuthnSession(ctx, h.sessionRepo, cmd.SessionID, entities.CeremonyRegistration)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode authenticator response
+	clientDataJSON, err := base64.RawURLEncoding.DecodeString(cmd.ClientDataJSON)
+	if err != nil {
+		return nil, errors.NewValidationError("client data is not valid base64url")
+	}
+	attestationObject, err := base64.RawURLEncoding.DecodeString(cmd.AttestationObject)
+	if err != nil {
+		return nil, errors.NewValidationError("attestation object is not valid base64url")
+	}
+
+	// Verify attestation
+	attested, err := h.webauthnService.VerifyRegistration(session.Challenge, services.WebAuthnRegistrationResponse{
+		ClientDataJSON:    clientDataJSON,
+		AttestationObject: attestationObject,
+	})
+	if err != nil {
+		return nil, errors.WrapWithType(err, errors.ErrorTypeValidation, "passkey registration failed")
+	}
+
+	// Save credential
+	name := cmd.Name
+	if name == "" {
+		name = "Passkey"
+	}
+	credential := entities.NewWebAuthnCredential(
+		session.UserID,
+		session.DeviceID,
+		name,
+		attested.CredentialID,
+		attested.PublicKey,
+		attested.AAGUID,
+		attested.AttestationFormat,
+		attested.SignCount,
+	)
+	if err := h.credentialRepo.Create(ctx, credential); err != nil {
+		if err == entities.ErrCredentialAlreadyExists {
+			return nil, errors.WrapWithType(err, errors.ErrorTypeConflict, "passkey registration failed")
+		}
+		return nil, errors.Wrap(err, "failed to save credential")
+	}
+
+	// Return result
+	return &FinishPasskeyRegistrationResult{
+		ID:                credential.ID,
+		UserID:            credential.UserID,
+		DeviceID:          credential.DeviceID,
+		CredentialID:      base64.RawURLEncoding.EncodeToString(credential.CredentialID),
+		AttestationFormat: credential.AttestationFormat,
+		CreatedAt:         credential.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}, nil
+}
+
+// takeWebAuthnSession consumes a ceremony session and checks its type and expiry
+func takeWebAuthnSession(ctx context.Context, sessionRepo repositories.WebAuthnSessionRepository, id types.ID, ceremony string) (*entities.WebAuthnSession, error) {
+	session, err := sessionRepo.Take(ctx, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get webauthn session")
+	}
+	if session == nil || session.Ceremony != ceremony {
+		return nil, errors.WrapWithType(entities.ErrWebAuthnSessionNotFound, errors.ErrorTypeNotFound, "webauthn ceremony failed")
+	}
+	if session.IsExpired() {
+		return nil, errors.WrapWithType(entities.ErrWebAuthnSessionExpired, errors.ErrorTypeValidation, "webauthn ceremony failed")
+	}
+	return session, nil
+}
@@ -0,0 +1,116 @@
+package commands
+
+import (
+	"context"
+	"encoding/base64"
+	"time"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/repositories"
+	"shadow-id/internal/domain/services"
+	"shadow-id/pkg/errors"
+	"shadow-id/pkg/types"
+)
+
+// IssueDeviceChallengeCommand represents the command to issue a nonce a
+// device must sign to prove a request comes from it. Devices awaiting approval
+// can only prove themselves if AllowPending is set, for callers that accept a
+// device its owner registered but nobody approved yet.
+type IssueDeviceChallengeCommand struct {
+	DeviceID     types.ID `json:"device_id" validate:"required"`
+	AllowPending bool     `json:"allow_pending"`
+}
+
+// IssueDeviceChallengeResult represents an issued challenge. The device signs
+// entities.DeviceChallengeMessage of the challenge ID, device ID and decoded
+// nonce.
+type IssueDeviceChallengeResult struct {
+	ChallengeID types.ID `json:"challenge_id"`
+	DeviceID    types.ID `json:"device_id"`
+	Nonce       string   `json:"nonce"`
+	ExpiresAt   string   `json:"expires_at"`
+}
+
+// IssueDeviceChallengeHandler handles the issue device challenge command
+type IssueDeviceChallengeHandler struct {
+	deviceRepo    repositories.DeviceRepository
+	challengeRepo repositories.DeviceChallengeRepository
+	keyService    services.DeviceKeyService
+	ttl           time.Duration
+}
+
+// NewIssueDeviceChallengeHandler creates a new issue device challenge handler
+func NewIssueDeviceChallengeHandler(
+	deviceRepo repositories.DeviceRepository,
+	challengeRepo repositories.DeviceChallengeRepository,
+	keyService services.DeviceKeyService,
+	ttl time.Duration,
+) *IssueDeviceChallengeHandler {
+	return &IssueDeviceChallengeHandler{
+		deviceRepo:    deviceRepo,
+		challengeRepo: challengeRepo,
+		keyService:    keyService,
+		ttl:           ttl,
+	}
+}
+
+// RequiredPermission returns the permission needed to execute the command.
+// Devices prove themselves before anyone is signed in.
+func (h *IssueDeviceChallengeHandler) RequiredPermission() entities.Permission {
+	return entities.PermissionNone
+}
+
+// AuditTarget names the challenged device
+func (h *IssueDeviceChallengeHandler) AuditTarget(cmd IssueDeviceChallengeCommand, res *IssueDeviceChallengeResult) entities.AuditTarget {
+	return entities.AuditTarget{Type: entities.AuditTargetDevice, ID: cmd.DeviceID}
+}
+
+// Handle executes the issue device challenge command
+func (h *IssueDeviceChallengeHandler) Handle(ctx context.Context, cmd IssueDeviceChallengeCommand) (*IssueDeviceChallengeResult, error) {
+	// Load device
+	device, err := loadProvingDevice(ctx, h.deviceRepo, cmd.DeviceID, cmd.AllowPending)
+	if err != nil {
+		return nil, err
+	}
+
+	// Issue nonce
+	nonce, err := h.keyService.NewNonce()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate nonce")
+	}
+	challenge := entities.NewDeviceChallenge(device.ID, nonce, h.ttl, cmd.AllowPending)
+	if err := h.challengeRepo.Create(ctx, challenge); err != nil {
+		return nil, errors.Wrap(err, "failed to save device challenge")
+	}
+
+	// Return result
+	return &IssueDeviceChallengeResult{
+		ChallengeID: challenge.ID,
+		DeviceID:    device.ID,
+		Nonce:       base64.RawURLEncoding.EncodeToString(nonce),
+		ExpiresAt:   challenge.ExpiresAt.Format("2006-01-02T15:04:05Z07:00"),
+	}, nil
+}
+
+// loadProvingDevice loads a device that is about to prove itself. Devices
+// without a key, suspended devices and revoked devices cannot, nor can devices
+// awaiting approval unless allowPending is set.
+func loadProvingDevice(ctx context.Context, deviceRepo repositories.DeviceRepository, deviceID types.ID, allowPending bool) (*entities.Device, error) {
+	device, err := deviceRepo.GetByID(ctx, deviceID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get device")
+	}
+	if device == nil {
+		return nil, errors.WrapWithType(entities.ErrDeviceNotFound, errors.ErrorTypeNotFound, "device challenge failed")
+	}
+	if !device.CanAuthenticate() {
+		return nil, errors.WrapWithType(entities.ErrDeviceNotTrusted, errors.ErrorTypeForbidden, "device challenge failed")
+	}
+	if device.TrustState == entities.DeviceTrustPending && !allowPending {
+		return nil, errors.WrapWithType(entities.ErrDevicePending, errors.ErrorTypeForbidden, "device challenge failed")
+	}
+	if len(device.PublicKey) == 0 {
+		return nil, errors.WrapWithType(entities.ErrDeviceKeyMissing, errors.ErrorTypeValidation, "device challenge failed")
+	}
+	return device, nil
+}
@@ -0,0 +1,77 @@
+package commands
+
+import (
+	"context"
+	"time"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/repositories"
+	"shadow-id/pkg/errors"
+	"shadow-id/pkg/types"
+)
+
+// defaultStaleBatchSize is the number of devices marked stale when a command does not set a limit
+const defaultStaleBatchSize = 100
+
+// MarkStaleDevicesCommand represents the command to mark devices stale that
+// have not sent a heartbeat within the stale interval. Limit caps the number
+// of devices marked at once.
+type MarkStaleDevicesCommand struct {
+	Limit int `json:"limit" validate:"min=0,max=1000"`
+}
+
+// MarkStaleDevicesResult represents the result of marking devices stale
+type MarkStaleDevicesResult struct {
+	Marked    int        `json:"marked"`
+	DeviceIDs []types.ID `json:"device_ids"`
+}
+
+// MarkStaleDevicesHandler handles the mark stale devices command. Devices
+// that never sent a heartbeat count as seen when they were registered.
+type MarkStaleDevicesHandler struct {
+	deviceRepo repositories.DeviceRepository
+	staleAfter time.Duration
+}
+
+// NewMarkStaleDevicesHandler creates a new mark stale devices handler
+func NewMarkStaleDevicesHandler(deviceRepo repositories.DeviceRepository, staleAfter time.Duration) *MarkStaleDevicesHandler {
+	return &MarkStaleDevicesHandler{
+		deviceRepo: deviceRepo,
+		staleAfter: staleAfter,
+	}
+}
+
+// RequiredPermission returns the permission needed to execute the command
+func (h *MarkStaleDevicesHandler) RequiredPermission() entities.Permission {
+	return entities.PermissionDevicesSweep
+}
+
+// Handle executes the mark stale devices command
+func (h *MarkStaleDevicesHandler) Handle(ctx context.Context, cmd MarkStaleDevicesCommand) (*MarkStaleDevicesResult, error) {
+	limit := cmd.Limit
+	if limit <= 0 {
+		limit = defaultStaleBatchSize
+	}
+
+	// Find devices unseen for longer than the stale interval
+	devices, err := h.deviceRepo.ListUnseenSince(ctx, time.Now().Add(-h.staleAfter), limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list unseen devices")
+	}
+
+	// Mark them stale
+	deviceIDs := make([]types.ID, 0, len(devices))
+	for _, device := range devices {
+		device.MarkStale()
+		if err := h.deviceRepo.Update(ctx, device); err != nil {
+			return nil, errors.Wrap(err, "failed to update device").WithDetail("device_id", device.ID.String())
+		}
+		deviceIDs = append(deviceIDs, device.ID)
+	}
+
+	// Return result
+	return &MarkStaleDevicesResult{
+		Marked:    len(deviceIDs),
+		DeviceIDs: deviceIDs,
+	}, nil
+}
@@ -0,0 +1,129 @@
+package commands
+
+import (
+	"context"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/repositories"
+	"shadow-id/pkg/errors"
+	"shadow-id/pkg/types"
+)
+
+// MatchDeviceCommand represents the command to recognize a presented
+// fingerprint among a user's devices. Fingerprint maps component names to
+// their salted hashes, as returned by GetCurrentDevice.
+type MatchDeviceCommand struct {
+	UserID      types.ID          `json:"user_id" validate:"required"`
+	Fingerprint map[string]string `json:"fingerprint" validate:"required,max=20"`
+}
+
+// MatchDeviceResult represents the best match among the user's devices.
+// DeviceID is empty when no device resembles the fingerprint.
+type MatchDeviceResult struct {
+	DeviceID           types.ID                    `json:"device_id,omitempty"`
+	TrustState         string                      `json:"trust_state,omitempty"`
+	Verdict            entities.FingerprintVerdict `json:"verdict"`
+	Confidence         float64                     `json:"confidence"`
+	Changed            []string                    `json:"changed"`
+	FingerprintUpdated bool                        `json:"fingerprint_updated"`
+	Version            int64                       `json:"version,omitempty"`
+}
+
+// MatchDeviceHandler handles the match device command. A matching device
+// whose fingerprint drifted takes over the presented fingerprint, so gradual
+// changes keep being recognized; a suspicious resemblance is recorded on the
+// device but leaves its fingerprint alone.
+type MatchDeviceHandler struct {
+	userRepo   repositories.UserRepository
+	deviceRepo repositories.DeviceRepository
+	policy     entities.FingerprintMatchPolicy
+}
+
+// NewMatchDeviceHandler creates a new match device handler
+func NewMatchDeviceHandler(
+	userRepo repositories.UserRepository,
+	deviceRepo repositories.DeviceRepository,
+	policy entities.FingerprintMatchPolicy,
+) *MatchDeviceHandler {
+	return &MatchDeviceHandler{
+		userRepo:   userRepo,
+		deviceRepo: deviceRepo,
+		policy:     policy,
+	}
+}
+
+// RequiredPermission returns the permission needed to execute the command
+func (h *MatchDeviceHandler) RequiredPermission() entities.Permission {
+	return entities.PermissionDevicesRegister
+}
+
+// PolicyResource describes the user whose devices are matched for policy evaluation
+func (h *MatchDeviceHandler) PolicyResource(ctx context.Context, cmd MatchDeviceCommand) (entities.PolicyAttributes, error) {
+	return userPolicyAttributes(cmd.UserID), nil
+}
+
+// AuditTarget names the matched device, or the user if none matched
+func (h *MatchDeviceHandler) AuditTarget(cmd MatchDeviceCommand, res *MatchDeviceResult) entities.AuditTarget {
+	if res == nil || res.DeviceID.IsEmpty() {
+		return entities.AuditTarget{Type: entities.AuditTargetUser, ID: cmd.UserID}
+	}
+	return entities.AuditTarget{Type: entities.AuditTargetDevice, ID: res.DeviceID}
+}
+
+// Handle executes the match device command
+func (h *MatchDeviceHandler) Handle(ctx context.Context, cmd MatchDeviceCommand) (*MatchDeviceResult, error) {
+	// Load owner
+	user, err := h.userRepo.GetByID(ctx, cmd.UserID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get user")
+	}
+	if user == nil {
+		return nil, errors.NewNotFoundError("user not found")
+	}
+
+	// Score the fingerprint against every fingerprinted device that was not revoked
+	devices, err := h.deviceRepo.ListByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list devices")
+	}
+	var best *entities.Device
+	bestMatch := entities.FingerprintMatch{Changed: []string{}, Verdict: entities.FingerprintUnknown}
+	for _, device := range devices {
+		if len(device.Fingerprint) == 0 || device.IsRevoked() {
+			continue
+		}
+		match := h.policy.Match(device.Fingerprint, cmd.Fingerprint)
+		if match.Verdict != entities.FingerprintUnknown && (best == nil || match.Confidence > bestMatch.Confidence) {
+			best, bestMatch = device, match
+		}
+	}
+
+	result := &MatchDeviceResult{
+		Verdict:    bestMatch.Verdict,
+		Confidence: bestMatch.Confidence,
+		Changed:    bestMatch.Changed,
+	}
+	if best == nil {
+		return result, nil
+	}
+
+	// Follow drift on a match, flag a suspicious change otherwise
+	switch {
+	case bestMatch.Verdict == entities.FingerprintMatched && len(bestMatch.Changed) > 0:
+		best.UpdateFingerprint(cmd.Fingerprint, bestMatch)
+		result.FingerprintUpdated = true
+	case bestMatch.Verdict == entities.FingerprintSuspicious:
+		best.FlagSuspiciousFingerprint(bestMatch)
+	}
+	if best.PendingCount() > 0 {
+		if err := h.deviceRepo.Update(ctx, best); err != nil {
+			return nil, errors.Wrap(err, "failed to update device")
+		}
+	}
+
+	// Return result
+	result.DeviceID = best.ID
+	result.TrustState = string(best.TrustState)
+	result.Version = best.ProfileVersion
+	return result, nil
+}
@@ -0,0 +1,133 @@
+package commands
+
+import (
+	"context"
+	"time"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/repositories"
+	"shadow-id/pkg/errors"
+	"shadow-id/pkg/types"
+)
+
+// defaultPurgeBatchSize is the number of users purged when a command does not set a limit
+const defaultPurgeBatchSize = 100
+
+// PurgeDeletedUsersCommand represents the command to hard-delete users whose
+// retention period has passed. Limit caps the number of users purged at once.
+type PurgeDeletedUsersCommand struct {
+	Limit int `json:"limit" validate:"min=0,max=1000"`
+}
+
+// PurgeDeletedUsersResult represents the result of purging deleted users
+type PurgeDeletedUsersResult struct {
+	Purged  int        `json:"purged"`
+	UserIDs []types.ID `json:"user_ids"`
+}
+
+// PurgeDeletedUsersHandler handles the purge deleted users command. Users
+// soft-deleted longer ago than the retention period are removed together
+// with their devices, passkeys and two-factor enrollment.
+type PurgeDeletedUsersHandler struct {
+	userRepo       repositories.UserRepository
+	deviceRepo     repositories.DeviceRepository
+	credentialRepo repositories.WebAuthnCredentialRepository
+	twoFactorRepo  repositories.TwoFactorRepository
+	retention      time.Duration
+}
+
+// NewPurgeDeletedUsersHandler creates a new purge deleted users handler
+func NewPurgeDeletedUsersHandler(
+	userRepo repositories.UserRepository,
+	deviceRepo repositories.DeviceRepository,
+	credentialRepo repositories.WebAuthnCredentialRepository,
+	twoFactorRepo repositories.TwoFactorRepository,
+	retention time.Duration,
+) *PurgeDeletedUsersHandler {
+	return &PurgeDeletedUsersHandler{
+		userRepo:       userRepo,
+		deviceRepo:     deviceRepo,
+		credentialRepo: credentialRepo,
+		twoFactorRepo:  twoFactorRepo,
+		retention:      retention,
+	}
+}
+
+// RequiredPermission returns the permission needed to execute the command
+func (h *PurgeDeletedUsersHandler) RequiredPermission() entities.Permission {
+	return entities.PermissionUsersPurge
+}
+
+// Handle executes the purge deleted users command
+func (h *PurgeDeletedUsersHandler) Handle(ctx context.Context, cmd PurgeDeletedUsersCommand) (*PurgeDeletedUsersResult, error) {
+	limit := cmd.Limit
+	if limit <= 0 {
+		limit = defaultPurgeBatchSize
+	}
+
+	// Find users past their retention period
+	users, err := h.userRepo.ListDeleted(ctx, time.Now().Add(-h.retention), limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list deleted users")
+	}
+
+	// Purge them
+	userIDs := make([]types.ID, 0, len(users))
+	for _, user := range users {
+		if err := h.purge(ctx, user); err != nil {
+			return nil, errors.Wrap(err, "failed to purge user").WithDetail("user_id", user.ID.String())
+		}
+		userIDs = append(userIDs, user.ID)
+	}
+
+	// Return result
+	return &PurgeDeletedUsersResult{
+		Purged:  len(userIDs),
+		UserIDs: userIDs,
+	}, nil
+}
+
+// purge removes a user together with everything registered to it
+func (h *PurgeDeletedUsersHandler) purge(ctx context.Context, user *entities.User) error {
+	// Revoke the user's devices
+	devices, err := h.deviceRepo.ListByUserID(ctx, user.ID)
+	if err != nil {
+		return errors.Wrap(err, "failed to list devices")
+	}
+	for _, device := range devices {
+		if !device.IsRevoked() {
+			if err := device.Revoke(); err != nil {
+				return errors.Wrap(err, "failed to revoke device")
+			}
+		}
+		if err := h.deviceRepo.Delete(ctx, device); err != nil {
+			return errors.Wrap(err, "failed to delete device")
+		}
+	}
+
+	// Remove credentials and two-factor enrollment
+	credentials, err := h.credentialRepo.ListByUserID(ctx, user.ID)
+	if err != nil {
+		return errors.Wrap(err, "failed to list credentials")
+	}
+	for _, credential := range credentials {
+		if err := h.credentialRepo.Delete(ctx, credential.ID); err != nil {
+			return errors.Wrap(err, "failed to delete credential")
+		}
+	}
+	twoFactor, err := h.twoFactorRepo.GetByUserID(ctx, user.ID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get two-factor enrollment")
+	}
+	if twoFactor != nil {
+		if err := h.twoFactorRepo.Delete(ctx, user.ID); err != nil {
+			return errors.Wrap(err, "failed to delete two-factor enrollment")
+		}
+	}
+
+	// Purge user
+	if err := user.MarkPurged(); err != nil {
+		return err
+	}
+	return h.userRepo.Purge(ctx, user)
+}
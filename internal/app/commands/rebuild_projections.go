@@ -0,0 +1,66 @@
+package commands
+
+import (
+	"context"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/services"
+	"shadow-id/pkg/errors"
+)
+
+// RebuildProjectionsCommand represents the command to regenerate read models
+// from the event history. An empty name rebuilds every projection.
+type RebuildProjectionsCommand struct {
+	Name string `json:"name"`
+}
+
+// ProjectionRebuildResult represents the rebuild of a single projection
+type ProjectionRebuildResult struct {
+	Name       string `json:"name"`
+	Events     int    `json:"events"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// RebuildProjectionsResult represents the result of rebuilding projections
+type RebuildProjectionsResult struct {
+	Projections []ProjectionRebuildResult `json:"projections"`
+}
+
+// RebuildProjectionsHandler handles the rebuild projections command
+type RebuildProjectionsHandler struct {
+	rebuilder services.ProjectionRebuilder
+}
+
+// NewRebuildProjectionsHandler creates a new rebuild projections handler
+func NewRebuildProjectionsHandler(rebuilder services.ProjectionRebuilder) *RebuildProjectionsHandler {
+	return &RebuildProjectionsHandler{
+		rebuilder: rebuilder,
+	}
+}
+
+// RequiredPermission returns the permission needed to execute the command
+func (h *RebuildProjectionsHandler) RequiredPermission() entities.Permission {
+	return entities.PermissionProjectionsRebuild
+}
+
+// Handle executes the rebuild projections command
+func (h *RebuildProjectionsHandler) Handle(ctx context.Context, cmd RebuildProjectionsCommand) (*RebuildProjectionsResult, error) {
+	// Replay the history
+	rebuilds, err := h.rebuilder.Rebuild(ctx, cmd.Name)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to rebuild projections")
+	}
+
+	// Return result
+	results := make([]ProjectionRebuildResult, len(rebuilds))
+	for i, rebuild := range rebuilds {
+		results[i] = ProjectionRebuildResult{
+			Name:       rebuild.Name,
+			Events:     rebuild.Events,
+			DurationMs: rebuild.Duration.Milliseconds(),
+		}
+	}
+	return &RebuildProjectionsResult{
+		Projections: results,
+	}, nil
+}
@@ -0,0 +1,90 @@
+package commands
+
+import (
+	"context"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/repositories"
+	"shadow-id/pkg/errors"
+	"shadow-id/pkg/types"
+)
+
+// RecordDeviceHeartbeatCommand represents the command to record that a
+// device is alive, running an app version from an IP address. The HTTP API
+// replaces the address with the one the request came from.
+type RecordDeviceHeartbeatCommand struct {
+	DeviceID   types.ID `json:"device_id" validate:"required"`
+	AppVersion string   `json:"app_version" validate:"max=50"`
+	IPAddress  string   `json:"ip_address" validate:"omitempty,ip"`
+}
+
+// RecordDeviceHeartbeatResult represents the result of recording a heartbeat
+type RecordDeviceHeartbeatResult struct {
+	DeviceID   types.ID `json:"device_id"`
+	UserID     types.ID `json:"user_id"`
+	LastSeenAt string   `json:"last_seen_at"`
+	Version    int64    `json:"version"`
+
+	// Resumed reports whether the device had been marked stale
+	Resumed bool `json:"resumed"`
+}
+
+// RecordDeviceHeartbeatHandler handles the record device heartbeat command
+type RecordDeviceHeartbeatHandler struct {
+	deviceRepo repositories.DeviceRepository
+}
+
+// NewRecordDeviceHeartbeatHandler creates a new record device heartbeat handler
+func NewRecordDeviceHeartbeatHandler(deviceRepo repositories.DeviceRepository) *RecordDeviceHeartbeatHandler {
+	return &RecordDeviceHeartbeatHandler{
+		deviceRepo: deviceRepo,
+	}
+}
+
+// RequiredPermission returns the permission needed to execute the command
+func (h *RecordDeviceHeartbeatHandler) RequiredPermission() entities.Permission {
+	return entities.PermissionDevicesHeartbeat
+}
+
+// PolicyResource describes the device sending the heartbeat for policy evaluation
+func (h *RecordDeviceHeartbeatHandler) PolicyResource(ctx context.Context, cmd RecordDeviceHeartbeatCommand) (entities.PolicyAttributes, error) {
+	device, err := loadTrustDevice(ctx, h.deviceRepo, cmd.DeviceID, "device heartbeat failed")
+	if err != nil {
+		return nil, err
+	}
+	return devicePolicyAttributes(device), nil
+}
+
+// AuditTarget names the device sending the heartbeat
+func (h *RecordDeviceHeartbeatHandler) AuditTarget(cmd RecordDeviceHeartbeatCommand, res *RecordDeviceHeartbeatResult) entities.AuditTarget {
+	return entities.AuditTarget{Type: entities.AuditTargetDevice, ID: cmd.DeviceID}
+}
+
+// Handle executes the record device heartbeat command
+func (h *RecordDeviceHeartbeatHandler) Handle(ctx context.Context, cmd RecordDeviceHeartbeatCommand) (*RecordDeviceHeartbeatResult, error) {
+	// Load device
+	device, err := loadTrustDevice(ctx, h.deviceRepo, cmd.DeviceID, "device heartbeat failed")
+	if err != nil {
+		return nil, err
+	}
+
+	// Record heartbeat
+	resumed, err := device.Heartbeat(cmd.AppVersion, cmd.IPAddress)
+	if err != nil {
+		return nil, errors.WrapWithType(err, errors.ErrorTypeConflict, "device heartbeat failed")
+	}
+
+	// Save device
+	if err := h.deviceRepo.Update(ctx, device); err != nil {
+		return nil, errors.Wrap(err, "failed to update device")
+	}
+
+	// Return result
+	return &RecordDeviceHeartbeatResult{
+		DeviceID:   device.ID,
+		UserID:     device.UserID,
+		LastSeenAt: device.LastSeenAt.Format("2006-01-02T15:04:05Z07:00"),
+		Version:    device.ProfileVersion,
+		Resumed:    resumed,
+	}, nil
+}
@@ -0,0 +1,90 @@
+package commands
+
+import (
+	"context"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/repositories"
+	"shadow-id/internal/domain/services"
+	"shadow-id/pkg/errors"
+	"shadow-id/pkg/types"
+)
+
+// RegenerateRecoveryCodesCommand represents the command to replace a user's recovery codes
+type RegenerateRecoveryCodesCommand struct {
+	UserID types.ID `json:"user_id" validate:"required"`
+	Code   string   `json:"code" validate:"required"`
+}
+
+// RegenerateRecoveryCodesResult represents the result of regenerating recovery codes
+type RegenerateRecoveryCodesResult struct {
+	UserID        types.ID `json:"user_id"`
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// RegenerateRecoveryCodesHandler handles the regenerate recovery codes command
+type RegenerateRecoveryCodesHandler struct {
+	twoFactorRepo repositories.TwoFactorRepository
+	totpService   services.TOTPService
+	guard         services.AuthenticationGuard
+}
+
+// NewRegenerateRecoveryCodesHandler creates a new regenerate recovery codes handler
+func NewRegenerateRecoveryCodesHandler(
+	twoFactorRepo repositories.TwoFactorRepository,
+	totpService services.TOTPService,
+	guard services.AuthenticationGuard,
+) *RegenerateRecoveryCodesHandler {
+	return &RegenerateRecoveryCodesHandler{
+		twoFactorRepo: twoFactorRepo,
+		totpService:   totpService,
+		guard:         guard,
+	}
+}
+
+// RequiredPermission returns the permission needed to execute the command
+func (h *RegenerateRecoveryCodesHandler) RequiredPermission() entities.Permission {
+	return entities.PermissionTwoFactorManage
+}
+
+// PolicyResource describes the user whose recovery codes are regenerated for policy evaluation
+func (h *RegenerateRecoveryCodesHandler) PolicyResource(ctx context.Context, cmd RegenerateRecoveryCodesCommand) (entities.PolicyAttributes, error) {
+	return userPolicyAttributes(cmd.UserID), nil
+}
+
+// AuditTarget names the enrollment whose recovery codes are replaced
+func (h *RegenerateRecoveryCodesHandler) AuditTarget(cmd RegenerateRecoveryCodesCommand, res *RegenerateRecoveryCodesResult) entities.AuditTarget {
+	return entities.AuditTarget{Type: entities.AuditTargetTwoFactor, ID: cmd.UserID}
+}
+
+// Handle executes the regenerate recovery codes command
+func (h *RegenerateRecoveryCodesHandler) Handle(ctx context.Context, cmd RegenerateRecoveryCodesCommand) (*RegenerateRecoveryCodesResult, error) {
+	// Load confirmed enrollment
+	twoFactor, err := loadConfirmedTwoFactor(ctx, h.twoFactorRepo, cmd.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Require a valid second factor before issuing new codes
+	if _, err := guardedVerifyTwoFactorCode(ctx, h.guard, h.totpService, twoFactor, "", cmd.Code, "recovery code regeneration failed"); err != nil {
+		return nil, err
+	}
+
+	// Replace recovery codes
+	recoveryCodes, err := h.totpService.GenerateRecoveryCodes()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate recovery codes")
+	}
+	twoFactor.ReplaceRecoveryCodes(hashRecoveryCodes(h.totpService, recoveryCodes))
+
+	// Save enrollment
+	if err := h.twoFactorRepo.Update(ctx, twoFactor); err != nil {
+		return nil, errors.Wrap(err, "failed to save two-factor enrollment")
+	}
+
+	// Return result
+	return &RegenerateRecoveryCodesResult{
+		UserID:        twoFactor.UserID,
+		RecoveryCodes: recoveryCodes,
+	}, nil
+}
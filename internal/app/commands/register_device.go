@@ -0,0 +1,192 @@
+package commands
+
+import (
+	"context"
+	"encoding/base64"
+	"time"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/repositories"
+	"shadow-id/pkg/errors"
+	"shadow-id/pkg/types"
+)
+
+// RegisterDeviceCommand represents the command to register a device to a
+// user. The client generates the device's Ed25519 key pair and sends only the
+// base64url encoded public key; the private key never leaves the device. The
+// optional fingerprint lets MatchDevice recognize the device later.
+// Registration fails once the user reached their device quota unless the
+// quota policy evicts an inactive device.
+type RegisterDeviceCommand struct {
+	UserID      types.ID          `json:"user_id" validate:"required"`
+	Name        string            `json:"name" validate:"required,min=1,max=100"`
+	Platform    string            `json:"platform" validate:"max=50"`
+	PublicKey   string            `json:"public_key" validate:"required,max=100"`
+	Fingerprint map[string]string `json:"fingerprint" validate:"max=20"`
+}
+
+// RegisterDeviceResult represents the result of registering a device
+type RegisterDeviceResult struct {
+	ID        types.ID `json:"id"`
+	UserID    types.ID `json:"user_id"`
+	Name      string   `json:"name"`
+	Platform  string   `json:"platform"`
+	CreatedAt string   `json:"created_at"`
+	Version   int64    `json:"version"`
+	PublicKey string   `json:"public_key"`
+
+	// EvictedDeviceID is the inactive device revoked to make room for this
+	// one, if any
+	EvictedDeviceID types.ID `json:"evicted_device_id,omitempty"`
+}
+
+// RegisterDeviceHandler handles the register device command
+type RegisterDeviceHandler struct {
+	userRepo       repositories.UserRepository
+	deviceRepo     repositories.DeviceRepository
+	credentialRepo repositories.WebAuthnCredentialRepository
+	uow            repositories.UnitOfWork
+	quotaPolicy    entities.DeviceQuotaPolicy
+}
+
+// NewRegisterDeviceHandler creates a new register device handler
+func NewRegisterDeviceHandler(
+	userRepo repositories.UserRepository,
+	deviceRepo repositories.DeviceRepository,
+	credentialRepo repositories.WebAuthnCredentialRepository,
+	uow repositories.UnitOfWork,
+	quotaPolicy entities.DeviceQuotaPolicy,
+) *RegisterDeviceHandler {
+	return &RegisterDeviceHandler{
+		userRepo:       userRepo,
+		deviceRepo:     deviceRepo,
+		credentialRepo: credentialRepo,
+		uow:            uow,
+		quotaPolicy:    quotaPolicy,
+	}
+}
+
+// RequiredPermission returns the permission needed to execute the command
+func (h *RegisterDeviceHandler) RequiredPermission() entities.Permission {
+	return entities.PermissionDevicesRegister
+}
+
+// PolicyResource describes the user the device is registered to for policy
+// evaluation. Registering can evict the user's devices and binds a key that
+// can prove the device, so it is checked before the quota is applied.
+func (h *RegisterDeviceHandler) PolicyResource(ctx context.Context, cmd RegisterDeviceCommand) (entities.PolicyAttributes, error) {
+	return userPolicyAttributes(cmd.UserID), nil
+}
+
+// AuditTarget names the device the command acted on
+func (h *RegisterDeviceHandler) AuditTarget(cmd RegisterDeviceCommand, res *RegisterDeviceResult) entities.AuditTarget {
+	// The device only has an ID once it was registered
+	if res == nil {
+		return entities.AuditTarget{Type: entities.AuditTargetDevice}
+	}
+	return entities.AuditTarget{Type: entities.AuditTargetDevice, ID: res.ID}
+}
+
+// Handle executes the register device command
+func (h *RegisterDeviceHandler) Handle(ctx context.Context, cmd RegisterDeviceCommand) (*RegisterDeviceResult, error) {
+	// Load owner
+	user, err := h.userRepo.GetByID(ctx, cmd.UserID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get user")
+	}
+	if user == nil {
+		return nil, errors.NewNotFoundError("user not found")
+	}
+
+	// Create device entity
+	device := entities.NewDevice(user.ID, cmd.Name, cmd.Platform)
+	if len(cmd.Fingerprint) > 0 {
+		device.AttachFingerprint(cmd.Fingerprint)
+	}
+
+	// Validate device
+	if err := device.Validate(); err != nil {
+		return nil, errors.WrapWithType(err, errors.ErrorTypeValidation, "invalid device data")
+	}
+	if err := attachDeviceKey(device, cmd.PublicKey); err != nil {
+		return nil, err
+	}
+
+	// Enforce the device quota and save the device together
+	var evicted *entities.Device
+	err = h.uow.WithinTx(ctx, func(ctx context.Context) error {
+		devices, err := h.deviceRepo.ListByUserID(ctx, user.ID)
+		if err != nil {
+			return errors.Wrap(err, "failed to list devices")
+		}
+		if evicted, err = h.makeRoom(ctx, user, devices); err != nil {
+			return err
+		}
+
+		if err := h.deviceRepo.Create(ctx, device); err != nil {
+			return errors.Wrap(err, "failed to register device")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Return result
+	result := &RegisterDeviceResult{
+		ID:        device.ID,
+		UserID:    device.UserID,
+		Name:      device.Name,
+		Platform:  device.Platform,
+		CreatedAt: device.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		Version:   device.ProfileVersion,
+		PublicKey: base64.RawURLEncoding.EncodeToString(device.PublicKey),
+	}
+	if evicted != nil {
+		result.EvictedDeviceID = evicted.ID
+	}
+	return result, nil
+}
+
+// makeRoom checks that the user has room for another device. If the quota
+// was reached, the oldest inactive device is revoked if the policy allows it
+// and returned.
+func (h *RegisterDeviceHandler) makeRoom(ctx context.Context, user *entities.User, devices []*entities.Device) (*entities.Device, error) {
+	quota := h.quotaPolicy.Usage(user, devices)
+	if !quota.Reached() {
+		return nil, nil
+	}
+
+	// Evicting one device only makes room if the user is exactly at the limit
+	candidate := h.quotaPolicy.EvictionCandidate(devices, time.Now())
+	if candidate == nil || quota.Used > quota.Limit {
+		return nil, errors.WrapWithType(quota.Err(), errors.ErrorTypeConflict, "device registration failed").
+			WithDetail("limit", quota.Limit).
+			WithDetail("used", quota.Used)
+	}
+
+	// Revoke the evicted device and its passkeys
+	if err := candidate.Revoke(); err != nil {
+		return nil, errors.WrapWithType(err, errors.ErrorTypeConflict, "device eviction failed")
+	}
+	if _, err := deleteDeviceCredentials(ctx, h.credentialRepo, candidate); err != nil {
+		return nil, err
+	}
+	if err := h.deviceRepo.Update(ctx, candidate); err != nil {
+		return nil, errors.Wrap(err, "failed to update device")
+	}
+	return candidate, nil
+}
+
+// attachDeviceKey decodes the base64url encoded public key the client
+// generated for a device being registered and attaches it to the device
+func attachDeviceKey(device *entities.Device, publicKey string) error {
+	key, err := base64.RawURLEncoding.DecodeString(publicKey)
+	if err != nil {
+		return errors.WrapWithType(entities.ErrInvalidDeviceKey, errors.ErrorTypeValidation, "invalid device data")
+	}
+	if err := device.AttachPublicKey(key); err != nil {
+		return errors.WrapWithType(err, errors.ErrorTypeValidation, "invalid device data")
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package commands
+
+import (
+	"context"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/repositories"
+	"shadow-id/pkg/errors"
+	"shadow-id/pkg/types"
+)
+
+// RestoreUserCommand represents the command to bring back a soft-deleted
+// user. A non-zero version must match the user's current version.
+type RestoreUserCommand struct {
+	ID      types.ID `json:"id" validate:"required"`
+	Version int64    `json:"version" validate:"min=0"`
+}
+
+// RestoreUserResult represents the result of restoring a user
+type RestoreUserResult struct {
+	ID         types.ID `json:"id"`
+	Name       string   `json:"name"`
+	Email      string   `json:"email"`
+	RestoredAt string   `json:"restored_at"`
+	Version    int64    `json:"version"`
+}
+
+// RestoreUserHandler handles the restore user command
+type RestoreUserHandler struct {
+	userRepo repositories.UserRepository
+}
+
+// NewRestoreUserHandler creates a new restore user handler
+func NewRestoreUserHandler(userRepo repositories.UserRepository) *RestoreUserHandler {
+	return &RestoreUserHandler{
+		userRepo: userRepo,
+	}
+}
+
+// RequiredPermission returns the permission needed to execute the command
+func (h *RestoreUserHandler) RequiredPermission() entities.Permission {
+	return entities.PermissionUsersRestore
+}
+
+// AuditTarget names the user being restored
+func (h *RestoreUserHandler) AuditTarget(cmd RestoreUserCommand, res *RestoreUserResult) entities.AuditTarget {
+	return entities.AuditTarget{Type: entities.AuditTargetUser, ID: cmd.ID}
+}
+
+// Handle executes the restore user command
+func (h *RestoreUserHandler) Handle(ctx context.Context, cmd RestoreUserCommand) (*RestoreUserResult, error) {
+	// Load user
+	user, err := h.userRepo.GetDeleted(ctx, cmd.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get user")
+	}
+	if user == nil {
+		return nil, errors.NewNotFoundError("deleted user not found")
+	}
+	if err := checkExpectedVersion(cmd.Version, user.ProfileVersion); err != nil {
+		return nil, err
+	}
+
+	// The email may have been taken while the user was deleted
+	existing, err := h.userRepo.GetByEmail(ctx, user.Email)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to check email uniqueness")
+	}
+	if existing != nil {
+		return nil, entities.ErrUserAlreadyExists
+	}
+
+	// Restore user
+	if err := user.Restore(); err != nil {
+		return nil, errors.WrapWithType(err, errors.ErrorTypeConflict, "user restoration failed")
+	}
+	if err := h.userRepo.Update(ctx, user); err != nil {
+		return nil, errors.Wrap(err, "failed to update user")
+	}
+
+	// Return result
+	return &RestoreUserResult{
+		ID:         user.ID,
+		Name:       user.Name,
+		Email:      user.Email,
+		RestoredAt: user.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		Version:    user.ProfileVersion,
+	}, nil
+}
@@ -0,0 +1,127 @@
+package commands
+
+import (
+	"context"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/repositories"
+	"shadow-id/pkg/errors"
+	"shadow-id/pkg/types"
+)
+
+// RevokeDeviceCommand represents the command to revoke a device. A non-zero
+// version must match the device's profile version.
+type RevokeDeviceCommand struct {
+	DeviceID types.ID `json:"device_id" validate:"required"`
+	Version  int64    `json:"version" validate:"min=0"`
+}
+
+// RevokeDeviceResult represents the result of revoking a device
+type RevokeDeviceResult struct {
+	DeviceID           types.ID `json:"device_id"`
+	UserID             types.ID `json:"user_id"`
+	RevokedCredentials int      `json:"revoked_credentials"`
+}
+
+// RevokeDeviceHandler handles the revoke device command
+type RevokeDeviceHandler struct {
+	deviceRepo     repositories.DeviceRepository
+	credentialRepo repositories.WebAuthnCredentialRepository
+}
+
+// NewRevokeDeviceHandler creates a new revoke device handler
+func NewRevokeDeviceHandler(
+	deviceRepo repositories.DeviceRepository,
+	credentialRepo repositories.WebAuthnCredentialRepository,
+) *RevokeDeviceHandler {
+	return &RevokeDeviceHandler{
+		deviceRepo:     deviceRepo,
+		credentialRepo: credentialRepo,
+	}
+}
+
+// RequiredPermission returns the permission needed to execute the command
+func (h *RevokeDeviceHandler) RequiredPermission() entities.Permission {
+	return entities.PermissionDevicesRevoke
+}
+
+// PolicyResource describes the device being revoked for policy evaluation
+func (h *RevokeDeviceHandler) PolicyResource(ctx context.Context, cmd RevokeDeviceCommand) (entities.PolicyAttributes, error) {
+	device, err := h.loadDevice(ctx, cmd.DeviceID)
+	if err != nil {
+		return nil, err
+	}
+	return devicePolicyAttributes(device), nil
+}
+
+// AuditTarget names the device being revoked
+func (h *RevokeDeviceHandler) AuditTarget(cmd RevokeDeviceCommand, res *RevokeDeviceResult) entities.AuditTarget {
+	return entities.AuditTarget{Type: entities.AuditTargetDevice, ID: cmd.DeviceID}
+}
+
+// Handle executes the revoke device command
+func (h *RevokeDeviceHandler) Handle(ctx context.Context, cmd RevokeDeviceCommand) (*RevokeDeviceResult, error) {
+	// Load device
+	device, err := h.loadDevice(ctx, cmd.DeviceID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkExpectedVersion(cmd.Version, device.ProfileVersion); err != nil {
+		return nil, err
+	}
+
+	// Revoke device; revoked devices are kept so their history stays visible
+	if err := device.Revoke(); err != nil {
+		return nil, errors.WrapWithType(err, errors.ErrorTypeConflict, "device revocation failed")
+	}
+
+	// Remove passkeys bound to the device
+	revoked, err := deleteDeviceCredentials(ctx, h.credentialRepo, device)
+	if err != nil {
+		return nil, err
+	}
+
+	// Save device
+	if err := h.deviceRepo.Update(ctx, device); err != nil {
+		return nil, errors.Wrap(err, "failed to update device")
+	}
+
+	// Return result
+	return &RevokeDeviceResult{
+		DeviceID:           device.ID,
+		UserID:             device.UserID,
+		RevokedCredentials: revoked,
+	}, nil
+}
+
+// loadDevice loads a device or returns a not found error
+func (h *RevokeDeviceHandler) loadDevice(ctx context.Context, deviceID types.ID) (*entities.Device, error) {
+	device, err := h.deviceRepo.GetByID(ctx, deviceID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get device")
+	}
+	if device == nil {
+		return nil, errors.WrapWithType(entities.ErrDeviceNotFound, errors.ErrorTypeNotFound, "device revocation failed")
+	}
+	return device, nil
+}
+
+// deleteDeviceCredentials removes the passkeys bound to a device and returns
+// how many were removed
+func deleteDeviceCredentials(ctx context.Context, credentialRepo repositories.WebAuthnCredentialRepository, device *entities.Device) (int, error) {
+	credentials, err := credentialRepo.ListByUserID(ctx, device.UserID)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to list credentials")
+	}
+	deleted := 0
+	for _, credential := range credentials {
+		if credential.DeviceID != device.ID {
+			continue
+		}
+		if err := credentialRepo.Delete(ctx, credential.ID); err != nil {
+			return 0, errors.Wrap(err, "failed to delete credential")
+		}
+		deleted++
+	}
+	return deleted, nil
+}
@@ -0,0 +1,97 @@
+package commands
+
+import (
+	"context"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/repositories"
+	"shadow-id/pkg/errors"
+	"shadow-id/pkg/types"
+)
+
+// RevokeRoleCommand represents the command to remove a role from a user
+type RevokeRoleCommand struct {
+	UserID types.ID `json:"user_id" validate:"required"`
+	Role   string   `json:"role" validate:"required"`
+}
+
+// RevokeRoleHandler handles the revoke role command
+type RevokeRoleHandler struct {
+	userRepo repositories.UserRepository
+}
+
+// NewRevokeRoleHandler creates a new revoke role handler
+func NewRevokeRoleHandler(userRepo repositories.UserRepository) *RevokeRoleHandler {
+	return &RevokeRoleHandler{
+		userRepo: userRepo,
+	}
+}
+
+// RequiredPermission returns the permission needed to execute the command
+func (h *RevokeRoleHandler) RequiredPermission() entities.Permission {
+	return entities.PermissionRolesAssign
+}
+
+// AuditTarget names the user whose roles change
+func (h *RevokeRoleHandler) AuditTarget(cmd RevokeRoleCommand, res *AssignRoleResult) entities.AuditTarget {
+	return entities.AuditTarget{Type: entities.AuditTargetUser, ID: cmd.UserID}
+}
+
+// Handle executes the revoke role command
+func (h *RevokeRoleHandler) Handle(ctx context.Context, cmd RevokeRoleCommand) (*AssignRoleResult, error) {
+	// Load user
+	user, err := h.userRepo.GetByID(ctx, cmd.UserID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get user")
+	}
+	if user == nil {
+		return nil, errors.NewNotFoundError("user not found")
+	}
+
+	// Never leave the installation without an administrator
+	if cmd.Role == entities.RoleAdmin && user.HasRole(entities.RoleAdmin) {
+		admins, err := countAdmins(ctx, h.userRepo)
+		if err != nil {
+			return nil, err
+		}
+		if admins <= 1 {
+			return nil, errors.WrapWithType(entities.ErrLastAdmin, errors.ErrorTypeConflict, "role revocation failed")
+		}
+	}
+
+	// Revoke role
+	if err := user.RevokeRole(cmd.Role); err != nil {
+		return nil, errors.WrapWithType(err, errors.ErrorTypeConflict, "role revocation failed")
+	}
+	if err := h.userRepo.Update(ctx, user); err != nil {
+		return nil, errors.Wrap(err, "failed to update user")
+	}
+
+	// Return result
+	return &AssignRoleResult{
+		UserID:    user.ID,
+		Roles:     user.Roles,
+		UpdatedAt: user.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		Version:   user.ProfileVersion,
+	}, nil
+}
+
+// countAdmins counts users holding the admin role
+func countAdmins(ctx context.Context, userRepo repositories.UserRepository) (int, error) {
+	total, err := userRepo.Count(ctx)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to count users")
+	}
+	users, err := userRepo.List(ctx, int(total), 0)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to list users")
+	}
+
+	admins := 0
+	for _, user := range users {
+		if user.HasRole(entities.RoleAdmin) {
+			admins++
+		}
+	}
+	return admins, nil
+}
@@ -0,0 +1,79 @@
+package commands
+
+import (
+	"context"
+
+	"shadow-id/internal/app/auth"
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/repositories"
+	"shadow-id/pkg/errors"
+	"shadow-id/pkg/types"
+)
+
+// SignOutCommand represents the command to record that the calling user
+// ended their session, on a device if the session was bound to one
+type SignOutCommand struct {
+	DeviceID types.ID `json:"device_id"`
+}
+
+// SignOutResult represents the result of signing out
+type SignOutResult struct {
+	UserID   types.ID `json:"user_id"`
+	DeviceID types.ID `json:"device_id,omitempty"`
+}
+
+// SignOutHandler handles the sign out command
+type SignOutHandler struct {
+	userRepo repositories.UserRepository
+}
+
+// NewSignOutHandler creates a new sign out handler
+func NewSignOutHandler(userRepo repositories.UserRepository) *SignOutHandler {
+	return &SignOutHandler{
+		userRepo: userRepo,
+	}
+}
+
+// RequiredPermission returns the permission needed to execute the command.
+// Any signed-in user may sign out; the handler rejects anonymous callers.
+func (h *SignOutHandler) RequiredPermission() entities.Permission {
+	return entities.PermissionNone
+}
+
+// AuditTarget names the user who signed out
+func (h *SignOutHandler) AuditTarget(cmd SignOutCommand, res *SignOutResult) entities.AuditTarget {
+	// The user is only known from the caller once the command ran
+	if res == nil {
+		return entities.AuditTarget{Type: entities.AuditTargetUser}
+	}
+	return entities.AuditTarget{Type: entities.AuditTargetUser, ID: res.UserID}
+}
+
+// Handle executes the sign out command
+func (h *SignOutHandler) Handle(ctx context.Context, cmd SignOutCommand) (*SignOutResult, error) {
+	principal := auth.PrincipalFromContext(ctx)
+	if principal.UserID.IsEmpty() {
+		return nil, errors.NewUnauthorizedError("not signed in")
+	}
+
+	// Load user
+	user, err := h.userRepo.GetByID(ctx, principal.UserID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get user")
+	}
+	if user == nil {
+		return nil, errors.NewNotFoundError("user not found")
+	}
+
+	// Record logout
+	user.RecordLogout(cmd.DeviceID)
+	if err := h.userRepo.Update(ctx, user); err != nil {
+		return nil, errors.Wrap(err, "failed to record logout")
+	}
+
+	// Return result
+	return &SignOutResult{
+		UserID:   user.ID,
+		DeviceID: cmd.DeviceID,
+	}, nil
+}
@@ -0,0 +1,72 @@
+package commands
+
+import (
+	"context"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/repositories"
+	"shadow-id/pkg/errors"
+	"shadow-id/pkg/types"
+)
+
+// SuspendDeviceCommand represents the command to suspend a pending or trusted
+// device. A non-zero version must match the device's profile version.
+type SuspendDeviceCommand struct {
+	DeviceID types.ID `json:"device_id" validate:"required"`
+	Reason   string   `json:"reason" validate:"max=200"`
+	Version  int64    `json:"version" validate:"min=0"`
+}
+
+// SuspendDeviceHandler handles the suspend device command
+type SuspendDeviceHandler struct {
+	deviceRepo repositories.DeviceRepository
+}
+
+// NewSuspendDeviceHandler creates a new suspend device handler
+func NewSuspendDeviceHandler(deviceRepo repositories.DeviceRepository) *SuspendDeviceHandler {
+	return &SuspendDeviceHandler{
+		deviceRepo: deviceRepo,
+	}
+}
+
+// RequiredPermission returns the permission needed to execute the command
+func (h *SuspendDeviceHandler) RequiredPermission() entities.Permission {
+	return entities.PermissionDevicesApprove
+}
+
+// PolicyResource describes the device being suspended for policy evaluation
+func (h *SuspendDeviceHandler) PolicyResource(ctx context.Context, cmd SuspendDeviceCommand) (entities.PolicyAttributes, error) {
+	device, err := loadTrustDevice(ctx, h.deviceRepo, cmd.DeviceID, "device suspension failed")
+	if err != nil {
+		return nil, err
+	}
+	return devicePolicyAttributes(device), nil
+}
+
+// AuditTarget names the device being suspended
+func (h *SuspendDeviceHandler) AuditTarget(cmd SuspendDeviceCommand, res *DeviceTrustResult) entities.AuditTarget {
+	return entities.AuditTarget{Type: entities.AuditTargetDevice, ID: cmd.DeviceID}
+}
+
+// Handle executes the suspend device command
+func (h *SuspendDeviceHandler) Handle(ctx context.Context, cmd SuspendDeviceCommand) (*DeviceTrustResult, error) {
+	// Load device
+	device, err := loadTrustDevice(ctx, h.deviceRepo, cmd.DeviceID, "device suspension failed")
+	if err != nil {
+		return nil, err
+	}
+	if err := checkExpectedVersion(cmd.Version, device.ProfileVersion); err != nil {
+		return nil, err
+	}
+
+	// Suspend device
+	if err := device.Suspend(cmd.Reason); err != nil {
+		return nil, errors.WrapWithType(err, errors.ErrorTypeConflict, "device suspension failed")
+	}
+	if err := h.deviceRepo.Update(ctx, device); err != nil {
+		return nil, errors.Wrap(err, "failed to update device")
+	}
+
+	// Return result
+	return newDeviceTrustResult(device), nil
+}
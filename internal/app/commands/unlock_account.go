@@ -0,0 +1,86 @@
+package commands
+
+import (
+	"context"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/repositories"
+	"shadow-id/internal/domain/services"
+	"shadow-id/pkg/errors"
+	"shadow-id/pkg/types"
+)
+
+// UnlockAccountCommand represents the administrative command to clear a lockout.
+// When DeviceID is set, the device's throttle is cleared as well.
+type UnlockAccountCommand struct {
+	UserID   types.ID `json:"user_id" validate:"required"`
+	DeviceID types.ID `json:"device_id"`
+}
+
+// UnlockAccountResult represents the result of unlocking an account
+type UnlockAccountResult struct {
+	UserID   types.ID `json:"user_id"`
+	DeviceID types.ID `json:"device_id,omitempty"`
+	Unlocked bool     `json:"unlocked"`
+}
+
+// UnlockAccountHandler handles the unlock account command
+type UnlockAccountHandler struct {
+	userRepo     repositories.UserRepository
+	throttleRepo repositories.LoginThrottleRepository
+	rateLimiter  services.RateLimiter
+}
+
+// NewUnlockAccountHandler creates a new unlock account handler
+func NewUnlockAccountHandler(
+	userRepo repositories.UserRepository,
+	throttleRepo repositories.LoginThrottleRepository,
+	rateLimiter services.RateLimiter,
+) *UnlockAccountHandler {
+	return &UnlockAccountHandler{
+		userRepo:     userRepo,
+		throttleRepo: throttleRepo,
+		rateLimiter:  rateLimiter,
+	}
+}
+
+// RequiredPermission returns the permission needed to execute the command
+func (h *UnlockAccountHandler) RequiredPermission() entities.Permission {
+	return entities.PermissionAccountsUnlock
+}
+
+// AuditTarget names the user being unlocked
+func (h *UnlockAccountHandler) AuditTarget(cmd UnlockAccountCommand, res *UnlockAccountResult) entities.AuditTarget {
+	return entities.AuditTarget{Type: entities.AuditTargetUser, ID: cmd.UserID}
+}
+
+// Handle executes the unlock account command
+func (h *UnlockAccountHandler) Handle(ctx context.Context, cmd UnlockAccountCommand) (*UnlockAccountResult, error) {
+	// Load user
+	user, err := h.userRepo.GetByID(ctx, cmd.UserID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get user")
+	}
+	if user == nil {
+		return nil, errors.NewNotFoundError("user not found")
+	}
+
+	// Clear throttles and rate limiter state
+	subjects := map[string]types.ID{entities.ThrottleScopeAccount: user.ID}
+	if !cmd.DeviceID.IsEmpty() {
+		subjects[entities.ThrottleScopeDevice] = cmd.DeviceID
+	}
+	for scope, subjectID := range subjects {
+		if err := h.throttleRepo.Delete(ctx, scope, subjectID); err != nil {
+			return nil, errors.Wrap(err, "failed to clear lockout")
+		}
+		h.rateLimiter.Reset(ctx, scope+":"+subjectID.String())
+	}
+
+	// Return result
+	return &UnlockAccountResult{
+		UserID:   user.ID,
+		DeviceID: cmd.DeviceID,
+		Unlocked: true,
+	}, nil
+}
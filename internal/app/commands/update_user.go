@@ -0,0 +1,135 @@
+package commands
+
+import (
+	"context"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/repositories"
+	"shadow-id/internal/domain/services"
+	"shadow-id/pkg/errors"
+	"shadow-id/pkg/types"
+)
+
+// UpdateUserCommand represents the command to update a user's profile.
+// Empty fields are left unchanged. A non-zero version must match the user's
+// current version, so changes based on a stale read are rejected.
+type UpdateUserCommand struct {
+	ID      types.ID `json:"id" validate:"required"`
+	Name    string   `json:"name" validate:"max=100"`
+	Email   string   `json:"email" validate:"omitempty,email"`
+	Version int64    `json:"version" validate:"min=0"`
+}
+
+// UpdateUserResult represents the result of updating a user
+type UpdateUserResult struct {
+	ID        types.ID `json:"id"`
+	Name      string   `json:"name"`
+	Email     string   `json:"email"`
+	UpdatedAt string   `json:"updated_at"`
+	Version   int64    `json:"version"`
+}
+
+// UpdateUserHandler handles the update user command
+type UpdateUserHandler struct {
+	userRepo    repositories.UserRepository
+	userService services.UserService
+}
+
+// NewUpdateUserHandler creates a new update user handler
+func NewUpdateUserHandler(
+	userRepo repositories.UserRepository,
+	userService services.UserService,
+) *UpdateUserHandler {
+	return &UpdateUserHandler{
+		userRepo:    userRepo,
+		userService: userService,
+	}
+}
+
+// RequiredPermission returns the permission needed to execute the command
+func (h *UpdateUserHandler) RequiredPermission() entities.Permission {
+	return entities.PermissionUsersUpdate
+}
+
+// PolicyResource describes the user being edited for policy evaluation
+func (h *UpdateUserHandler) PolicyResource(ctx context.Context, cmd UpdateUserCommand) (entities.PolicyAttributes, error) {
+	return userPolicyAttributes(cmd.ID), nil
+}
+
+// AuditTarget names the user being updated
+func (h *UpdateUserHandler) AuditTarget(cmd UpdateUserCommand, res *UpdateUserResult) entities.AuditTarget {
+	return entities.AuditTarget{Type: entities.AuditTargetUser, ID: cmd.ID}
+}
+
+// Handle executes the update user command
+func (h *UpdateUserHandler) Handle(ctx context.Context, cmd UpdateUserCommand) (*UpdateUserResult, error) {
+	// Load user
+	user, err := h.userRepo.GetByID(ctx, cmd.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get user")
+	}
+	if user == nil {
+		return nil, errors.NewNotFoundError("user not found")
+	}
+	if err := checkExpectedVersion(cmd.Version, user.ProfileVersion); err != nil {
+		return nil, err
+	}
+
+	// Apply changes
+	if cmd.Name != "" {
+		user.UpdateName(cmd.Name)
+	}
+	if cmd.Email != "" && cmd.Email != user.Email {
+		isUnique, err := h.userService.IsEmailUnique(ctx, cmd.Email, user.ID)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to check email uniqueness")
+		}
+		if !isUnique {
+			return nil, entities.ErrUserAlreadyExists
+		}
+		user.UpdateEmail(cmd.Email)
+	}
+
+	// Validate user
+	if err := user.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid user data")
+	}
+	if err := h.userService.ValidateUserUpdate(ctx, user); err != nil {
+		return nil, errors.Wrap(err, "user update validation failed")
+	}
+
+	// Save user
+	if err := h.userRepo.Update(ctx, user); err != nil {
+		return nil, errors.Wrap(err, "failed to update user")
+	}
+
+	// Return result
+	return &UpdateUserResult{
+		ID:        user.ID,
+		Name:      user.Name,
+		Email:     user.Email,
+		UpdatedAt: user.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		Version:   user.ProfileVersion,
+	}, nil
+}
+
+// checkExpectedVersion fails with a conflict carrying the current version
+// unless the expected version is zero or matches it
+func checkExpectedVersion(expectedVersion, currentVersion int64) error {
+	if expectedVersion == 0 || expectedVersion == currentVersion {
+		return nil
+	}
+	return errors.WrapWithType(entities.ErrVersionConflict, errors.ErrorTypeConflict, "entity version mismatch").
+		WithDetail("expected_version", expectedVersion).
+		WithDetail("current_version", currentVersion)
+}
+
+// userPolicyAttributes describes a user account for policy evaluation; a user
+// owns their own account and everything attached to it
+func userPolicyAttributes(userID types.ID) entities.PolicyAttributes {
+	return entities.PolicyAttributes{
+		"type":     "user",
+		"id":       userID.String(),
+		"owner_id": userID.String(),
+	}
+}
@@ -0,0 +1,100 @@
+package commands
+
+import (
+	"context"
+	"encoding/base64"
+	"time"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/repositories"
+	"shadow-id/internal/domain/services"
+	"shadow-id/pkg/errors"
+	"shadow-id/pkg/types"
+)
+
+// VerifyDeviceChallengeCommand represents a device's answer to a challenge;
+// the signature is base64url encoded
+type VerifyDeviceChallengeCommand struct {
+	ChallengeID types.ID `json:"challenge_id" validate:"required"`
+	DeviceID    types.ID `json:"device_id" validate:"required"`
+	Signature   string   `json:"signature" validate:"required"`
+}
+
+// VerifyDeviceChallengeResult represents a device that proved itself
+type VerifyDeviceChallengeResult struct {
+	DeviceID   types.ID `json:"device_id"`
+	UserID     types.ID `json:"user_id"`
+	TrustState string   `json:"trust_state"`
+	VerifiedAt string   `json:"verified_at"`
+}
+
+// VerifyDeviceChallengeHandler handles the verify device challenge command.
+// A challenge is consumed by the first answer, right or wrong, so a captured
+// signature cannot be replayed.
+type VerifyDeviceChallengeHandler struct {
+	deviceRepo    repositories.DeviceRepository
+	challengeRepo repositories.DeviceChallengeRepository
+	keyService    services.DeviceKeyService
+}
+
+// NewVerifyDeviceChallengeHandler creates a new verify device challenge handler
+func NewVerifyDeviceChallengeHandler(
+	deviceRepo repositories.DeviceRepository,
+	challengeRepo repositories.DeviceChallengeRepository,
+	keyService services.DeviceKeyService,
+) *VerifyDeviceChallengeHandler {
+	return &VerifyDeviceChallengeHandler{
+		deviceRepo:    deviceRepo,
+		challengeRepo: challengeRepo,
+		keyService:    keyService,
+	}
+}
+
+// RequiredPermission returns the permission needed to execute the command.
+// Devices prove themselves before anyone is signed in.
+func (h *VerifyDeviceChallengeHandler) RequiredPermission() entities.Permission {
+	return entities.PermissionNone
+}
+
+// AuditTarget names the device that answered
+func (h *VerifyDeviceChallengeHandler) AuditTarget(cmd VerifyDeviceChallengeCommand, res *VerifyDeviceChallengeResult) entities.AuditTarget {
+	return entities.AuditTarget{Type: entities.AuditTargetDevice, ID: cmd.DeviceID}
+}
+
+// Handle executes the verify device challenge command
+func (h *VerifyDeviceChallengeHandler) Handle(ctx context.Context, cmd VerifyDeviceChallengeCommand) (*VerifyDeviceChallengeResult, error) {
+	// Consume challenge
+	challenge, err := h.challengeRepo.Take(ctx, cmd.ChallengeID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get device challenge")
+	}
+	if challenge == nil || challenge.DeviceID != cmd.DeviceID {
+		return nil, errors.WrapWithType(entities.ErrDeviceChallengeNotFound, errors.ErrorTypeNotFound, "device challenge failed")
+	}
+	if challenge.IsExpired() {
+		return nil, errors.WrapWithType(entities.ErrDeviceChallengeExpired, errors.ErrorTypeValidation, "device challenge failed")
+	}
+
+	// Decode signature
+	signature, err := base64.RawURLEncoding.DecodeString(cmd.Signature)
+	if err != nil {
+		return nil, errors.NewValidationError("signature is not valid base64url")
+	}
+
+	// Verify signature with the device's key
+	device, err := loadProvingDevice(ctx, h.deviceRepo, challenge.DeviceID, challenge.AllowPending)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.keyService.Verify(device.PublicKey, challenge.Message(), signature); err != nil {
+		return nil, errors.WrapWithType(err, errors.ErrorTypeUnauthorized, "device challenge failed")
+	}
+
+	// Return result
+	return &VerifyDeviceChallengeResult{
+		DeviceID:   device.ID,
+		UserID:     device.UserID,
+		TrustState: string(device.TrustState),
+		VerifiedAt: time.Now().Format("2006-01-02T15:04:05Z07:00"),
+	}, nil
+}
@@ -0,0 +1,162 @@
+package commands
+
+import (
+	"context"
+	"time"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/repositories"
+	"shadow-id/internal/domain/services"
+	"shadow-id/pkg/errors"
+	"shadow-id/pkg/types"
+)
+
+// Two-factor verification methods
+const (
+	TwoFactorMethodTOTP         = "totp"
+	TwoFactorMethodRecoveryCode = "recovery_code"
+)
+
+// VerifyTwoFactorCommand represents the command to verify a TOTP or recovery code
+type VerifyTwoFactorCommand struct {
+	UserID   types.ID `json:"user_id" validate:"required"`
+	DeviceID types.ID `json:"device_id"`
+	Code     string   `json:"code" validate:"required"`
+}
+
+// VerifyTwoFactorResult represents the result of verifying a second factor
+type VerifyTwoFactorResult struct {
+	UserID                 types.ID `json:"user_id"`
+	Verified               bool     `json:"verified"`
+	Method                 string   `json:"method"`
+	RemainingRecoveryCodes int      `json:"remaining_recovery_codes"`
+}
+
+// VerifyTwoFactorHandler handles the verify two-factor command
+type VerifyTwoFactorHandler struct {
+	userRepo      repositories.UserRepository
+	twoFactorRepo repositories.TwoFactorRepository
+	totpService   services.TOTPService
+	guard         services.AuthenticationGuard
+}
+
+// NewVerifyTwoFactorHandler creates a new verify two-factor handler
+func NewVerifyTwoFactorHandler(
+	userRepo repositories.UserRepository,
+	twoFactorRepo repositories.TwoFactorRepository,
+	totpService services.TOTPService,
+	guard services.AuthenticationGuard,
+) *VerifyTwoFactorHandler {
+	return &VerifyTwoFactorHandler{
+		userRepo:      userRepo,
+		twoFactorRepo: twoFactorRepo,
+		totpService:   totpService,
+		guard:         guard,
+	}
+}
+
+// RequiredPermission returns the permission needed to execute the command
+func (h *VerifyTwoFactorHandler) RequiredPermission() entities.Permission {
+	return entities.PermissionNone
+}
+
+// AuditTarget names the enrollment the code is checked against
+func (h *VerifyTwoFactorHandler) AuditTarget(cmd VerifyTwoFactorCommand, res *VerifyTwoFactorResult) entities.AuditTarget {
+	return entities.AuditTarget{Type: entities.AuditTargetTwoFactor, ID: cmd.UserID}
+}
+
+// Handle executes the verify two-factor command
+func (h *VerifyTwoFactorHandler) Handle(ctx context.Context, cmd VerifyTwoFactorCommand) (*VerifyTwoFactorResult, error) {
+	// Load confirmed enrollment
+	twoFactor, err := loadConfirmedTwoFactor(ctx, h.twoFactorRepo, cmd.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify code and persist the consumed step or recovery code
+	method, err := guardedVerifyTwoFactorCode(ctx, h.guard, h.totpService, twoFactor, cmd.DeviceID, cmd.Code, "two-factor verification failed")
+	if err != nil {
+		return nil, err
+	}
+	if err := h.twoFactorRepo.Update(ctx, twoFactor); err != nil {
+		return nil, errors.Wrap(err, "failed to save two-factor enrollment")
+	}
+
+	// Record the completed sign-in
+	user, err := h.userRepo.GetByID(ctx, cmd.UserID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get user")
+	}
+	if user == nil {
+		return nil, errors.NewNotFoundError("user not found")
+	}
+	user.RecordLogin(method, cmd.DeviceID)
+	if err := h.userRepo.Update(ctx, user); err != nil {
+		return nil, errors.Wrap(err, "failed to record login")
+	}
+
+	// Return result
+	return &VerifyTwoFactorResult{
+		UserID:                 twoFactor.UserID,
+		Verified:               true,
+		Method:                 method,
+		RemainingRecoveryCodes: twoFactor.RemainingRecoveryCodes(),
+	}, nil
+}
+
+// loadConfirmedTwoFactor loads a user's enrollment and requires it to be confirmed
+func loadConfirmedTwoFactor(ctx context.Context, twoFactorRepo repositories.TwoFactorRepository, userID types.ID) (*entities.TwoFactor, error) {
+	twoFactor, err := twoFactorRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get two-factor enrollment")
+	}
+	if twoFactor == nil || !twoFactor.Confirmed {
+		return nil, errors.WrapWithType(entities.ErrTwoFactorNotEnrolled, errors.ErrorTypeNotFound, "two-factor verification failed")
+	}
+	return twoFactor, nil
+}
+
+// guardedVerifyTwoFactorCode verifies a code behind the authentication guard,
+// recording the outcome so repeated failures are throttled and locked out
+func guardedVerifyTwoFactorCode(
+	ctx context.Context,
+	guard services.AuthenticationGuard,
+	totpService services.TOTPService,
+	twoFactor *entities.TwoFactor,
+	deviceID types.ID,
+	code string,
+	failureMessage string,
+) (string, error) {
+	if err := guard.Check(ctx, twoFactor.UserID, deviceID); err != nil {
+		return "", err
+	}
+
+	method, err := verifyTwoFactorCode(totpService, twoFactor, code)
+	if err != nil {
+		if recordErr := guard.RecordFailure(ctx, twoFactor.UserID, deviceID); recordErr != nil {
+			return "", errors.Wrap(recordErr, "failed to record failed attempt")
+		}
+		return "", errors.WrapWithType(err, errors.ErrorTypeValidation, failureMessage)
+	}
+
+	if err := guard.RecordSuccess(ctx, twoFactor.UserID, deviceID); err != nil {
+		return "", errors.Wrap(err, "failed to clear failed attempts")
+	}
+	return method, nil
+}
+
+// verifyTwoFactorCode accepts either a TOTP code or an unused recovery code and
+// marks it as consumed on the enrollment
+func verifyTwoFactorCode(totpService services.TOTPService, twoFactor *entities.TwoFactor, code string) (string, error) {
+	if step, ok := totpService.Validate(twoFactor.Secret, code, time.Now()); ok {
+		if err := twoFactor.UseStep(step); err != nil {
+			return "", err
+		}
+		return TwoFactorMethodTOTP, nil
+	}
+
+	if err := twoFactor.UseRecoveryCode(totpService.HashRecoveryCode(code)); err != nil {
+		return "", err
+	}
+	return TwoFactorMethodRecoveryCode, nil
+}
@@ -0,0 +1,147 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"shadow-id/pkg/errors"
+)
+
+// route is a registered handler bound to the middleware chain
+type route struct {
+	kind   Kind
+	result reflect.Type
+	handle func(ctx context.Context, msg interface{}) (interface{}, error)
+}
+
+// Bus routes commands and queries to the handler registered for their type.
+// Every handler goes through the dispatcher's middleware chain.
+type Bus struct {
+	mu         sync.RWMutex
+	dispatcher *Dispatcher
+	routes     map[reflect.Type]route
+	duplicates []reflect.Type
+}
+
+// NewBus creates an empty bus using the dispatcher's middleware
+func NewBus(dispatcher *Dispatcher) *Bus {
+	return &Bus{
+		dispatcher: dispatcher,
+		routes:     make(map[reflect.Type]route),
+	}
+}
+
+// RegisterCommand registers the handler for commands of type C
+func RegisterCommand[C, R any](b *Bus, handler CommandHandler[C, R]) {
+	register[C, R](b, KindCommand, Command[C, R](b.dispatcher, handler))
+}
+
+// RegisterQuery registers the handler for queries of type Q
+func RegisterQuery[Q, R any](b *Bus, handler QueryHandler[Q, R]) {
+	register[Q, R](b, KindQuery, Query[Q, R](b.dispatcher, handler))
+}
+
+// register records a route for a message type; a second registration for
+// the same type is kept aside and reported by Verify
+func register[Req, Res any](b *Bus, kind Kind, handler interface {
+	Handle(ctx context.Context, req Req) (Res, error)
+}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	msgType := reflect.TypeOf((*Req)(nil)).Elem()
+	if _, exists := b.routes[msgType]; exists {
+		b.duplicates = append(b.duplicates, msgType)
+		return
+	}
+	b.routes[msgType] = route{
+		kind:   kind,
+		result: reflect.TypeOf((*Res)(nil)).Elem(),
+		handle: func(ctx context.Context, msg interface{}) (interface{}, error) {
+			return handler.Handle(ctx, msg.(Req))
+		},
+	}
+}
+
+// Dispatch sends a command or query to its registered handler
+func (b *Bus) Dispatch(ctx context.Context, msg interface{}) (interface{}, error) {
+	b.mu.RLock()
+	route, ok := b.routes[reflect.TypeOf(msg)]
+	b.mu.RUnlock()
+
+	if !ok {
+		return nil, errors.NewInternalError(fmt.Sprintf("no handler registered for %T", msg))
+	}
+	return route.handle(ctx, msg)
+}
+
+// ResultType returns the type of the result the handler registered for a
+// message type returns
+func (b *Bus) ResultType(msgType reflect.Type) (reflect.Type, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	route, ok := b.routes[msgType]
+	if !ok {
+		return nil, false
+	}
+	return route.result, true
+}
+
+// Send dispatches a command or query and returns its result as type R
+func Send[R any](ctx context.Context, b *Bus, msg interface{}) (R, error) {
+	var zero R
+	result, err := b.Dispatch(ctx, msg)
+	if err != nil {
+		return zero, err
+	}
+	typed, ok := result.(R)
+	if !ok {
+		return zero, errors.NewInternalError(fmt.Sprintf("handler for %T returned %T, not %T", msg, result, zero))
+	}
+	return typed, nil
+}
+
+// Verify checks that every listed command and query has exactly one handler
+// and that no handler is registered for a message that is not listed
+func (b *Bus) Verify(commands, queries []interface{}) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	var problems []string
+	expected := make(map[reflect.Type]bool, len(commands)+len(queries))
+	check := func(kind Kind, messages []interface{}) {
+		for _, msg := range messages {
+			msgType := reflect.TypeOf(msg)
+			expected[msgType] = true
+			route, ok := b.routes[msgType]
+			switch {
+			case !ok:
+				problems = append(problems, fmt.Sprintf("%s %s has no handler", kind, msgType))
+			case route.kind != kind:
+				problems = append(problems, fmt.Sprintf("%s %s is registered as a %s", kind, msgType, route.kind))
+			}
+		}
+	}
+	check(KindCommand, commands)
+	check(KindQuery, queries)
+
+	for _, msgType := range b.duplicates {
+		problems = append(problems, fmt.Sprintf("%s has more than one handler", msgType))
+	}
+	for msgType, route := range b.routes {
+		if !expected[msgType] {
+			problems = append(problems, fmt.Sprintf("%s %s is not a known message", route.kind, msgType))
+		}
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return errors.NewInternalError("handler registration is inconsistent: " + strings.Join(problems, "; "))
+	}
+	return nil
+}
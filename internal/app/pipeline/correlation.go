@@ -0,0 +1,38 @@
+package pipeline
+
+import (
+	"context"
+
+	"shadow-id/pkg/types"
+)
+
+// correlationKey is the context key for the correlation ID
+type correlationKey struct{}
+
+// WithCorrelationID returns a context carrying the correlation ID
+func WithCorrelationID(ctx context.Context, correlationID string) context.Context {
+	return context.WithValue(ctx, correlationKey{}, correlationID)
+}
+
+// CorrelationID returns the correlation ID carried by the context, or an empty
+// string if there is none
+func CorrelationID(ctx context.Context) string {
+	if correlationID, ok := ctx.Value(correlationKey{}).(string); ok {
+		return correlationID
+	}
+	return ""
+}
+
+// Correlation assigns a correlation ID to requests that arrive without one so
+// everything a request causes can be traced back to it. Requests dispatched
+// from within a handler keep the ID of the request that issued them.
+func Correlation() Middleware {
+	return func(next Next) Next {
+		return func(ctx context.Context, req *Request) (interface{}, error) {
+			if CorrelationID(ctx) == "" {
+				ctx = WithCorrelationID(ctx, types.NewID().String())
+			}
+			return next(ctx, req)
+		}
+	}
+}
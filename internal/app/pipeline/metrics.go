@@ -0,0 +1,102 @@
+package pipeline
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// HandlerMetrics summarises the calls made to a single handler
+type HandlerMetrics struct {
+	Kind            Kind    `json:"kind"`
+	Name            string  `json:"name"`
+	Calls           int64   `json:"calls"`
+	Errors          int64   `json:"errors"`
+	AverageDuration float64 `json:"average_duration_ms"`
+	MaxDuration     float64 `json:"max_duration_ms"`
+}
+
+// handlerStats accumulates raw call statistics
+type handlerStats struct {
+	kind   Kind
+	calls  int64
+	errors int64
+	total  time.Duration
+	max    time.Duration
+}
+
+// Metrics collects per-handler call counts, error counts and timings
+type Metrics struct {
+	mu       sync.Mutex
+	handlers map[string]*handlerStats
+}
+
+// NewMetrics creates an empty metrics collector
+func NewMetrics() *Metrics {
+	return &Metrics{
+		handlers: make(map[string]*handlerStats),
+	}
+}
+
+// Middleware records every request passing through the chain
+func (m *Metrics) Middleware() Middleware {
+	return func(next Next) Next {
+		return func(ctx context.Context, req *Request) (interface{}, error) {
+			started := time.Now()
+			result, err := next(ctx, req)
+			m.record(req, time.Since(started), err != nil)
+			return result, err
+		}
+	}
+}
+
+// Snapshot returns the collected metrics sorted by handler name
+func (m *Metrics) Snapshot() []HandlerMetrics {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snapshot := make([]HandlerMetrics, 0, len(m.handlers))
+	for name, stats := range m.handlers {
+		metrics := HandlerMetrics{
+			Kind:        stats.kind,
+			Name:        name,
+			Calls:       stats.calls,
+			Errors:      stats.errors,
+			MaxDuration: milliseconds(stats.max),
+		}
+		if stats.calls > 0 {
+			metrics.AverageDuration = milliseconds(stats.total) / float64(stats.calls)
+		}
+		snapshot = append(snapshot, metrics)
+	}
+	sort.Slice(snapshot, func(i, j int) bool {
+		return snapshot[i].Name < snapshot[j].Name
+	})
+	return snapshot
+}
+
+// record adds a single call to the statistics
+func (m *Metrics) record(req *Request, elapsed time.Duration, failed bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats, ok := m.handlers[req.Name]
+	if !ok {
+		stats = &handlerStats{kind: req.Kind}
+		m.handlers[req.Name] = stats
+	}
+	stats.calls++
+	if failed {
+		stats.errors++
+	}
+	stats.total += elapsed
+	if elapsed > stats.max {
+		stats.max = elapsed
+	}
+}
+
+// milliseconds converts a duration to fractional milliseconds
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
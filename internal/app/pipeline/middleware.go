@@ -0,0 +1,90 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"shadow-id/pkg/errors"
+	"shadow-id/pkg/logger"
+	"shadow-id/pkg/validation"
+)
+
+// Recovery converts a panicking handler into an internal error
+func Recovery(log logger.Logger) Middleware {
+	return func(next Next) Next {
+		return func(ctx context.Context, req *Request) (result interface{}, err error) {
+			defer func() {
+				if recovered := recover(); recovered != nil {
+					log.Error("Handler panicked",
+						"kind", req.Kind, "name", req.Name,
+						"panic", fmt.Sprint(recovered), "stack", string(debug.Stack()))
+					result = nil
+					err = errors.NewInternalError("internal error").WithDetail("handler", req.Name)
+				}
+			}()
+			return next(ctx, req)
+		}
+	}
+}
+
+// Logging logs the outcome and duration of every request
+func Logging(log logger.Logger) Middleware {
+	return func(next Next) Next {
+		return func(ctx context.Context, req *Request) (interface{}, error) {
+			started := time.Now()
+			correlationID := CorrelationID(ctx)
+			log.Debug("Handling request", "kind", req.Kind, "name", req.Name, "correlation_id", correlationID)
+
+			result, err := next(ctx, req)
+
+			elapsed := time.Since(started)
+			if err != nil {
+				log.Warn("Request failed",
+					"kind", req.Kind, "name", req.Name, "correlation_id", correlationID,
+					"duration", elapsed, "error", err)
+				return result, err
+			}
+			log.Debug("Request handled",
+				"kind", req.Kind, "name", req.Name, "correlation_id", correlationID, "duration", elapsed)
+			return result, nil
+		}
+	}
+}
+
+// Validation rejects requests whose payload fails its `validate` tags
+func Validation() Middleware {
+	return func(next Next) Next {
+		return func(ctx context.Context, req *Request) (interface{}, error) {
+			if err := validation.Struct(req.Payload); err != nil {
+				return nil, err
+			}
+			return next(ctx, req)
+		}
+	}
+}
+
+// Timeout bounds how long a request may run. Handlers receive a context with
+// the deadline and are expected to give up once it passes. A request that
+// outlives its deadline fails with a timeout error even if its handler
+// finished, so Timeout must run inside Transaction: the command is then
+// rolled back and a caller never sees a timeout for a command that committed.
+func Timeout(timeout time.Duration) Middleware {
+	return func(next Next) Next {
+		if timeout <= 0 {
+			return next
+		}
+		return func(ctx context.Context, req *Request) (interface{}, error) {
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			result, err := next(ctx, req)
+			if ctx.Err() == context.DeadlineExceeded {
+				return nil, errors.WrapWithType(ctx.Err(), errors.ErrorTypeTimeout, req.Name+" timed out").
+					WithDetail("timeout_ms", timeout.Milliseconds())
+			}
+			return result, err
+		}
+	}
+}
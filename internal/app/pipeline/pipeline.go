@@ -0,0 +1,188 @@
+package pipeline
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	"shadow-id/internal/domain/entities"
+)
+
+// CommandHandler handles a command of type C and produces a result of type R
+type CommandHandler[C, R any] interface {
+	Handle(ctx context.Context, cmd C) (R, error)
+}
+
+// QueryHandler handles a query of type Q and produces a result of type R
+type QueryHandler[Q, R any] interface {
+	Handle(ctx context.Context, query Q) (R, error)
+}
+
+// Permissioned is implemented by handlers that declare the permission they require
+type Permissioned interface {
+	RequiredPermission() entities.Permission
+}
+
+// ResourceHandler is implemented by handlers whose requests target a resource
+// governed by attribute-based policies. The returned attributes describe the
+// resource, typically its type, id and owner_id.
+type ResourceHandler[Req any] interface {
+	PolicyResource(ctx context.Context, req Req) (entities.PolicyAttributes, error)
+}
+
+// AuditedHandler is implemented by command handlers that act on an entity so
+// the audit log can name it and record how it changed. AuditTarget is called
+// with a nil result before the handler runs and again with its result once it
+// succeeded, so commands that create the entity can name it from the result.
+type AuditedHandler[Req, Res any] interface {
+	AuditTarget(req Req, res Res) entities.AuditTarget
+}
+
+// Kind distinguishes commands from queries
+type Kind string
+
+// Request kinds
+const (
+	KindCommand Kind = "command"
+	KindQuery   Kind = "query"
+)
+
+// Request describes a single call travelling through the middleware chain
+type Request struct {
+	Kind Kind
+	Name string
+
+	// Permission is the permission the handler declared. Handlers that declare
+	// none require PermissionAll so an omission fails closed.
+	Permission entities.Permission
+
+	Payload interface{}
+
+	resource    func(ctx context.Context) (entities.PolicyAttributes, error)
+	auditTarget func(result interface{}) entities.AuditTarget
+}
+
+// Resource returns the policy attributes of the resource the request targets.
+// The second value is false if the handler does not target a resource.
+func (r *Request) Resource(ctx context.Context) (entities.PolicyAttributes, bool, error) {
+	if r.resource == nil {
+		return nil, false, nil
+	}
+	attributes, err := r.resource(ctx)
+	return attributes, true, err
+}
+
+// AuditTarget returns the entity the request acts on given the handler's
+// result, which is nil before the handler ran. The target is empty if the
+// handler does not name one.
+func (r *Request) AuditTarget(result interface{}) entities.AuditTarget {
+	if r.auditTarget == nil {
+		return entities.AuditTarget{}
+	}
+	return r.auditTarget(result)
+}
+
+// Next invokes the rest of the chain
+type Next func(ctx context.Context, req *Request) (interface{}, error)
+
+// Middleware wraps the rest of the chain with cross-cutting behaviour
+type Middleware func(next Next) Next
+
+// Dispatcher routes every handler through a shared middleware chain
+type Dispatcher struct {
+	middleware []Middleware
+}
+
+// NewDispatcher creates a dispatcher. Middleware runs in the order given, so
+// the first middleware is the outermost.
+func NewDispatcher(middleware ...Middleware) *Dispatcher {
+	return &Dispatcher{
+		middleware: middleware,
+	}
+}
+
+// Command wraps a command handler so every call goes through the dispatcher
+func Command[C, R any](d *Dispatcher, handler CommandHandler[C, R]) CommandHandler[C, R] {
+	return dispatch[C, R](d, KindCommand, handler)
+}
+
+// Query wraps a query handler so every call goes through the dispatcher
+func Query[Q, R any](d *Dispatcher, handler QueryHandler[Q, R]) QueryHandler[Q, R] {
+	return dispatch[Q, R](d, KindQuery, handler)
+}
+
+// dispatched is a handler bound to a dispatcher's middleware chain
+type dispatched[Req, Res any] struct {
+	kind       Kind
+	name       string
+	permission entities.Permission
+	handler    interface {
+		Handle(ctx context.Context, req Req) (Res, error)
+	}
+	chain Next
+}
+
+// dispatch binds a handler to the middleware chain
+func dispatch[Req, Res any](d *Dispatcher, kind Kind, handler interface {
+	Handle(ctx context.Context, req Req) (Res, error)
+}) *dispatched[Req, Res] {
+	h := &dispatched[Req, Res]{
+		kind:       kind,
+		name:       handlerName(handler),
+		permission: entities.PermissionAll,
+		handler:    handler,
+	}
+	if permissioned, ok := handler.(Permissioned); ok {
+		h.permission = permissioned.RequiredPermission()
+	}
+
+	h.chain = func(ctx context.Context, req *Request) (interface{}, error) {
+		return h.handler.Handle(ctx, req.Payload.(Req))
+	}
+	for i := len(d.middleware) - 1; i >= 0; i-- {
+		h.chain = d.middleware[i](h.chain)
+	}
+	return h
+}
+
+// Handle runs the request through the middleware chain and the handler
+func (h *dispatched[Req, Res]) Handle(ctx context.Context, req Req) (Res, error) {
+	request := &Request{
+		Kind:       h.kind,
+		Name:       h.name,
+		Permission: h.permission,
+		Payload:    req,
+	}
+	if resourceHandler, ok := h.handler.(ResourceHandler[Req]); ok {
+		request.resource = func(ctx context.Context) (entities.PolicyAttributes, error) {
+			return resourceHandler.PolicyResource(ctx, req)
+		}
+	}
+
+	if auditedHandler, ok := h.handler.(AuditedHandler[Req, Res]); ok {
+		request.auditTarget = func(result interface{}) entities.AuditTarget {
+			typed, _ := result.(Res)
+			return auditedHandler.AuditTarget(req, typed)
+		}
+	}
+
+	var zero Res
+	result, err := h.chain(ctx, request)
+	if err != nil {
+		return zero, err
+	}
+	typed, ok := result.(Res)
+	if !ok {
+		return zero, nil
+	}
+	return typed, nil
+}
+
+// handlerName derives a use case name such as "CreateUser" from the handler type
+func handlerName(handler interface{}) string {
+	handlerType := reflect.TypeOf(handler)
+	for handlerType.Kind() == reflect.Ptr {
+		handlerType = handlerType.Elem()
+	}
+	return strings.TrimSuffix(handlerType.Name(), "Handler")
+}
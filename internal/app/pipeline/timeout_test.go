@@ -0,0 +1,73 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"shadow-id/pkg/errors"
+)
+
+// recordingUnitOfWork reports whether its transactions committed
+type recordingUnitOfWork struct {
+	commits   int
+	rollbacks int
+}
+
+func (u *recordingUnitOfWork) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if err := fn(ctx); err != nil {
+		u.rollbacks++
+		return err
+	}
+	u.commits++
+	return nil
+}
+
+type slowCommand struct{}
+
+// slowHandler succeeds after a delay without observing its context
+type slowHandler struct {
+	delay time.Duration
+}
+
+func (h slowHandler) Handle(ctx context.Context, cmd slowCommand) (string, error) {
+	time.Sleep(h.delay)
+	return "done", nil
+}
+
+func TestTimedOutCommandIsRolledBack(t *testing.T) {
+	uow := &recordingUnitOfWork{}
+	dispatcher := NewDispatcher(Transaction(uow), Timeout(10*time.Millisecond))
+	handler := Command[slowCommand, string](dispatcher, slowHandler{delay: 50 * time.Millisecond})
+
+	hookRan := false
+	ctx := WithCommitHook(context.Background(), func(ctx context.Context, result interface{}) error {
+		hookRan = true
+		return nil
+	})
+
+	_, err := handler.Handle(ctx, slowCommand{})
+	if !errors.IsTimeoutError(err) {
+		t.Fatalf("Handle() error = %v, want a timeout", err)
+	}
+	if uow.commits != 0 || uow.rollbacks != 1 {
+		t.Errorf("commits = %d, rollbacks = %d, want the command rolled back", uow.commits, uow.rollbacks)
+	}
+	if hookRan {
+		t.Error("commit hook ran for a command that timed out")
+	}
+}
+
+func TestCommandWithinTimeoutCommits(t *testing.T) {
+	uow := &recordingUnitOfWork{}
+	dispatcher := NewDispatcher(Transaction(uow), Timeout(time.Second))
+	handler := Command[slowCommand, string](dispatcher, slowHandler{})
+
+	result, err := handler.Handle(context.Background(), slowCommand{})
+	if err != nil {
+		t.Fatalf("Handle() error = %v", err)
+	}
+	if result != "done" || uow.commits != 1 {
+		t.Errorf("result = %q, commits = %d, want %q committed once", result, uow.commits, "done")
+	}
+}
@@ -0,0 +1,59 @@
+package pipeline
+
+import (
+	"context"
+
+	"shadow-id/internal/domain/repositories"
+)
+
+// CommitHook runs inside a command's transaction after its handler succeeded.
+// An error rolls the command back.
+type CommitHook func(ctx context.Context, result interface{}) error
+
+// commitHooksKey is the context key for the commit hooks
+type commitHooksKey struct{}
+
+// WithCommitHook returns a context carrying a hook to run inside the
+// transaction of the command dispatched with it
+func WithCommitHook(ctx context.Context, hook CommitHook) context.Context {
+	hooks, _ := ctx.Value(commitHooksKey{}).([]CommitHook)
+	hooks = append(append([]CommitHook(nil), hooks...), hook)
+	return context.WithValue(ctx, commitHooksKey{}, hooks)
+}
+
+// Transaction runs every command handler in a unit of work, so the changes a
+// command makes are committed together or not at all. Commit hooks registered
+// by outer middleware run in the same transaction. Queries run without one.
+func Transaction(uow repositories.UnitOfWork) Middleware {
+	return func(next Next) Next {
+		return func(ctx context.Context, req *Request) (interface{}, error) {
+			if req.Kind != KindCommand {
+				return next(ctx, req)
+			}
+
+			// Commands the handler dispatches must not run this command's hooks
+			hooks, _ := ctx.Value(commitHooksKey{}).([]CommitHook)
+			ctx = context.WithValue(ctx, commitHooksKey{}, []CommitHook(nil))
+
+			var result interface{}
+			err := uow.WithinTx(ctx, func(ctx context.Context) error {
+				var err error
+				result, err = next(ctx, req)
+				if err != nil {
+					return err
+				}
+
+				for _, hook := range hooks {
+					if err := hook(ctx, result); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+			return result, nil
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package queries
+
+import (
+	"context"
+
+	"shadow-id/internal/app/auth"
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/services"
+	"shadow-id/pkg/types"
+)
+
+// EvaluatePolicyQuery represents a what-if access request evaluated against
+// the loaded policies without enforcing the outcome
+type EvaluatePolicyQuery struct {
+	SubjectID types.ID                  `json:"subject_id" validate:"required"`
+	Action    string                    `json:"action" validate:"required"`
+	Resource  entities.PolicyAttributes `json:"resource"`
+	Context   entities.PolicyAttributes `json:"context"`
+}
+
+// EvaluatePolicyResult represents the explained decision for the request
+type EvaluatePolicyResult struct {
+	SubjectID types.ID `json:"subject_id"`
+	Action    string   `json:"action"`
+	services.PolicyDecision
+}
+
+// EvaluatePolicyHandler handles the evaluate policy query
+type EvaluatePolicyHandler struct {
+	authorizer *auth.Authorizer
+}
+
+// NewEvaluatePolicyHandler creates a new evaluate policy handler
+func NewEvaluatePolicyHandler(authorizer *auth.Authorizer) *EvaluatePolicyHandler {
+	return &EvaluatePolicyHandler{
+		authorizer: authorizer,
+	}
+}
+
+// RequiredPermission returns the permission needed to execute the query
+func (h *EvaluatePolicyHandler) RequiredPermission() entities.Permission {
+	return entities.PermissionPoliciesEvaluate
+}
+
+// Handle executes the evaluate policy query
+func (h *EvaluatePolicyHandler) Handle(ctx context.Context, query EvaluatePolicyQuery) (*EvaluatePolicyResult, error) {
+	// Evaluate as the subject
+	principal := auth.Principal{UserID: query.SubjectID}
+	decision, err := h.authorizer.Explain(ctx, principal, entities.Permission(query.Action), query.Resource, query.Context)
+	if err != nil {
+		return nil, err
+	}
+
+	// Return result
+	return &EvaluatePolicyResult{
+		SubjectID:      query.SubjectID,
+		Action:         query.Action,
+		PolicyDecision: decision,
+	}, nil
+}
@@ -0,0 +1,60 @@
+package queries
+
+import (
+	"context"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/services"
+	"shadow-id/pkg/errors"
+)
+
+// GetCurrentDeviceQuery represents the query to identify the machine the
+// application runs on
+type GetCurrentDeviceQuery struct{}
+
+// GetCurrentDeviceResult represents the fingerprint of the local machine.
+// Components holds the salted hash of every attribute that could be read.
+type GetCurrentDeviceResult struct {
+	Fingerprint string            `json:"fingerprint"`
+	Components  map[string]string `json:"components"`
+	Platform    string            `json:"platform"`
+	Hostname    string            `json:"hostname"`
+	CollectedAt string            `json:"collected_at"`
+}
+
+// GetCurrentDeviceHandler handles the get current device query
+type GetCurrentDeviceHandler struct {
+	deviceService services.DeviceService
+}
+
+// NewGetCurrentDeviceHandler creates a new get current device handler
+func NewGetCurrentDeviceHandler(deviceService services.DeviceService) *GetCurrentDeviceHandler {
+	return &GetCurrentDeviceHandler{
+		deviceService: deviceService,
+	}
+}
+
+// RequiredPermission returns the permission needed to execute the query. The
+// fingerprint only describes the caller's own machine and reveals no raw
+// attributes, so it is available before anyone signs in.
+func (h *GetCurrentDeviceHandler) RequiredPermission() entities.Permission {
+	return entities.PermissionNone
+}
+
+// Handle executes the get current device query
+func (h *GetCurrentDeviceHandler) Handle(ctx context.Context, query GetCurrentDeviceQuery) (*GetCurrentDeviceResult, error) {
+	// Fingerprint the machine
+	fingerprint, err := h.deviceService.CurrentFingerprint(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fingerprint device")
+	}
+
+	// Return result
+	return &GetCurrentDeviceResult{
+		Fingerprint: fingerprint.Hash,
+		Components:  fingerprint.Components,
+		Platform:    fingerprint.Platform,
+		Hostname:    fingerprint.Hostname,
+		CollectedAt: fingerprint.CollectedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}, nil
+}
@@ -0,0 +1,63 @@
+package queries
+
+import (
+	"context"
+	"time"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/repositories"
+	"shadow-id/pkg/errors"
+)
+
+// GetDeviceActivityQuery represents the query for the number of active and stale devices
+type GetDeviceActivityQuery struct{}
+
+// GetDeviceActivityResult represents the number of active and stale devices.
+// Revoked devices are not counted.
+type GetDeviceActivityResult struct {
+	Active int64 `json:"active"`
+	Stale  int64 `json:"stale"`
+
+	// StaleAfterSeconds is how long a device may go without a heartbeat
+	// before it is marked stale
+	StaleAfterSeconds int64 `json:"stale_after_seconds"`
+}
+
+// GetDeviceActivityHandler handles the get device activity query
+type GetDeviceActivityHandler struct {
+	deviceRepo repositories.DeviceRepository
+	staleAfter time.Duration
+}
+
+// NewGetDeviceActivityHandler creates a new get device activity handler
+func NewGetDeviceActivityHandler(deviceRepo repositories.DeviceRepository, staleAfter time.Duration) *GetDeviceActivityHandler {
+	return &GetDeviceActivityHandler{
+		deviceRepo: deviceRepo,
+		staleAfter: staleAfter,
+	}
+}
+
+// RequiredPermission returns the permission needed to execute the query
+func (h *GetDeviceActivityHandler) RequiredPermission() entities.Permission {
+	return entities.PermissionMetricsRead
+}
+
+// Handle executes the get device activity query
+func (h *GetDeviceActivityHandler) Handle(ctx context.Context, query GetDeviceActivityQuery) (*GetDeviceActivityResult, error) {
+	// Count devices by staleness
+	active, err := h.deviceRepo.CountByStaleness(ctx, false)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to count active devices")
+	}
+	stale, err := h.deviceRepo.CountByStaleness(ctx, true)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to count stale devices")
+	}
+
+	// Return result
+	return &GetDeviceActivityResult{
+		Active:            active,
+		Stale:             stale,
+		StaleAfterSeconds: int64(h.staleAfter.Seconds()),
+	}, nil
+}
@@ -0,0 +1,86 @@
+package queries
+
+import (
+	"context"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/repositories"
+	"shadow-id/pkg/errors"
+	"shadow-id/pkg/types"
+)
+
+// GetDeviceQuotaQuery represents the query to get a user's device usage against their quota
+type GetDeviceQuotaQuery struct {
+	UserID types.ID `json:"user_id" validate:"required"`
+}
+
+// GetDeviceQuotaResult represents a user's device usage against their quota.
+// Revoked devices are not counted; a limit of zero means unlimited.
+type GetDeviceQuotaResult struct {
+	UserID    types.ID `json:"user_id"`
+	Limit     int      `json:"limit"`
+	Used      int      `json:"used"`
+	Remaining int      `json:"remaining"`
+	Unlimited bool     `json:"unlimited"`
+	Reached   bool     `json:"reached"`
+
+	// EvictsInactive reports whether registering past the limit revokes the
+	// oldest inactive device instead of failing
+	EvictsInactive bool `json:"evicts_inactive"`
+}
+
+// GetDeviceQuotaHandler handles the get device quota query
+type GetDeviceQuotaHandler struct {
+	userRepo    repositories.UserRepository
+	deviceRepo  repositories.DeviceRepository
+	quotaPolicy entities.DeviceQuotaPolicy
+}
+
+// NewGetDeviceQuotaHandler creates a new get device quota handler
+func NewGetDeviceQuotaHandler(
+	userRepo repositories.UserRepository,
+	deviceRepo repositories.DeviceRepository,
+	quotaPolicy entities.DeviceQuotaPolicy,
+) *GetDeviceQuotaHandler {
+	return &GetDeviceQuotaHandler{
+		userRepo:    userRepo,
+		deviceRepo:  deviceRepo,
+		quotaPolicy: quotaPolicy,
+	}
+}
+
+// RequiredPermission returns the permission needed to execute the query
+func (h *GetDeviceQuotaHandler) RequiredPermission() entities.Permission {
+	return entities.PermissionDevicesRead
+}
+
+// Handle executes the get device quota query
+func (h *GetDeviceQuotaHandler) Handle(ctx context.Context, query GetDeviceQuotaQuery) (*GetDeviceQuotaResult, error) {
+	// Get user from repository
+	user, err := h.userRepo.GetByID(ctx, query.UserID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get user")
+	}
+	if user == nil {
+		return nil, errors.NewNotFoundError("user not found")
+	}
+
+	// Get devices from repository
+	devices, err := h.deviceRepo.ListByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list devices")
+	}
+
+	// Return result
+	quota := h.quotaPolicy.Usage(user, devices)
+	return &GetDeviceQuotaResult{
+		UserID:    user.ID,
+		Limit:     quota.Limit,
+		Used:      quota.Used,
+		Remaining: quota.Remaining(),
+		Unlimited: quota.Unlimited(),
+		Reached:   quota.Reached(),
+
+		EvictsInactive: h.quotaPolicy.EvictOldestInactive,
+	}, nil
+}
@@ -0,0 +1,41 @@
+package queries
+
+import (
+	"context"
+
+	"shadow-id/internal/app/pipeline"
+	"shadow-id/internal/domain/entities"
+)
+
+// GetHandlerMetricsQuery represents the query for command and query handler metrics
+type GetHandlerMetricsQuery struct{}
+
+// GetHandlerMetricsResult represents per-handler call statistics
+type GetHandlerMetricsResult struct {
+	Handlers []pipeline.HandlerMetrics `json:"handlers"`
+}
+
+// GetHandlerMetricsHandler handles the get handler metrics query
+type GetHandlerMetricsHandler struct {
+	metrics *pipeline.Metrics
+}
+
+// NewGetHandlerMetricsHandler creates a new get handler metrics handler
+func NewGetHandlerMetricsHandler(metrics *pipeline.Metrics) *GetHandlerMetricsHandler {
+	return &GetHandlerMetricsHandler{
+		metrics: metrics,
+	}
+}
+
+// RequiredPermission returns the permission needed to execute the query
+func (h *GetHandlerMetricsHandler) RequiredPermission() entities.Permission {
+	return entities.PermissionMetricsRead
+}
+
+// Handle executes the get handler metrics query
+func (h *GetHandlerMetricsHandler) Handle(ctx context.Context, query GetHandlerMetricsQuery) (*GetHandlerMetricsResult, error) {
+	// Return result
+	return &GetHandlerMetricsResult{
+		Handlers: h.metrics.Snapshot(),
+	}, nil
+}
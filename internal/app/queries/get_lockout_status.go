@@ -0,0 +1,70 @@
+package queries
+
+import (
+	"context"
+	"time"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/repositories"
+	"shadow-id/pkg/errors"
+	"shadow-id/pkg/types"
+)
+
+// GetLockoutStatusQuery represents the query to get failed-attempt state for an account
+type GetLockoutStatusQuery struct {
+	UserID types.ID `json:"user_id" validate:"required"`
+}
+
+// GetLockoutStatusResult represents an account's failed-attempt state
+type GetLockoutStatusResult struct {
+	UserID         types.ID `json:"user_id"`
+	FailedAttempts int      `json:"failed_attempts"`
+	Locked         bool     `json:"locked"`
+	LockedUntil    string   `json:"locked_until,omitempty"`
+	NextAttemptAt  string   `json:"next_attempt_at,omitempty"`
+}
+
+// GetLockoutStatusHandler handles the get lockout status query
+type GetLockoutStatusHandler struct {
+	throttleRepo repositories.LoginThrottleRepository
+}
+
+// NewGetLockoutStatusHandler creates a new get lockout status handler
+func NewGetLockoutStatusHandler(throttleRepo repositories.LoginThrottleRepository) *GetLockoutStatusHandler {
+	return &GetLockoutStatusHandler{
+		throttleRepo: throttleRepo,
+	}
+}
+
+// RequiredPermission returns the permission needed to execute the query
+func (h *GetLockoutStatusHandler) RequiredPermission() entities.Permission {
+	return entities.PermissionLockoutsRead
+}
+
+// Handle executes the get lockout status query
+func (h *GetLockoutStatusHandler) Handle(ctx context.Context, query GetLockoutStatusQuery) (*GetLockoutStatusResult, error) {
+	// Get throttle from repository
+	throttle, err := h.throttleRepo.Get(ctx, entities.ThrottleScopeAccount, query.UserID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get lockout status")
+	}
+
+	// Return result
+	result := &GetLockoutStatusResult{
+		UserID: query.UserID,
+	}
+	if throttle == nil {
+		return result, nil
+	}
+
+	now := time.Now()
+	result.FailedAttempts = throttle.FailedAttempts
+	result.Locked = throttle.IsLocked(now)
+	if result.Locked {
+		result.LockedUntil = throttle.LockedUntil.Format("2006-01-02T15:04:05Z07:00")
+	}
+	if now.Before(throttle.NextAttemptAt) {
+		result.NextAttemptAt = throttle.NextAttemptAt.Format("2006-01-02T15:04:05Z07:00")
+	}
+	return result, nil
+}
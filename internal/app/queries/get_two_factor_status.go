@@ -0,0 +1,76 @@
+package queries
+
+import (
+	"context"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/repositories"
+	"shadow-id/pkg/errors"
+	"shadow-id/pkg/types"
+)
+
+// GetTwoFactorStatusQuery represents the query to get a user's two-factor status
+type GetTwoFactorStatusQuery struct {
+	UserID types.ID `json:"user_id" validate:"required"`
+}
+
+// GetTwoFactorStatusResult represents a user's two-factor status
+type GetTwoFactorStatusResult struct {
+	UserID                 types.ID `json:"user_id"`
+	Enrolled               bool     `json:"enrolled"`
+	Enabled                bool     `json:"enabled"`
+	Required               bool     `json:"required"`
+	RemainingRecoveryCodes int      `json:"remaining_recovery_codes"`
+}
+
+// GetTwoFactorStatusHandler handles the get two-factor status query
+type GetTwoFactorStatusHandler struct {
+	userRepo      repositories.UserRepository
+	twoFactorRepo repositories.TwoFactorRepository
+}
+
+// NewGetTwoFactorStatusHandler creates a new get two-factor status handler
+func NewGetTwoFactorStatusHandler(
+	userRepo repositories.UserRepository,
+	twoFactorRepo repositories.TwoFactorRepository,
+) *GetTwoFactorStatusHandler {
+	return &GetTwoFactorStatusHandler{
+		userRepo:      userRepo,
+		twoFactorRepo: twoFactorRepo,
+	}
+}
+
+// RequiredPermission returns the permission needed to execute the query
+func (h *GetTwoFactorStatusHandler) RequiredPermission() entities.Permission {
+	return entities.PermissionUsersRead
+}
+
+// Handle executes the get two-factor status query
+func (h *GetTwoFactorStatusHandler) Handle(ctx context.Context, query GetTwoFactorStatusQuery) (*GetTwoFactorStatusResult, error) {
+	// Get user from repository
+	user, err := h.userRepo.GetByID(ctx, query.UserID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get user")
+	}
+	if user == nil {
+		return nil, errors.NewNotFoundError("user not found")
+	}
+
+	// Get enrollment from repository
+	twoFactor, err := h.twoFactorRepo.GetByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get two-factor enrollment")
+	}
+
+	// Return result
+	result := &GetTwoFactorStatusResult{
+		UserID:   user.ID,
+		Required: user.TwoFactorRequired,
+	}
+	if twoFactor != nil {
+		result.Enrolled = true
+		result.Enabled = twoFactor.Confirmed
+		result.RemainingRecoveryCodes = twoFactor.RemainingRecoveryCodes()
+	}
+	return result, nil
+}
@@ -3,55 +3,87 @@ package queries
 import (
 	"context"
 
+	"shadow-id/internal/domain/entities"
 	"shadow-id/internal/domain/repositories"
 	"shadow-id/pkg/errors"
 	"shadow-id/pkg/types"
 )
 
-// GetUserQuery represents the query to get a user
+// GetUserQuery represents the query to get a user. A soft-deleted user is
+// only found if IncludeDeleted is set.
 type GetUserQuery struct {
-	ID types.ID `json:"id" validate:"required"`
+	ID             types.ID `json:"id" validate:"required"`
+	IncludeDeleted bool     `json:"include_deleted"`
 }
 
 // GetUserResult represents the result of getting a user
 type GetUserResult struct {
-	ID        types.ID `json:"id"`
-	Name      string   `json:"name"`
-	Email     string   `json:"email"`
-	CreatedAt string   `json:"created_at"`
-	UpdatedAt string   `json:"updated_at"`
+	ID                types.ID `json:"id"`
+	Name              string   `json:"name"`
+	Email             string   `json:"email"`
+	Roles             []string `json:"roles"`
+	TwoFactorRequired bool     `json:"two_factor_required"`
+	DeviceCount       int      `json:"device_count"`
+	LastLoginAt       string   `json:"last_login_at,omitempty"`
+	CreatedAt         string   `json:"created_at"`
+	UpdatedAt         string   `json:"updated_at"`
+	DeletedAt         string   `json:"deleted_at,omitempty"`
+	Version           int64    `json:"version"`
 }
 
-// GetUserHandler handles the get user query
+// GetUserHandler handles the get user query. Users are read from the user
+// summary read model, which is updated from domain events and may briefly lag
+// behind the latest change.
 type GetUserHandler struct {
-	userRepo repositories.UserRepository
+	summaryRepo repositories.UserSummaryRepository
 }
 
 // NewGetUserHandler creates a new get user handler
-func NewGetUserHandler(userRepo repositories.UserRepository) *GetUserHandler {
+func NewGetUserHandler(summaryRepo repositories.UserSummaryRepository) *GetUserHandler {
 	return &GetUserHandler{
-		userRepo: userRepo,
+		summaryRepo: summaryRepo,
 	}
 }
 
+// RequiredPermission returns the permission needed to execute the query
+func (h *GetUserHandler) RequiredPermission() entities.Permission {
+	return entities.PermissionUsersRead
+}
+
 // Handle executes the get user query
 func (h *GetUserHandler) Handle(ctx context.Context, query GetUserQuery) (*GetUserResult, error) {
-	// Get user from repository
-	user, err := h.userRepo.GetByID(ctx, query.ID)
+	// Get user from read model
+	summary, err := h.summaryRepo.GetByID(ctx, query.ID)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get user")
 	}
-	
-	if user == nil {
+
+	if summary == nil || (summary.IsDeleted() && !query.IncludeDeleted) {
 		return nil, errors.NewNotFoundError("user not found")
 	}
-	
+
 	// Return result
-	return &GetUserResult{
-		ID:        user.ID,
-		Name:      user.Name,
-		Email:     user.Email,
-		CreatedAt: user.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		UpdatedAt: user.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
-	}, nil
+	return newGetUserResult(summary), nil
+}
+
+// newGetUserResult converts a user summary into a result
+func newGetUserResult(summary *entities.UserSummary) *GetUserResult {
+	result := &GetUserResult{
+		ID:                summary.ID,
+		Name:              summary.Name,
+		Email:             summary.Email,
+		Roles:             summary.Roles,
+		TwoFactorRequired: summary.TwoFactorRequired,
+		DeviceCount:       summary.DeviceCount(),
+		CreatedAt:         summary.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt:         summary.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		Version:           summary.ProfileVersion,
+	}
+	if summary.LastLoginAt != nil {
+		result.LastLoginAt = summary.LastLoginAt.Format("2006-01-02T15:04:05Z07:00")
+	}
+	if summary.DeletedAt != nil {
+		result.DeletedAt = summary.DeletedAt.Format("2006-01-02T15:04:05Z07:00")
+	}
+	return result
 }
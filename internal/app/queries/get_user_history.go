@@ -0,0 +1,132 @@
+package queries
+
+import (
+	"context"
+	"encoding/json"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/repositories"
+	"shadow-id/pkg/errors"
+	"shadow-id/pkg/types"
+)
+
+// GetUserHistoryQuery represents the query to replay a user's event history.
+// Version limits the replay to the first events of the stream; zero replays all of them.
+type GetUserHistoryQuery struct {
+	UserID  types.ID `json:"user_id" validate:"required"`
+	Version int64    `json:"version"`
+}
+
+// UserHistoryEvent represents a single event in a user's history
+type UserHistoryEvent struct {
+	Version    int64           `json:"version"`
+	Name       string          `json:"name"`
+	OccurredAt string          `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// GetUserHistoryResult represents a user's replayed history and the state it
+// produced. Purged users have no history left to replay.
+type GetUserHistoryResult struct {
+	UserID  types.ID           `json:"user_id"`
+	Version int64              `json:"version"`
+	Events  []UserHistoryEvent `json:"events"`
+	State   *UserStateResult   `json:"state"`
+}
+
+// UserStateResult represents the state of a user rebuilt from its events
+type UserStateResult struct {
+	ID                types.ID `json:"id"`
+	Name              string   `json:"name"`
+	Email             string   `json:"email"`
+	Roles             []string `json:"roles"`
+	TwoFactorRequired bool     `json:"two_factor_required"`
+	LastLoginAt       string   `json:"last_login_at,omitempty"`
+	CreatedAt         string   `json:"created_at"`
+	UpdatedAt         string   `json:"updated_at"`
+	DeletedAt         string   `json:"deleted_at,omitempty"`
+	Version           int64    `json:"version"`
+}
+
+// GetUserHistoryHandler handles the get user history query
+type GetUserHistoryHandler struct {
+	eventStore repositories.EventStore
+}
+
+// NewGetUserHistoryHandler creates a new get user history handler. The event
+// store is nil when users are not event sourced, in which case the query fails.
+func NewGetUserHistoryHandler(eventStore repositories.EventStore) *GetUserHistoryHandler {
+	return &GetUserHistoryHandler{
+		eventStore: eventStore,
+	}
+}
+
+// RequiredPermission returns the permission needed to execute the query
+func (h *GetUserHistoryHandler) RequiredPermission() entities.Permission {
+	return entities.PermissionUsersRead
+}
+
+// Handle executes the get user history query
+func (h *GetUserHistoryHandler) Handle(ctx context.Context, query GetUserHistoryQuery) (*GetUserHistoryResult, error) {
+	if h.eventStore == nil {
+		return nil, errors.NewValidationError("user history is only kept when users are event sourced")
+	}
+	if query.Version < 0 {
+		return nil, errors.NewValidationError("version must not be negative")
+	}
+
+	// Load the stream
+	stored, err := h.eventStore.Load(ctx, query.UserID, 0)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load user history")
+	}
+	if len(stored) == 0 {
+		return nil, errors.NewNotFoundError("user not found")
+	}
+	if query.Version > 0 && query.Version < int64(len(stored)) {
+		stored = stored[:query.Version]
+	}
+
+	// Replay the events
+	user := &entities.User{}
+	history := make([]UserHistoryEvent, len(stored))
+	for i, storedEvent := range stored {
+		event, err := storedEvent.Event()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decode user event").
+				WithDetail("version", storedEvent.Version)
+		}
+		user.Apply(event)
+
+		history[i] = UserHistoryEvent{
+			Version:    storedEvent.Version,
+			Name:       storedEvent.EventName,
+			OccurredAt: storedEvent.OccurredAt.Format("2006-01-02T15:04:05Z07:00"),
+			Data:       json.RawMessage(storedEvent.Payload),
+		}
+	}
+
+	// Return result
+	result := &GetUserHistoryResult{
+		UserID:  query.UserID,
+		Version: stored[len(stored)-1].Version,
+		Events:  history,
+		State: &UserStateResult{
+			ID:                user.ID,
+			Name:              user.Name,
+			Email:             user.Email,
+			Roles:             user.Roles,
+			TwoFactorRequired: user.TwoFactorRequired,
+			CreatedAt:         user.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+			UpdatedAt:         user.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+			Version:           user.Version,
+		},
+	}
+	if user.LastLoginAt != nil {
+		result.State.LastLoginAt = user.LastLoginAt.Format("2006-01-02T15:04:05Z07:00")
+	}
+	if user.DeletedAt != nil {
+		result.State.DeletedAt = user.DeletedAt.Format("2006-01-02T15:04:05Z07:00")
+	}
+	return result, nil
+}
@@ -0,0 +1,131 @@
+package queries
+
+import (
+	"context"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/repositories"
+	"shadow-id/pkg/errors"
+	"shadow-id/pkg/types"
+)
+
+// defaultTimelinePageSize is the page size used when a query does not set one
+const defaultTimelinePageSize = 50
+
+// GetUserTimelineQuery represents the query for what a user did from which
+// device. Entries can be narrowed to a device and to actions.
+type GetUserTimelineQuery struct {
+	UserID   types.ID `json:"user_id" validate:"required"`
+	DeviceID types.ID `json:"device_id"`
+	Actions  []string `json:"actions" validate:"max=10"`
+	Limit    int      `json:"limit" validate:"min=0,max=500"`
+	Offset   int      `json:"offset" validate:"min=0"`
+}
+
+// TimelineEntryResult represents a single timeline entry. The device name is
+// empty if the entry is not bound to a device or the device was deleted.
+type TimelineEntryResult struct {
+	ID         types.ID          `json:"id"`
+	Action     string            `json:"action"`
+	DeviceID   types.ID          `json:"device_id,omitempty"`
+	DeviceName string            `json:"device_name,omitempty"`
+	Details    map[string]string `json:"details,omitempty"`
+	OccurredAt string            `json:"occurred_at"`
+}
+
+// GetUserTimelineResult represents a page of a user's timeline, newest first
+type GetUserTimelineResult struct {
+	UserID  types.ID              `json:"user_id"`
+	Entries []TimelineEntryResult `json:"entries"`
+	Total   int64                 `json:"total"`
+	Limit   int                   `json:"limit"`
+	Offset  int                   `json:"offset"`
+}
+
+// GetUserTimelineHandler handles the get user timeline query
+type GetUserTimelineHandler struct {
+	userRepo     repositories.UserRepository
+	deviceRepo   repositories.DeviceRepository
+	timelineRepo repositories.UserTimelineRepository
+}
+
+// NewGetUserTimelineHandler creates a new get user timeline handler
+func NewGetUserTimelineHandler(
+	userRepo repositories.UserRepository,
+	deviceRepo repositories.DeviceRepository,
+	timelineRepo repositories.UserTimelineRepository,
+) *GetUserTimelineHandler {
+	return &GetUserTimelineHandler{
+		userRepo:     userRepo,
+		deviceRepo:   deviceRepo,
+		timelineRepo: timelineRepo,
+	}
+}
+
+// RequiredPermission returns the permission needed to execute the query
+func (h *GetUserTimelineHandler) RequiredPermission() entities.Permission {
+	return entities.PermissionAuditRead
+}
+
+// Handle executes the get user timeline query
+func (h *GetUserTimelineHandler) Handle(ctx context.Context, query GetUserTimelineQuery) (*GetUserTimelineResult, error) {
+	filter := entities.TimelineFilter{DeviceID: query.DeviceID}
+	for _, action := range query.Actions {
+		if !entities.TimelineAction(action).IsValid() {
+			return nil, errors.NewValidationError("unknown timeline action").WithDetail("action", action)
+		}
+		filter.Actions = append(filter.Actions, entities.TimelineAction(action))
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultTimelinePageSize
+	}
+
+	// Get user from repository
+	user, err := h.userRepo.GetByID(ctx, query.UserID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get user")
+	}
+	if user == nil {
+		return nil, errors.NewNotFoundError("user not found")
+	}
+
+	// Load the page of entries and the devices they name
+	entries, err := h.timelineRepo.List(ctx, user.ID, filter, limit, query.Offset)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list timeline entries")
+	}
+	total, err := h.timelineRepo.Count(ctx, user.ID, filter)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to count timeline entries")
+	}
+	devices, err := h.deviceRepo.ListByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list devices")
+	}
+	deviceNames := make(map[types.ID]string, len(devices))
+	for _, device := range devices {
+		deviceNames[device.ID] = device.Name
+	}
+
+	// Return result
+	results := make([]TimelineEntryResult, len(entries))
+	for i, entry := range entries {
+		results[i] = TimelineEntryResult{
+			ID:         entry.ID,
+			Action:     string(entry.Action),
+			DeviceID:   entry.DeviceID,
+			DeviceName: deviceNames[entry.DeviceID],
+			Details:    entry.Details,
+			OccurredAt: entry.OccurredAt.Format("2006-01-02T15:04:05Z07:00"),
+		}
+	}
+	return &GetUserTimelineResult{
+		UserID:  user.ID,
+		Entries: results,
+		Total:   total,
+		Limit:   limit,
+		Offset:  query.Offset,
+	}, nil
+}
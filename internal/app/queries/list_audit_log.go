@@ -0,0 +1,116 @@
+package queries
+
+import (
+	"context"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/repositories"
+	"shadow-id/pkg/errors"
+	"shadow-id/pkg/types"
+)
+
+// defaultAuditPageSize is the page size used when a query does not set one
+const defaultAuditPageSize = 100
+
+// ListAuditLogQuery represents the query to list audit entries. Entries can be
+// filtered by the entity acted on or by the user who acted, but not both.
+// Actions of principals without a user are attributed to their actor name,
+// such as "system".
+type ListAuditLogQuery struct {
+	TargetID types.ID `json:"target_id"`
+	ActorID  types.ID `json:"actor_id"`
+	Limit    int      `json:"limit" validate:"max=1000"`
+	Offset   int      `json:"offset"`
+}
+
+// AuditEntryResult represents a single audit entry
+type AuditEntryResult struct {
+	ID            types.ID               `json:"id"`
+	Sequence      int64                  `json:"sequence"`
+	ActorID       types.ID               `json:"actor_id,omitempty"`
+	Action        string                 `json:"action"`
+	Target        entities.AuditTarget   `json:"target"`
+	Changes       []entities.AuditChange `json:"changes,omitempty"`
+	Outcome       entities.AuditOutcome  `json:"outcome"`
+	Error         string                 `json:"error,omitempty"`
+	CorrelationID string                 `json:"correlation_id,omitempty"`
+	OccurredAt    string                 `json:"occurred_at"`
+	Hash          string                 `json:"hash"`
+}
+
+// ListAuditLogResult represents the result of listing audit entries
+type ListAuditLogResult struct {
+	Entries []AuditEntryResult `json:"entries"`
+	Limit   int                `json:"limit"`
+	Offset  int                `json:"offset"`
+}
+
+// ListAuditLogHandler handles the list audit log query
+type ListAuditLogHandler struct {
+	auditRepo repositories.AuditRepository
+}
+
+// NewListAuditLogHandler creates a new list audit log handler
+func NewListAuditLogHandler(auditRepo repositories.AuditRepository) *ListAuditLogHandler {
+	return &ListAuditLogHandler{
+		auditRepo: auditRepo,
+	}
+}
+
+// RequiredPermission returns the permission needed to execute the query
+func (h *ListAuditLogHandler) RequiredPermission() entities.Permission {
+	return entities.PermissionAuditRead
+}
+
+// Handle executes the list audit log query
+func (h *ListAuditLogHandler) Handle(ctx context.Context, query ListAuditLogQuery) (*ListAuditLogResult, error) {
+	if !query.TargetID.IsEmpty() && !query.ActorID.IsEmpty() {
+		return nil, errors.NewValidationError("filter by target or by actor, not both")
+	}
+	if query.Offset < 0 {
+		return nil, errors.NewValidationError("offset must not be negative")
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultAuditPageSize
+	}
+
+	// Load entries
+	var entries []*entities.AuditEntry
+	var err error
+	switch {
+	case !query.TargetID.IsEmpty():
+		entries, err = h.auditRepo.ListByTarget(ctx, query.TargetID, limit, query.Offset)
+	case !query.ActorID.IsEmpty():
+		entries, err = h.auditRepo.ListByActor(ctx, query.ActorID, limit, query.Offset)
+	default:
+		entries, err = h.auditRepo.List(ctx, limit, query.Offset)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list audit entries")
+	}
+
+	// Return result
+	results := make([]AuditEntryResult, len(entries))
+	for i, entry := range entries {
+		results[i] = AuditEntryResult{
+			ID:            entry.ID,
+			Sequence:      entry.Sequence,
+			ActorID:       entry.ActorID,
+			Action:        entry.Action,
+			Target:        entry.Target,
+			Changes:       entry.Changes,
+			Outcome:       entry.Outcome,
+			Error:         entry.Error,
+			CorrelationID: entry.CorrelationID,
+			OccurredAt:    entry.OccurredAt.Format("2006-01-02T15:04:05Z07:00"),
+			Hash:          entry.Hash,
+		}
+	}
+	return &ListAuditLogResult{
+		Entries: results,
+		Limit:   limit,
+		Offset:  query.Offset,
+	}, nil
+}
@@ -0,0 +1,83 @@
+package queries
+
+import (
+	"context"
+	"encoding/base64"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/repositories"
+	"shadow-id/pkg/errors"
+	"shadow-id/pkg/types"
+)
+
+// ListPasskeysQuery represents the query to list a user's passkeys
+type ListPasskeysQuery struct {
+	UserID types.ID `json:"user_id" validate:"required"`
+}
+
+// PasskeyResult represents a registered passkey
+type PasskeyResult struct {
+	ID                types.ID `json:"id"`
+	DeviceID          types.ID `json:"device_id,omitempty"`
+	Name              string   `json:"name"`
+	CredentialID      string   `json:"credential_id"`
+	AttestationFormat string   `json:"attestation_format"`
+	SignCount         uint32   `json:"sign_count"`
+	CloneWarning      bool     `json:"clone_warning"`
+	CreatedAt         string   `json:"created_at"`
+	LastUsedAt        string   `json:"last_used_at,omitempty"`
+}
+
+// ListPasskeysResult represents the result of listing passkeys
+type ListPasskeysResult struct {
+	UserID   types.ID        `json:"user_id"`
+	Passkeys []PasskeyResult `json:"passkeys"`
+}
+
+// ListPasskeysHandler handles the list passkeys query
+type ListPasskeysHandler struct {
+	credentialRepo repositories.WebAuthnCredentialRepository
+}
+
+// NewListPasskeysHandler creates a new list passkeys handler
+func NewListPasskeysHandler(credentialRepo repositories.WebAuthnCredentialRepository) *ListPasskeysHandler {
+	return &ListPasskeysHandler{
+		credentialRepo: credentialRepo,
+	}
+}
+
+// RequiredPermission returns the permission needed to execute the query
+func (h *ListPasskeysHandler) RequiredPermission() entities.Permission {
+	return entities.PermissionPasskeysRead
+}
+
+// Handle executes the list passkeys query
+func (h *ListPasskeysHandler) Handle(ctx context.Context, query ListPasskeysQuery) (*ListPasskeysResult, error) {
+	// Get credentials from repository
+	credentials, err := h.credentialRepo.ListByUserID(ctx, query.UserID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list credentials")
+	}
+
+	// Return result
+	passkeys := make([]PasskeyResult, len(credentials))
+	for i, credential := range credentials {
+		passkeys[i] = PasskeyResult{
+			ID:                credential.ID,
+			DeviceID:          credential.DeviceID,
+			Name:              credential.Name,
+			CredentialID:      base64.RawURLEncoding.EncodeToString(credential.CredentialID),
+			AttestationFormat: credential.AttestationFormat,
+			SignCount:         credential.SignCount,
+			CloneWarning:      credential.CloneWarning,
+			CreatedAt:         credential.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		}
+		if credential.LastUsedAt != nil {
+			passkeys[i].LastUsedAt = credential.LastUsedAt.Format("2006-01-02T15:04:05Z07:00")
+		}
+	}
+	return &ListPasskeysResult{
+		UserID:   query.UserID,
+		Passkeys: passkeys,
+	}, nil
+}
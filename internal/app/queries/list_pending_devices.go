@@ -0,0 +1,78 @@
+package queries
+
+import (
+	"context"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/repositories"
+	"shadow-id/pkg/errors"
+)
+
+// defaultDevicePageSize is the page size used when a query does not set one
+const defaultDevicePageSize = 50
+
+// ListPendingDevicesQuery represents the query to list devices awaiting approval
+type ListPendingDevicesQuery struct {
+	Limit  int `json:"limit" validate:"max=500"`
+	Offset int `json:"offset"`
+}
+
+// ListPendingDevicesResult represents a page of devices awaiting approval,
+// oldest first
+type ListPendingDevicesResult struct {
+	Devices []DeviceResult `json:"devices"`
+	Total   int64          `json:"total"`
+	Limit   int            `json:"limit"`
+	Offset  int            `json:"offset"`
+}
+
+// ListPendingDevicesHandler handles the list pending devices query
+type ListPendingDevicesHandler struct {
+	deviceRepo repositories.DeviceRepository
+}
+
+// NewListPendingDevicesHandler creates a new list pending devices handler
+func NewListPendingDevicesHandler(deviceRepo repositories.DeviceRepository) *ListPendingDevicesHandler {
+	return &ListPendingDevicesHandler{
+		deviceRepo: deviceRepo,
+	}
+}
+
+// RequiredPermission returns the permission needed to execute the query. Only
+// those who may approve devices see the approval queue.
+func (h *ListPendingDevicesHandler) RequiredPermission() entities.Permission {
+	return entities.PermissionDevicesApprove
+}
+
+// Handle executes the list pending devices query
+func (h *ListPendingDevicesHandler) Handle(ctx context.Context, query ListPendingDevicesQuery) (*ListPendingDevicesResult, error) {
+	if query.Offset < 0 {
+		return nil, errors.NewValidationError("offset must not be negative")
+	}
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultDevicePageSize
+	}
+
+	// Get devices from repository
+	devices, err := h.deviceRepo.ListByTrustState(ctx, entities.DeviceTrustPending, limit, query.Offset)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list devices")
+	}
+	total, err := h.deviceRepo.CountByTrustState(ctx, entities.DeviceTrustPending)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to count devices")
+	}
+
+	// Return result
+	results := make([]DeviceResult, len(devices))
+	for i, device := range devices {
+		results[i] = newDeviceResult(device)
+	}
+	return &ListPendingDevicesResult{
+		Devices: results,
+		Total:   total,
+		Limit:   limit,
+		Offset:  query.Offset,
+	}, nil
+}
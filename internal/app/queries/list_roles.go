@@ -0,0 +1,69 @@
+package queries
+
+import (
+	"context"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/repositories"
+	"shadow-id/pkg/errors"
+)
+
+// ListRolesQuery represents the query to list the available roles
+type ListRolesQuery struct{}
+
+// RoleResult represents a role and its permissions
+type RoleResult struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+	BuiltIn     bool     `json:"built_in"`
+}
+
+// ListRolesResult represents the result of listing roles
+type ListRolesResult struct {
+	Roles []RoleResult `json:"roles"`
+}
+
+// ListRolesHandler handles the list roles query
+type ListRolesHandler struct {
+	roleRepo repositories.RoleRepository
+}
+
+// NewListRolesHandler creates a new list roles handler
+func NewListRolesHandler(roleRepo repositories.RoleRepository) *ListRolesHandler {
+	return &ListRolesHandler{
+		roleRepo: roleRepo,
+	}
+}
+
+// RequiredPermission returns the permission needed to execute the query
+func (h *ListRolesHandler) RequiredPermission() entities.Permission {
+	return entities.PermissionRolesRead
+}
+
+// Handle executes the list roles query
+func (h *ListRolesHandler) Handle(ctx context.Context, query ListRolesQuery) (*ListRolesResult, error) {
+	// Get roles from repository
+	roles, err := h.roleRepo.List(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list roles")
+	}
+
+	// Return result
+	results := make([]RoleResult, len(roles))
+	for i, role := range roles {
+		permissions := make([]string, len(role.Permissions))
+		for j, permission := range role.Permissions {
+			permissions[j] = string(permission)
+		}
+		results[i] = RoleResult{
+			Name:        role.Name,
+			Description: role.Description,
+			Permissions: permissions,
+			BuiltIn:     role.BuiltIn,
+		}
+	}
+	return &ListRolesResult{
+		Roles: results,
+	}, nil
+}
@@ -0,0 +1,110 @@
+package queries
+
+import (
+	"context"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/repositories"
+	"shadow-id/pkg/errors"
+	"shadow-id/pkg/types"
+)
+
+// ListUserDevicesQuery represents the query to list a user's devices
+type ListUserDevicesQuery struct {
+	UserID types.ID `json:"user_id" validate:"required"`
+}
+
+// DeviceResult represents a registered device
+type DeviceResult struct {
+	ID        types.ID `json:"id"`
+	UserID    types.ID `json:"user_id"`
+	Name      string   `json:"name"`
+	Platform  string   `json:"platform"`
+	CreatedAt string   `json:"created_at"`
+	UpdatedAt string   `json:"updated_at"`
+	Version   int64    `json:"version"`
+
+	// TrustState is where the device stands in the approval workflow
+	TrustState     string   `json:"trust_state"`
+	TrustChangedAt string   `json:"trust_changed_at"`
+	ApprovedBy     types.ID `json:"approved_by,omitempty"`
+
+	// LastSeenAt, AppVersion and LastIP come from the latest heartbeat;
+	// LastSeenAt is empty for devices that never sent one
+	LastSeenAt string `json:"last_seen_at,omitempty"`
+	AppVersion string `json:"app_version,omitempty"`
+	LastIP     string `json:"last_ip,omitempty"`
+	Stale      bool   `json:"stale"`
+
+	// Fingerprinted reports whether the device can be recognized by MatchDevice
+	Fingerprinted bool `json:"fingerprinted"`
+}
+
+// ListUserDevicesResult represents the result of listing a user's devices
+type ListUserDevicesResult struct {
+	UserID  types.ID       `json:"user_id"`
+	Devices []DeviceResult `json:"devices"`
+}
+
+// ListUserDevicesHandler handles the list user devices query
+type ListUserDevicesHandler struct {
+	deviceRepo repositories.DeviceRepository
+}
+
+// NewListUserDevicesHandler creates a new list user devices handler
+func NewListUserDevicesHandler(deviceRepo repositories.DeviceRepository) *ListUserDevicesHandler {
+	return &ListUserDevicesHandler{
+		deviceRepo: deviceRepo,
+	}
+}
+
+// RequiredPermission returns the permission needed to execute the query
+func (h *ListUserDevicesHandler) RequiredPermission() entities.Permission {
+	return entities.PermissionDevicesRead
+}
+
+// Handle executes the list user devices query
+func (h *ListUserDevicesHandler) Handle(ctx context.Context, query ListUserDevicesQuery) (*ListUserDevicesResult, error) {
+	// Get devices from repository
+	devices, err := h.deviceRepo.ListByUserID(ctx, query.UserID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list devices")
+	}
+
+	// Return result
+	results := make([]DeviceResult, len(devices))
+	for i, device := range devices {
+		results[i] = newDeviceResult(device)
+	}
+	return &ListUserDevicesResult{
+		UserID:  query.UserID,
+		Devices: results,
+	}, nil
+}
+
+// newDeviceResult creates a device result from a device
+func newDeviceResult(device *entities.Device) DeviceResult {
+	result := DeviceResult{
+		ID:        device.ID,
+		UserID:    device.UserID,
+		Name:      device.Name,
+		Platform:  device.Platform,
+		CreatedAt: device.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt: device.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		Version:   device.ProfileVersion,
+
+		TrustState:     string(device.TrustState),
+		TrustChangedAt: device.TrustChangedAt.Format("2006-01-02T15:04:05Z07:00"),
+		ApprovedBy:     device.ApprovedBy,
+
+		AppVersion: device.AppVersion,
+		LastIP:     device.LastIP,
+		Stale:      device.Stale,
+
+		Fingerprinted: len(device.Fingerprint) > 0,
+	}
+	if !device.LastSeenAt.IsZero() {
+		result.LastSeenAt = device.LastSeenAt.Format("2006-01-02T15:04:05Z07:00")
+	}
+	return result
+}
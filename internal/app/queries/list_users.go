@@ -0,0 +1,79 @@
+package queries
+
+import (
+	"context"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/repositories"
+	"shadow-id/pkg/errors"
+)
+
+// defaultUserPageSize is the page size used when a query does not set one
+const defaultUserPageSize = 50
+
+// ListUsersQuery represents the query to list users. Soft-deleted users are
+// only listed if IncludeDeleted is set.
+type ListUsersQuery struct {
+	Limit          int  `json:"limit" validate:"max=500"`
+	Offset         int  `json:"offset"`
+	IncludeDeleted bool `json:"include_deleted"`
+}
+
+// ListUsersResult represents a page of users
+type ListUsersResult struct {
+	Users  []*GetUserResult `json:"users"`
+	Total  int64            `json:"total"`
+	Limit  int              `json:"limit"`
+	Offset int              `json:"offset"`
+}
+
+// ListUsersHandler handles the list users query. Users are read from the user
+// summary read model.
+type ListUsersHandler struct {
+	summaryRepo repositories.UserSummaryRepository
+}
+
+// NewListUsersHandler creates a new list users handler
+func NewListUsersHandler(summaryRepo repositories.UserSummaryRepository) *ListUsersHandler {
+	return &ListUsersHandler{
+		summaryRepo: summaryRepo,
+	}
+}
+
+// RequiredPermission returns the permission needed to execute the query
+func (h *ListUsersHandler) RequiredPermission() entities.Permission {
+	return entities.PermissionUsersRead
+}
+
+// Handle executes the list users query
+func (h *ListUsersHandler) Handle(ctx context.Context, query ListUsersQuery) (*ListUsersResult, error) {
+	if query.Offset < 0 {
+		return nil, errors.NewValidationError("offset must not be negative")
+	}
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultUserPageSize
+	}
+
+	// Get users from read model
+	summaries, err := h.summaryRepo.List(ctx, limit, query.Offset, query.IncludeDeleted)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list users")
+	}
+	total, err := h.summaryRepo.Count(ctx, query.IncludeDeleted)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to count users")
+	}
+
+	// Return result
+	users := make([]*GetUserResult, len(summaries))
+	for i, summary := range summaries {
+		users[i] = newGetUserResult(summary)
+	}
+	return &ListUsersResult{
+		Users:  users,
+		Total:  total,
+		Limit:  limit,
+		Offset: query.Offset,
+	}, nil
+}
@@ -0,0 +1,25 @@
+package queries
+
+// All returns a zero value of every query. The bus is verified against this
+// list at startup, so a new query must be added here and given a handler.
+func All() []interface{} {
+	return []interface{}{
+		EvaluatePolicyQuery{},
+		GetCurrentDeviceQuery{},
+		GetDeviceActivityQuery{},
+		GetDeviceQuotaQuery{},
+		GetHandlerMetricsQuery{},
+		GetLockoutStatusQuery{},
+		GetTwoFactorStatusQuery{},
+		GetUserHistoryQuery{},
+		GetUserQuery{},
+		GetUserTimelineQuery{},
+		ListAuditLogQuery{},
+		ListPendingDevicesQuery{},
+		ListPasskeysQuery{},
+		ListRolesQuery{},
+		ListUserDevicesQuery{},
+		ListUsersQuery{},
+		VerifyAuditLogQuery{},
+	}
+}
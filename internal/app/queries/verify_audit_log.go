@@ -0,0 +1,72 @@
+package queries
+
+import (
+	"context"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/repositories"
+	"shadow-id/pkg/errors"
+)
+
+// auditVerifyBatchSize is the number of entries loaded at a time while verifying
+const auditVerifyBatchSize = 500
+
+// VerifyAuditLogQuery represents the query to check the audit log for tampering
+type VerifyAuditLogQuery struct{}
+
+// VerifyAuditLogResult represents the outcome of verifying the audit log's hash chain
+type VerifyAuditLogResult struct {
+	Valid   bool  `json:"valid"`
+	Entries int64 `json:"entries"`
+
+	// BrokenAt is the sequence of the first entry that failed verification
+	BrokenAt int64  `json:"broken_at,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// VerifyAuditLogHandler handles the verify audit log query
+type VerifyAuditLogHandler struct {
+	auditRepo repositories.AuditRepository
+}
+
+// NewVerifyAuditLogHandler creates a new verify audit log handler
+func NewVerifyAuditLogHandler(auditRepo repositories.AuditRepository) *VerifyAuditLogHandler {
+	return &VerifyAuditLogHandler{
+		auditRepo: auditRepo,
+	}
+}
+
+// RequiredPermission returns the permission needed to execute the query
+func (h *VerifyAuditLogHandler) RequiredPermission() entities.Permission {
+	return entities.PermissionAuditRead
+}
+
+// Handle executes the verify audit log query
+func (h *VerifyAuditLogHandler) Handle(ctx context.Context, query VerifyAuditLogQuery) (*VerifyAuditLogResult, error) {
+	// Load the whole log in sequence order
+	entries := make([]*entities.AuditEntry, 0)
+	for offset := 0; ; offset += auditVerifyBatchSize {
+		batch, err := h.auditRepo.List(ctx, auditVerifyBatchSize, offset)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list audit entries")
+		}
+		entries = append(entries, batch...)
+		if len(batch) < auditVerifyBatchSize {
+			break
+		}
+	}
+
+	// Walk the hash chain
+	result := &VerifyAuditLogResult{
+		Valid:   true,
+		Entries: int64(len(entries)),
+	}
+	if brokenAt, err := entities.VerifyAuditChain(entries); err != nil {
+		result.Valid = false
+		result.BrokenAt = brokenAt
+		result.Reason = err.Error()
+	}
+
+	// Return result
+	return result, nil
+}
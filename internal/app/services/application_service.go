@@ -1,39 +1,148 @@
 package services
 
 import (
+	"time"
+
+	"shadow-id/internal/app/audit"
+	"shadow-id/internal/app/auth"
 	"shadow-id/internal/app/commands"
+	"shadow-id/internal/app/pipeline"
 	"shadow-id/internal/app/queries"
+	"shadow-id/internal/domain/entities"
 	"shadow-id/internal/domain/repositories"
 	"shadow-id/internal/domain/services"
+	"shadow-id/pkg/logger"
 )
 
-// ApplicationService aggregates all application services
+// ApplicationService exposes the use cases through a command and query bus
 type ApplicationService struct {
-	Commands *CommandHandlers
-	Queries  *QueryHandlers
-}
+	// Bus routes every command and query to its handler
+	Bus *pipeline.Bus
 
-// CommandHandlers aggregates all command handlers
-type CommandHandlers struct {
-	CreateUser *commands.CreateUserHandler
+	// Metrics holds call statistics for every handler
+	Metrics *pipeline.Metrics
 }
 
-// QueryHandlers aggregates all query handlers
-type QueryHandlers struct {
-	GetUser *queries.GetUserHandler
+// Dependencies holds the repositories and domain services the handlers are built from
+type Dependencies struct {
+	UserRepo            repositories.UserRepository
+	UserEventStore      repositories.EventStore // nil unless users are event sourced
+	TwoFactorRepo       repositories.TwoFactorRepository
+	DeviceRepo          repositories.DeviceRepository
+	DeviceChallengeRepo repositories.DeviceChallengeRepository
+	CredentialRepo      repositories.WebAuthnCredentialRepository
+	WebAuthnSessionRepo repositories.WebAuthnSessionRepository
+	LoginThrottleRepo   repositories.LoginThrottleRepository
+	RoleRepo            repositories.RoleRepository
+	AuditRepo           repositories.AuditRepository
+	UserSummaryRepo     repositories.UserSummaryRepository
+	UserTimelineRepo    repositories.UserTimelineRepository
+	UnitOfWork          repositories.UnitOfWork
+
+	UserService         services.UserService
+	TOTPService         services.TOTPService
+	DeviceService       services.DeviceService
+	DeviceKeyService    services.DeviceKeyService
+	WebAuthnService     services.WebAuthnService
+	RateLimiter         services.RateLimiter
+	AuthenticationGuard services.AuthenticationGuard
+	PolicyEngine        services.PolicyEngine
+	ProjectionRebuilder services.ProjectionRebuilder
+
+	Logger         logger.Logger
+	HandlerTimeout time.Duration
+	UserRetention  time.Duration // how long soft-deleted users are kept before they are purged
+
+	FingerprintPolicy  entities.FingerprintMatchPolicy
+	DeviceQuotaPolicy  entities.DeviceQuotaPolicy
+	DeviceChallengeTTL time.Duration // how long a device has to answer a challenge
+	DeviceStaleAfter   time.Duration // how long a device may go without a heartbeat before it is marked stale
 }
 
 // NewApplicationService creates a new application service
-func NewApplicationService(
-	userRepo repositories.UserRepository,
-	userService services.UserService,
-) *ApplicationService {
-	return &ApplicationService{
-		Commands: &CommandHandlers{
-			CreateUser: commands.NewCreateUserHandler(userRepo, userService),
-		},
-		Queries: &QueryHandlers{
-			GetUser: queries.NewGetUserHandler(userRepo),
-		},
+func NewApplicationService(deps Dependencies) (*ApplicationService, error) {
+	authorizer := auth.NewAuthorizer(deps.UserRepo, deps.RoleRepo, deps.PolicyEngine)
+	metrics := pipeline.NewMetrics()
+
+	// Every handler runs through the same middleware chain, outermost first
+	dispatcher := pipeline.NewDispatcher(
+		pipeline.Correlation(),
+		metrics.Middleware(),
+		pipeline.Logging(deps.Logger),
+		audit.Middleware(deps.AuditRepo, audit.NewSnapshotter(deps.UserRepo, deps.DeviceRepo, deps.TwoFactorRepo), deps.Logger),
+		pipeline.Recovery(deps.Logger),
+		pipeline.Validation(),
+		auth.Middleware(authorizer),
+		pipeline.Transaction(deps.UnitOfWork),
+		// Inside the transaction so a command that runs out of time is rolled back
+		pipeline.Timeout(deps.HandlerTimeout),
+	)
+
+	bus := pipeline.NewBus(dispatcher)
+	registerCommands(bus, deps)
+	registerQueries(bus, deps, authorizer, metrics)
+
+	// Refuse to start with a command or query that cannot be dispatched
+	if err := bus.Verify(commands.All(), queries.All()); err != nil {
+		return nil, err
 	}
+
+	return &ApplicationService{
+		Bus:     bus,
+		Metrics: metrics,
+	}, nil
+}
+
+// registerCommands registers a handler for every command
+func registerCommands(bus *pipeline.Bus, deps Dependencies) {
+	pipeline.RegisterCommand(bus, commands.NewCreateUserHandler(deps.UserRepo, deps.DeviceRepo, deps.UnitOfWork, deps.UserService))
+	pipeline.RegisterCommand(bus, commands.NewEnrollTwoFactorHandler(deps.UserRepo, deps.TwoFactorRepo, deps.TOTPService))
+	pipeline.RegisterCommand(bus, commands.NewConfirmTwoFactorHandler(deps.TwoFactorRepo, deps.TOTPService))
+	pipeline.RegisterCommand(bus, commands.NewVerifyTwoFactorHandler(deps.UserRepo, deps.TwoFactorRepo, deps.TOTPService, deps.AuthenticationGuard))
+	pipeline.RegisterCommand(bus, commands.NewRegenerateRecoveryCodesHandler(deps.TwoFactorRepo, deps.TOTPService, deps.AuthenticationGuard))
+	pipeline.RegisterCommand(bus, commands.NewDisableTwoFactorHandler(deps.UserRepo, deps.TwoFactorRepo, deps.TOTPService, deps.AuthenticationGuard))
+	pipeline.RegisterCommand(bus, commands.NewSetTwoFactorRequirementHandler(deps.UserRepo))
+	pipeline.RegisterCommand(bus, commands.NewRegisterDeviceHandler(deps.UserRepo, deps.DeviceRepo, deps.CredentialRepo, deps.UnitOfWork, deps.DeviceQuotaPolicy))
+	pipeline.RegisterCommand(bus, commands.NewBeginPasskeyRegistrationHandler(deps.UserRepo, deps.DeviceRepo, deps.CredentialRepo, deps.WebAuthnSessionRepo, deps.WebAuthnService))
+	pipeline.RegisterCommand(bus, commands.NewFinishPasskeyRegistrationHandler(deps.CredentialRepo, deps.WebAuthnSessionRepo, deps.WebAuthnService))
+	pipeline.RegisterCommand(bus, commands.NewBeginPasskeyLoginHandler(deps.UserRepo, deps.CredentialRepo, deps.WebAuthnSessionRepo, deps.WebAuthnService))
+	pipeline.RegisterCommand(bus, commands.NewFinishPasskeyLoginHandler(deps.UserRepo, deps.TwoFactorRepo, deps.CredentialRepo, deps.DeviceRepo, deps.WebAuthnSessionRepo, deps.WebAuthnService, deps.AuthenticationGuard))
+	pipeline.RegisterCommand(bus, commands.NewSignOutHandler(deps.UserRepo))
+	pipeline.RegisterCommand(bus, commands.NewUnlockAccountHandler(deps.UserRepo, deps.LoginThrottleRepo, deps.RateLimiter))
+	pipeline.RegisterCommand(bus, commands.NewAssignRoleHandler(deps.UserRepo, deps.RoleRepo))
+	pipeline.RegisterCommand(bus, commands.NewRevokeRoleHandler(deps.UserRepo))
+	pipeline.RegisterCommand(bus, commands.NewUpdateUserHandler(deps.UserRepo, deps.UserService))
+	pipeline.RegisterCommand(bus, commands.NewDeleteUserHandler(deps.UserRepo))
+	pipeline.RegisterCommand(bus, commands.NewRestoreUserHandler(deps.UserRepo))
+	pipeline.RegisterCommand(bus, commands.NewPurgeDeletedUsersHandler(deps.UserRepo, deps.DeviceRepo, deps.CredentialRepo, deps.TwoFactorRepo, deps.UserRetention))
+	pipeline.RegisterCommand(bus, commands.NewMatchDeviceHandler(deps.UserRepo, deps.DeviceRepo, deps.FingerprintPolicy))
+	pipeline.RegisterCommand(bus, commands.NewRevokeDeviceHandler(deps.DeviceRepo, deps.CredentialRepo))
+	pipeline.RegisterCommand(bus, commands.NewApproveDeviceHandler(deps.DeviceRepo))
+	pipeline.RegisterCommand(bus, commands.NewSuspendDeviceHandler(deps.DeviceRepo))
+	pipeline.RegisterCommand(bus, commands.NewIssueDeviceChallengeHandler(deps.DeviceRepo, deps.DeviceChallengeRepo, deps.DeviceKeyService, deps.DeviceChallengeTTL))
+	pipeline.RegisterCommand(bus, commands.NewVerifyDeviceChallengeHandler(deps.DeviceRepo, deps.DeviceChallengeRepo, deps.DeviceKeyService))
+	pipeline.RegisterCommand(bus, commands.NewRecordDeviceHeartbeatHandler(deps.DeviceRepo))
+	pipeline.RegisterCommand(bus, commands.NewMarkStaleDevicesHandler(deps.DeviceRepo, deps.DeviceStaleAfter))
+	pipeline.RegisterCommand(bus, commands.NewRebuildProjectionsHandler(deps.ProjectionRebuilder))
+}
+
+// registerQueries registers a handler for every query
+func registerQueries(bus *pipeline.Bus, deps Dependencies, authorizer *auth.Authorizer, metrics *pipeline.Metrics) {
+	pipeline.RegisterQuery(bus, queries.NewGetUserHandler(deps.UserSummaryRepo))
+	pipeline.RegisterQuery(bus, queries.NewListUsersHandler(deps.UserSummaryRepo))
+	pipeline.RegisterQuery(bus, queries.NewGetUserHistoryHandler(deps.UserEventStore))
+	pipeline.RegisterQuery(bus, queries.NewGetUserTimelineHandler(deps.UserRepo, deps.DeviceRepo, deps.UserTimelineRepo))
+	pipeline.RegisterQuery(bus, queries.NewGetTwoFactorStatusHandler(deps.UserRepo, deps.TwoFactorRepo))
+	pipeline.RegisterQuery(bus, queries.NewListUserDevicesHandler(deps.DeviceRepo))
+	pipeline.RegisterQuery(bus, queries.NewListPendingDevicesHandler(deps.DeviceRepo))
+	pipeline.RegisterQuery(bus, queries.NewGetDeviceQuotaHandler(deps.UserRepo, deps.DeviceRepo, deps.DeviceQuotaPolicy))
+	pipeline.RegisterQuery(bus, queries.NewGetDeviceActivityHandler(deps.DeviceRepo, deps.DeviceStaleAfter))
+	pipeline.RegisterQuery(bus, queries.NewGetCurrentDeviceHandler(deps.DeviceService))
+	pipeline.RegisterQuery(bus, queries.NewListPasskeysHandler(deps.CredentialRepo))
+	pipeline.RegisterQuery(bus, queries.NewGetLockoutStatusHandler(deps.LoginThrottleRepo))
+	pipeline.RegisterQuery(bus, queries.NewListRolesHandler(deps.RoleRepo))
+	pipeline.RegisterQuery(bus, queries.NewEvaluatePolicyHandler(authorizer))
+	pipeline.RegisterQuery(bus, queries.NewGetHandlerMetricsHandler(metrics))
+	pipeline.RegisterQuery(bus, queries.NewListAuditLogHandler(deps.AuditRepo))
+	pipeline.RegisterQuery(bus, queries.NewVerifyAuditLogHandler(deps.AuditRepo))
 }
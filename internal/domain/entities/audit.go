@@ -0,0 +1,198 @@
+package entities
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"shadow-id/pkg/types"
+)
+
+// Audit target types
+const (
+	AuditTargetUser       = "user"
+	AuditTargetDevice     = "device"
+	AuditTargetTwoFactor  = "two_factor"
+	AuditTargetCredential = "credential"
+)
+
+// AuditOutcome reports whether an audited command succeeded
+type AuditOutcome string
+
+// Audit outcomes
+const (
+	AuditOutcomeSuccess AuditOutcome = "success"
+	AuditOutcomeFailure AuditOutcome = "failure"
+)
+
+// AuditTarget identifies the entity a command acted on
+type AuditTarget struct {
+	Type string   `json:"type,omitempty"`
+	ID   types.ID `json:"id,omitempty"`
+}
+
+// IsEmpty checks if the target names no entity
+func (t AuditTarget) IsEmpty() bool {
+	return t.ID.IsEmpty()
+}
+
+// AuditChange records how a single field of the target changed
+type AuditChange struct {
+	Field  string      `json:"field"`
+	Before interface{} `json:"before,omitempty"`
+	After  interface{} `json:"after,omitempty"`
+}
+
+// AuditEntry is an append-only record of a command. Entries are hash chained:
+// each hash covers the entry's content and the previous entry's hash, so
+// altering, removing or reordering an entry breaks every later link.
+type AuditEntry struct {
+	ID            types.ID      `json:"id"`
+	Sequence      int64         `json:"sequence"`
+	ActorID       types.ID      `json:"actor_id,omitempty"`
+	Action        string        `json:"action"`
+	Target        AuditTarget   `json:"target"`
+	Changes       []AuditChange `json:"changes,omitempty"`
+	Outcome       AuditOutcome  `json:"outcome"`
+	Error         string        `json:"error,omitempty"`
+	CorrelationID string        `json:"correlation_id,omitempty"`
+	OccurredAt    time.Time     `json:"occurred_at"`
+	PrevHash      string        `json:"prev_hash"`
+	Hash          string        `json:"hash"`
+}
+
+// NewAuditEntry creates an unchained audit entry for an action taken by an
+// actor; an empty actor is the unauthenticated caller
+func NewAuditEntry(actorID types.ID, action, correlationID string) *AuditEntry {
+	return &AuditEntry{
+		ID:            types.NewID(),
+		ActorID:       actorID,
+		Action:        action,
+		CorrelationID: correlationID,
+		OccurredAt:    time.Now().UTC(),
+	}
+}
+
+// Succeed records a successful outcome and the changes it made to the target
+func (e *AuditEntry) Succeed(target AuditTarget, changes []AuditChange) {
+	e.Target = target
+	e.Changes = changes
+	e.Outcome = AuditOutcomeSuccess
+}
+
+// Fail records a failed outcome
+func (e *AuditEntry) Fail(target AuditTarget, cause error) {
+	e.Target = target
+	e.Outcome = AuditOutcomeFailure
+	if cause != nil {
+		e.Error = cause.Error()
+	}
+}
+
+// Chain links the entry after the previous one, which is nil for the first
+// entry, and seals it with its hash
+func (e *AuditEntry) Chain(previous *AuditEntry) {
+	e.Sequence = 1
+	e.PrevHash = ""
+	if previous != nil {
+		e.Sequence = previous.Sequence + 1
+		e.PrevHash = previous.Hash
+	}
+	e.Hash = e.ComputeHash()
+}
+
+// ComputeHash returns the hash of the entry's content and its link to the
+// previous entry. No changes hash the same whether the slice is nil or empty,
+// since copying or storing an entry may turn one into the other.
+func (e *AuditEntry) ComputeHash() string {
+	changes := e.Changes
+	if len(changes) == 0 {
+		changes = nil
+	}
+	content, _ := json.Marshal(struct {
+		ID            types.ID      `json:"id"`
+		Sequence      int64         `json:"sequence"`
+		ActorID       types.ID      `json:"actor_id"`
+		Action        string        `json:"action"`
+		Target        AuditTarget   `json:"target"`
+		Changes       []AuditChange `json:"changes"`
+		Outcome       AuditOutcome  `json:"outcome"`
+		Error         string        `json:"error"`
+		CorrelationID string        `json:"correlation_id"`
+		OccurredAt    string        `json:"occurred_at"`
+		PrevHash      string        `json:"prev_hash"`
+	}{
+		ID:            e.ID,
+		Sequence:      e.Sequence,
+		ActorID:       e.ActorID,
+		Action:        e.Action,
+		Target:        e.Target,
+		Changes:       changes,
+		Outcome:       e.Outcome,
+		Error:         e.Error,
+		CorrelationID: e.CorrelationID,
+		OccurredAt:    e.OccurredAt.UTC().Format(time.RFC3339Nano),
+		PrevHash:      e.PrevHash,
+	})
+
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// VerifyAuditChain checks that entries, ordered by sequence from the start of
+// the log, form an unbroken chain. It returns the sequence of the first entry
+// that fails verification and a description of the failure.
+func VerifyAuditChain(entries []*AuditEntry) (int64, error) {
+	var previous *AuditEntry
+	for _, entry := range entries {
+		expectedSequence, expectedPrevHash := int64(1), ""
+		if previous != nil {
+			expectedSequence, expectedPrevHash = previous.Sequence+1, previous.Hash
+		}
+
+		switch {
+		case entry.Sequence != expectedSequence:
+			return entry.Sequence, fmt.Errorf("%w: expected sequence %d, found %d", ErrAuditChainBroken, expectedSequence, entry.Sequence)
+		case entry.PrevHash != expectedPrevHash:
+			return entry.Sequence, fmt.Errorf("%w: entry %d does not link to its predecessor", ErrAuditChainBroken, entry.Sequence)
+		case entry.Hash != entry.ComputeHash():
+			return entry.Sequence, fmt.Errorf("%w: entry %d was modified", ErrAuditChainBroken, entry.Sequence)
+		}
+		previous = entry
+	}
+	return 0, nil
+}
+
+// DiffAuditSnapshots compares two snapshots of an entity field by field. A nil
+// before snapshot means the entity was created, a nil after snapshot that it
+// was removed.
+func DiffAuditSnapshots(before, after map[string]interface{}) []AuditChange {
+	fields := make([]string, 0, len(before)+len(after))
+	for field := range before {
+		fields = append(fields, field)
+	}
+	for field := range after {
+		if _, seen := before[field]; !seen {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+
+	changes := make([]AuditChange, 0)
+	for _, field := range fields {
+		previous, next := before[field], after[field]
+		if reflect.DeepEqual(previous, next) {
+			continue
+		}
+		changes = append(changes, AuditChange{
+			Field:  field,
+			Before: previous,
+			After:  next,
+		})
+	}
+	return changes
+}
@@ -0,0 +1,232 @@
+package entities
+
+import (
+	"bytes"
+	"maps"
+	"strings"
+	"time"
+
+	"shadow-id/internal/domain/events"
+	"shadow-id/pkg/types"
+)
+
+// DevicePublicKeySize is the length of a device's Ed25519 public key in bytes
+const DevicePublicKeySize = 32
+
+// Device represents a machine registered to a user
+type Device struct {
+	ID        types.ID  `json:"id"`
+	UserID    types.ID  `json:"user_id"`
+	Name      string    `json:"name"`
+	Platform  string    `json:"platform"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// TrustState is where the device stands in the approval workflow;
+	// TrustChangedAt and ApprovedBy describe the latest change to it
+	TrustState     DeviceTrustState `json:"trust_state"`
+	TrustChangedAt time.Time        `json:"trust_changed_at"`
+	ApprovedBy     types.ID         `json:"approved_by,omitempty"`
+
+	// PublicKey is the Ed25519 key the device signs challenges with. The
+	// client generates the key pair and registers only the public key, so
+	// the private half never leaves the device.
+	PublicKey []byte `json:"public_key,omitempty"`
+
+	// LastSeenAt, AppVersion and LastIP come from the latest heartbeat. A
+	// device that has not sent one for too long is marked Stale until it
+	// sends another.
+	LastSeenAt time.Time `json:"last_seen_at"`
+	AppVersion string    `json:"app_version,omitempty"`
+	LastIP     string    `json:"last_ip,omitempty"`
+	Stale      bool      `json:"stale"`
+
+	// Fingerprint holds the salted component hashes of the machine the device
+	// was last recognized as; it is empty for devices registered without one
+	Fingerprint map[string]string `json:"fingerprint,omitempty"`
+
+	// Version counts the changes made to the device; every recorded event
+	// advances it by one. Writes based on an outdated version are rejected.
+	Version int64 `json:"version"`
+
+	// ProfileVersion is the version of the last change that was not activity:
+	// a heartbeat, being marked stale or a fingerprint seen on recognition.
+	// Trust commands check the version callers read against it, so a device
+	// reporting in does not make every earlier read of it stale.
+	ProfileVersion int64 `json:"profile_version"`
+
+	events.Recorder `json:"-"`
+}
+
+// NewDevice creates a new device entity
+func NewDevice(userID types.ID, name, platform string) *Device {
+	now := time.Now()
+	device := &Device{
+		ID:        types.NewID(),
+		UserID:    userID,
+		Name:      strings.TrimSpace(name),
+		Platform:  platform,
+		CreatedAt: now,
+		UpdatedAt: now,
+
+		TrustState:     DeviceTrustPending,
+		TrustChangedAt: now,
+	}
+	device.Record(events.NewDeviceRegistered(device.ID, device.nextVersion(), userID, device.Name, platform))
+	return device
+}
+
+// Rename updates the device's display name
+func (d *Device) Rename(name string) {
+	name = strings.TrimSpace(name)
+	if name != d.Name {
+		d.Record(events.NewDeviceRenamed(d.ID, d.nextVersion(), d.UserID, d.Name, name))
+	}
+	d.Name = name
+	d.UpdatedAt = time.Now()
+}
+
+// AttachFingerprint sets the fingerprint of a device being registered
+func (d *Device) AttachFingerprint(components map[string]string) {
+	d.Fingerprint = maps.Clone(components)
+}
+
+// AttachPublicKey sets the public key of a device being registered
+func (d *Device) AttachPublicKey(publicKey []byte) error {
+	if len(publicKey) != DevicePublicKeySize {
+		return ErrInvalidDeviceKey
+	}
+	d.PublicKey = bytes.Clone(publicKey)
+	return nil
+}
+
+// UpdateFingerprint replaces the stored fingerprint with a presented one
+// that matched it, recording which components drifted
+func (d *Device) UpdateFingerprint(components map[string]string, match FingerprintMatch) {
+	d.Fingerprint = maps.Clone(components)
+	d.UpdatedAt = time.Now()
+	d.Record(events.NewDeviceFingerprintUpdated(d.ID, d.nextActivityVersion(), d.UserID, match.Changed, match.Confidence))
+}
+
+// FlagSuspiciousFingerprint records that a presented fingerprint resembled
+// the device's without matching it closely enough
+func (d *Device) FlagSuspiciousFingerprint(match FingerprintMatch) {
+	d.Record(events.NewDeviceFingerprintSuspicious(d.ID, d.nextActivityVersion(), d.UserID, match.Changed, match.Confidence))
+}
+
+// Approve trusts a pending or suspended device on behalf of a privileged user
+func (d *Device) Approve(approvedBy types.ID) error {
+	if err := d.TrustState.transitionTo(DeviceTrustTrusted); err != nil {
+		return err
+	}
+	d.changeTrust(DeviceTrustTrusted)
+	d.ApprovedBy = approvedBy
+	d.Record(events.NewDeviceApproved(d.ID, d.nextVersion(), d.UserID, approvedBy))
+	return nil
+}
+
+// Suspend bars a pending or trusted device until it is approved again
+func (d *Device) Suspend(reason string) error {
+	if err := d.TrustState.transitionTo(DeviceTrustSuspended); err != nil {
+		return err
+	}
+	d.changeTrust(DeviceTrustSuspended)
+	d.Record(events.NewDeviceSuspended(d.ID, d.nextVersion(), d.UserID, strings.TrimSpace(reason)))
+	return nil
+}
+
+// Revoke permanently bars the device
+func (d *Device) Revoke() error {
+	if err := d.TrustState.transitionTo(DeviceTrustRevoked); err != nil {
+		return err
+	}
+	d.changeTrust(DeviceTrustRevoked)
+	d.Record(events.NewDeviceRevoked(d.ID, d.nextVersion(), d.UserID))
+	return nil
+}
+
+// Heartbeat records that the device is alive, running an app version from an
+// IP address. It reports whether the device had been marked stale.
+func (d *Device) Heartbeat(appVersion, ip string) (bool, error) {
+	if d.IsRevoked() {
+		return false, ErrDeviceRevoked
+	}
+	resumed := d.Stale
+	d.LastSeenAt = time.Now()
+	d.AppVersion = strings.TrimSpace(appVersion)
+	d.LastIP = ip
+	d.Stale = false
+	d.Record(events.NewDeviceHeartbeat(d.ID, d.nextActivityVersion(), d.UserID, d.AppVersion, ip, resumed))
+	return resumed, nil
+}
+
+// MarkStale flags a device that stopped sending heartbeats
+func (d *Device) MarkStale() {
+	if d.Stale || d.IsRevoked() {
+		return
+	}
+	d.Stale = true
+	d.Record(events.NewDeviceMarkedStale(d.ID, d.nextActivityVersion(), d.UserID, d.SeenAt()))
+}
+
+// SeenAt returns when the device last sent a heartbeat, or when it was
+// registered if it never sent one
+func (d *Device) SeenAt() time.Time {
+	if d.LastSeenAt.IsZero() {
+		return d.CreatedAt
+	}
+	return d.LastSeenAt
+}
+
+// IsRevoked checks if the device was revoked
+func (d *Device) IsRevoked() bool {
+	return d.TrustState == DeviceTrustRevoked
+}
+
+// CanAuthenticate checks if the device may still be used to sign in. Pending
+// devices may, so owners are not locked out while approval is outstanding.
+func (d *Device) CanAuthenticate() bool {
+	return d.TrustState == DeviceTrustPending || d.TrustState == DeviceTrustTrusted
+}
+
+// LastActiveAt returns when the device last sent a heartbeat, was recognized
+// or changed
+func (d *Device) LastActiveAt() time.Time {
+	if d.LastSeenAt.After(d.UpdatedAt) {
+		return d.LastSeenAt
+	}
+	return d.UpdatedAt
+}
+
+// changeTrust moves the device to a new trust state
+func (d *Device) changeTrust(state DeviceTrustState) {
+	now := time.Now()
+	d.TrustState = state
+	d.TrustChangedAt = now
+	d.UpdatedAt = now
+}
+
+// nextVersion advances the version for a change about to be recorded
+func (d *Device) nextVersion() int64 {
+	d.Version++
+	d.ProfileVersion = d.Version
+	return d.Version
+}
+
+// nextActivityVersion advances the version for activity about to be
+// recorded, leaving the profile version as it is
+func (d *Device) nextActivityVersion() int64 {
+	d.Version++
+	return d.Version
+}
+
+// Validate validates the device entity
+func (d *Device) Validate() error {
+	if d.UserID.IsEmpty() {
+		return ErrUserNotFound
+	}
+	if d.Name == "" {
+		return ErrInvalidDeviceName
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package entities
+
+import (
+	"time"
+
+	"shadow-id/pkg/types"
+)
+
+// deviceChallengeContext separates device challenge signatures from any other
+// message the device key could be asked to sign
+const deviceChallengeContext = "shadow-id device challenge v1"
+
+// DeviceChallenge holds a nonce issued to a device that it must sign with
+// its private key to prove a request comes from it. A challenge can only be
+// answered once and only before it expires.
+type DeviceChallenge struct {
+	ID        types.ID  `json:"id"`
+	DeviceID  types.ID  `json:"device_id"`
+	Nonce     []byte    `json:"-"`
+	ExpiresAt time.Time `json:"expires_at"`
+
+	// AllowPending lets a device still awaiting approval answer the challenge
+	AllowPending bool `json:"allow_pending"`
+}
+
+// NewDeviceChallenge creates a new challenge for a device
+func NewDeviceChallenge(deviceID types.ID, nonce []byte, ttl time.Duration, allowPending bool) *DeviceChallenge {
+	return &DeviceChallenge{
+		ID:           types.NewID(),
+		DeviceID:     deviceID,
+		Nonce:        nonce,
+		ExpiresAt:    time.Now().Add(ttl),
+		AllowPending: allowPending,
+	}
+}
+
+// IsExpired checks if the challenge can no longer be answered
+func (c *DeviceChallenge) IsExpired() bool {
+	return time.Now().After(c.ExpiresAt)
+}
+
+// Message returns the bytes the device signs to answer the challenge
+func (c *DeviceChallenge) Message() []byte {
+	return DeviceChallengeMessage(c.ID, c.DeviceID, c.Nonce)
+}
+
+// DeviceChallengeMessage returns the bytes a device signs to answer a
+// challenge. The challenge and device IDs are part of the message, so a
+// signature cannot be replayed against another challenge or device.
+func DeviceChallengeMessage(challengeID, deviceID types.ID, nonce []byte) []byte {
+	message := make([]byte, 0, len(deviceChallengeContext)+len(challengeID)+len(deviceID)+len(nonce)+3)
+	message = append(message, deviceChallengeContext...)
+	message = append(message, 0)
+	message = append(message, challengeID...)
+	message = append(message, 0)
+	message = append(message, deviceID...)
+	message = append(message, 0)
+	return append(message, nonce...)
+}
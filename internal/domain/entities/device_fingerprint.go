@@ -0,0 +1,141 @@
+package entities
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Fingerprint components, one per kind of machine attribute
+const (
+	FingerprintMachineID = "machine_id"
+	FingerprintHostname  = "hostname"
+	FingerprintMAC       = "mac_addresses"
+	FingerprintCPU       = "cpu"
+	FingerprintOS        = "os"
+	FingerprintDisks     = "disk_serials"
+)
+
+// DeviceFingerprint identifies the machine the application runs on. Raw
+// attributes never leave the collector: every component is a salted hash,
+// so fingerprints can be compared without revealing serial numbers or
+// addresses, and fingerprints from installations with different salts
+// cannot be correlated.
+type DeviceFingerprint struct {
+	// Hash combines every component into a single identifier
+	Hash string `json:"hash"`
+
+	// Components holds the salted hash of each attribute that could be read,
+	// keyed by component name
+	Components map[string]string `json:"components"`
+
+	// Platform and Hostname are kept in the clear to label the device
+	Platform string `json:"platform"`
+	Hostname string `json:"hostname"`
+
+	CollectedAt time.Time `json:"collected_at"`
+}
+
+// FingerprintVerdict is the outcome of matching a fingerprint against a device
+type FingerprintVerdict string
+
+// Fingerprint verdicts
+const (
+	// FingerprintMatched means the fingerprint belongs to the device
+	FingerprintMatched FingerprintVerdict = "matched"
+	// FingerprintSuspicious means the fingerprint resembles the device's but
+	// changed too much to be trusted
+	FingerprintSuspicious FingerprintVerdict = "suspicious"
+	// FingerprintUnknown means the fingerprint belongs to another machine
+	FingerprintUnknown FingerprintVerdict = "unknown"
+)
+
+// FingerprintMatch is the result of scoring a presented fingerprint against
+// a stored one
+type FingerprintMatch struct {
+	// Confidence is the weighted share of components that agree, from 0 to 1
+	Confidence float64 `json:"confidence"`
+
+	// Changed names the components that differ or are missing on one side
+	Changed []string `json:"changed"`
+
+	Verdict FingerprintVerdict `json:"verdict"`
+}
+
+// FingerprintMatchPolicy decides how closely a fingerprint must resemble a
+// stored one. Components are weighted by how stable and how unique they are;
+// components without a weight are ignored.
+type FingerprintMatchPolicy struct {
+	Weights map[string]float64
+
+	// MatchThreshold is the confidence from which a fingerprint matches
+	MatchThreshold float64
+
+	// SuspicionThreshold is the confidence from which a fingerprint that does
+	// not match is flagged as a suspicious change rather than another machine
+	SuspicionThreshold float64
+}
+
+// DefaultFingerprintMatchPolicy returns weights that tolerate a swapped
+// network card or an upgraded operating system, but not a new machine ID
+func DefaultFingerprintMatchPolicy() FingerprintMatchPolicy {
+	return FingerprintMatchPolicy{
+		Weights: map[string]float64{
+			FingerprintMachineID: 0.35,
+			FingerprintDisks:     0.20,
+			FingerprintMAC:       0.15,
+			FingerprintCPU:       0.15,
+			FingerprintHostname:  0.10,
+			FingerprintOS:        0.05,
+		},
+		MatchThreshold:     0.8,
+		SuspicionThreshold: 0.5,
+	}
+}
+
+// Match scores a presented fingerprint against a stored one. A component
+// present on only one side counts as changed, so a fingerprint cannot match
+// by leaving out the components that would differ.
+func (p FingerprintMatchPolicy) Match(stored, presented map[string]string) FingerprintMatch {
+	names := make([]string, 0, len(stored)+len(presented))
+	for name := range stored {
+		names = append(names, name)
+	}
+	for name := range presented {
+		if _, seen := stored[name]; !seen {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var total, agreed float64
+	changed := make([]string, 0)
+	for _, name := range names {
+		weight := p.Weights[name]
+		if weight <= 0 {
+			continue
+		}
+		total += weight
+		storedHash, inStored := stored[name]
+		presentedHash, inPresented := presented[name]
+		if inStored && inPresented && storedHash == presentedHash {
+			agreed += weight
+		} else {
+			changed = append(changed, name)
+		}
+	}
+
+	match := FingerprintMatch{Changed: changed, Verdict: FingerprintUnknown}
+	if total > 0 {
+		// Round so sums of weights do not fall just short of a threshold
+		match.Confidence = math.Round(agreed/total*1e4) / 1e4
+	}
+	switch {
+	case total == 0:
+	case match.Confidence >= p.MatchThreshold:
+		match.Verdict = FingerprintMatched
+	case match.Confidence >= p.SuspicionThreshold:
+		match.Verdict = FingerprintSuspicious
+	}
+	return match
+}
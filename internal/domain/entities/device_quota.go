@@ -0,0 +1,118 @@
+package entities
+
+import (
+	"fmt"
+	"time"
+
+	"shadow-id/pkg/types"
+)
+
+// DeviceQuotaPolicy decides how many devices a user may have activated.
+// Revoked devices do not count towards a quota. A limit of zero means
+// unlimited.
+type DeviceQuotaPolicy struct {
+	// DefaultLimit applies to users without a user or role limit
+	DefaultLimit int
+
+	// RoleLimits limit the users holding a role; a user holding several
+	// limited roles gets the most generous limit
+	RoleLimits map[string]int
+
+	// UserLimits override every other limit for single users
+	UserLimits map[types.ID]int
+
+	// EvictOldestInactive makes room for a new device by revoking the device
+	// that has been inactive the longest instead of rejecting the new one.
+	// Only devices inactive for at least InactiveAfter are evicted.
+	EvictOldestInactive bool
+	InactiveAfter       time.Duration
+}
+
+// DeviceQuota is a user's device usage against their limit
+type DeviceQuota struct {
+	Limit int
+	Used  int
+}
+
+// Unlimited checks if the user may activate any number of devices
+func (q DeviceQuota) Unlimited() bool {
+	return q.Limit <= 0
+}
+
+// Remaining returns how many more devices the user may activate; it is zero
+// for unlimited quotas
+func (q DeviceQuota) Remaining() int {
+	if q.Unlimited() || q.Used >= q.Limit {
+		return 0
+	}
+	return q.Limit - q.Used
+}
+
+// Reached checks if the user has no room for another device
+func (q DeviceQuota) Reached() bool {
+	return !q.Unlimited() && q.Used >= q.Limit
+}
+
+// Err returns ErrDeviceLimitReached describing the usage if the quota was reached
+func (q DeviceQuota) Err() error {
+	if !q.Reached() {
+		return nil
+	}
+	return fmt.Errorf("%w: %d of %d devices in use", ErrDeviceLimitReached, q.Used, q.Limit)
+}
+
+// LimitFor returns the device limit that applies to a user
+func (p DeviceQuotaPolicy) LimitFor(user *User) int {
+	if limit, exists := p.UserLimits[user.ID]; exists {
+		return limit
+	}
+
+	limit, limited := 0, false
+	for _, role := range user.Roles {
+		roleLimit, exists := p.RoleLimits[role]
+		if !exists {
+			continue
+		}
+		// An unlimited role lifts every other role limit
+		if roleLimit <= 0 {
+			return 0
+		}
+		if !limited || roleLimit > limit {
+			limit, limited = roleLimit, true
+		}
+	}
+	if limited {
+		return limit
+	}
+	return p.DefaultLimit
+}
+
+// Usage counts a user's devices against their limit
+func (p DeviceQuotaPolicy) Usage(user *User, devices []*Device) DeviceQuota {
+	quota := DeviceQuota{Limit: p.LimitFor(user)}
+	for _, device := range devices {
+		if !device.IsRevoked() {
+			quota.Used++
+		}
+	}
+	return quota
+}
+
+// EvictionCandidate returns the device to revoke to make room for a new one,
+// or nil if eviction is disabled or no device has been inactive long enough
+func (p DeviceQuotaPolicy) EvictionCandidate(devices []*Device, now time.Time) *Device {
+	if !p.EvictOldestInactive {
+		return nil
+	}
+
+	var candidate *Device
+	for _, device := range devices {
+		if device.IsRevoked() || now.Sub(device.LastActiveAt()) < p.InactiveAfter {
+			continue
+		}
+		if candidate == nil || device.LastActiveAt().Before(candidate.LastActiveAt()) {
+			candidate = device
+		}
+	}
+	return candidate
+}
@@ -0,0 +1,54 @@
+package entities
+
+import "fmt"
+
+// DeviceTrustState describes how far a device is trusted to act for its owner
+type DeviceTrustState string
+
+// Device trust states
+const (
+	// DeviceTrustPending devices are registered but await approval
+	DeviceTrustPending DeviceTrustState = "pending"
+	// DeviceTrustTrusted devices were approved by a privileged user
+	DeviceTrustTrusted DeviceTrustState = "trusted"
+	// DeviceTrustSuspended devices are temporarily barred until approved again
+	DeviceTrustSuspended DeviceTrustState = "suspended"
+	// DeviceTrustRevoked devices are permanently barred
+	DeviceTrustRevoked DeviceTrustState = "revoked"
+)
+
+// deviceTrustTransitions lists the states each state may move to. Revoked is
+// terminal.
+var deviceTrustTransitions = map[DeviceTrustState][]DeviceTrustState{
+	DeviceTrustPending:   {DeviceTrustTrusted, DeviceTrustSuspended, DeviceTrustRevoked},
+	DeviceTrustTrusted:   {DeviceTrustSuspended, DeviceTrustRevoked},
+	DeviceTrustSuspended: {DeviceTrustTrusted, DeviceTrustRevoked},
+}
+
+// IsValid checks if the trust state is a known state
+func (s DeviceTrustState) IsValid() bool {
+	switch s {
+	case DeviceTrustPending, DeviceTrustTrusted, DeviceTrustSuspended, DeviceTrustRevoked:
+		return true
+	}
+	return false
+}
+
+// CanTransitionTo checks if a device in this state may move to the target state
+func (s DeviceTrustState) CanTransitionTo(target DeviceTrustState) bool {
+	for _, allowed := range deviceTrustTransitions[s] {
+		if allowed == target {
+			return true
+		}
+	}
+	return false
+}
+
+// transitionTo returns an error wrapping ErrInvalidTrustTransition when the
+// move to the target state is not allowed
+func (s DeviceTrustState) transitionTo(target DeviceTrustState) error {
+	if !s.CanTransitionTo(target) {
+		return fmt.Errorf("%w: %s to %s", ErrInvalidTrustTransition, s, target)
+	}
+	return nil
+}
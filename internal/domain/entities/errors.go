@@ -4,8 +4,58 @@ import "errors"
 
 // Domain errors
 var (
-	ErrInvalidUserName  = errors.New("invalid user name")
-	ErrInvalidUserEmail = errors.New("invalid user email")
-	ErrUserNotFound     = errors.New("user not found")
+	ErrInvalidUserName   = errors.New("invalid user name")
+	ErrInvalidUserEmail  = errors.New("invalid user email")
+	ErrUserNotFound      = errors.New("user not found")
 	ErrUserAlreadyExists = errors.New("user already exists")
+	ErrUserNotDeleted    = errors.New("user is not deleted")
+
+	ErrTwoFactorNotEnrolled    = errors.New("two-factor authentication is not enrolled")
+	ErrTwoFactorAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorRequired       = errors.New("two-factor authentication is required for this user")
+	ErrInvalidTwoFactorCode    = errors.New("invalid two-factor code")
+	ErrTwoFactorCodeReused     = errors.New("two-factor code has already been used")
+	ErrRecoveryCodeUsed        = errors.New("recovery code has already been used")
+
+	ErrDeviceNotFound      = errors.New("device not found")
+	ErrInvalidDeviceName   = errors.New("invalid device name")
+	ErrDeviceOwnerMismatch = errors.New("device does not belong to user")
+	ErrDeviceNotTrusted    = errors.New("device is suspended or revoked")
+	ErrDeviceLimitReached  = errors.New("device limit reached")
+	ErrDeviceRevoked       = errors.New("device is revoked")
+	ErrDevicePending       = errors.New("device is awaiting approval")
+
+	ErrInvalidTrustTransition = errors.New("invalid device trust transition")
+
+	ErrDeviceKeyMissing        = errors.New("device has no key")
+	ErrInvalidDeviceKey        = errors.New("invalid device public key")
+	ErrInvalidDeviceSignature  = errors.New("invalid device signature")
+	ErrDeviceChallengeNotFound = errors.New("device challenge not found")
+	ErrDeviceChallengeExpired  = errors.New("device challenge expired")
+
+	ErrFingerprintUnavailable = errors.New("no device attributes could be collected")
+
+	ErrCredentialNotFound      = errors.New("credential not found")
+	ErrCredentialAlreadyExists = errors.New("credential already exists")
+	ErrCredentialCloned        = errors.New("credential sign counter regressed, possible cloned authenticator")
+	ErrWebAuthnSessionNotFound = errors.New("webauthn session not found")
+	ErrWebAuthnSessionExpired  = errors.New("webauthn session expired")
+
+	ErrAccountLocked   = errors.New("account is temporarily locked")
+	ErrTooManyAttempts = errors.New("too many failed attempts, try again later")
+
+	ErrInvalidRoleName     = errors.New("invalid role name")
+	ErrRoleNotFound        = errors.New("role not found")
+	ErrRoleAlreadyExists   = errors.New("role already exists")
+	ErrRoleAlreadyAssigned = errors.New("role already assigned")
+	ErrRoleNotAssigned     = errors.New("role not assigned")
+	ErrLastAdmin           = errors.New("cannot remove the last administrator")
+
+	ErrInvalidPolicy   = errors.New("invalid policy")
+	ErrDuplicatePolicy = errors.New("duplicate policy ID")
+	ErrPolicyDenied    = errors.New("denied by policy")
+
+	ErrAuditChainBroken = errors.New("audit log hash chain is broken")
+
+	ErrVersionConflict = errors.New("entity was changed concurrently")
 )
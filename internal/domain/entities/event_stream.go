@@ -0,0 +1,50 @@
+package entities
+
+import (
+	"time"
+
+	"shadow-id/internal/domain/events"
+	"shadow-id/pkg/types"
+)
+
+// StoredEvent is an event persisted as part of an aggregate's stream. Versions
+// start at 1 and increase by one with every event in the stream.
+type StoredEvent struct {
+	StreamID   types.ID  `json:"stream_id"`
+	Version    int64     `json:"version"`
+	EventID    types.ID  `json:"event_id"`
+	EventName  string    `json:"event_name"`
+	Payload    []byte    `json:"payload"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// NewStoredEvent serializes an event at a position in its stream
+func NewStoredEvent(event events.Event, version int64) (*StoredEvent, error) {
+	payload, err := events.Encode(event)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StoredEvent{
+		StreamID:   event.AggregateID(),
+		Version:    version,
+		EventID:    event.EventID(),
+		EventName:  event.EventName(),
+		Payload:    payload,
+		OccurredAt: event.OccurredAt(),
+	}, nil
+}
+
+// Event rebuilds the stored domain event
+func (e *StoredEvent) Event() (events.Event, error) {
+	return events.Decode(e.EventName, e.Payload)
+}
+
+// Snapshot is the serialized state of an aggregate after a given version of
+// its stream, letting it be rebuilt without replaying every event
+type Snapshot struct {
+	StreamID  types.ID  `json:"stream_id"`
+	Version   int64     `json:"version"`
+	State     []byte    `json:"state"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,93 @@
+package entities
+
+import (
+	"testing"
+	"time"
+
+	"shadow-id/pkg/types"
+)
+
+func TestRecordFailureBacksOff(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   LockoutPolicy
+		failures int
+		want     time.Duration
+	}{
+		{"first failure waits the base delay", LockoutPolicy{BaseDelay: time.Second, MaxDelay: time.Minute}, 1, time.Second},
+		{"each failure doubles the delay", LockoutPolicy{BaseDelay: time.Second, MaxDelay: time.Minute}, 4, 8 * time.Second},
+		{"delay is capped", LockoutPolicy{BaseDelay: time.Second, MaxDelay: 5 * time.Second}, 4, 5 * time.Second},
+		{"zero max delay leaves it uncapped", LockoutPolicy{BaseDelay: time.Second}, 11, 1024 * time.Second},
+		{"uncapped delay does not overflow", LockoutPolicy{BaseDelay: time.Second}, 100, time.Second << 33},
+		{"no base delay", LockoutPolicy{MaxDelay: time.Minute}, 3, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Now()
+			throttle := NewLoginThrottle(ThrottleScopeAccount, types.NewID())
+			for i := 0; i < tt.failures; i++ {
+				throttle.RecordFailure(now, tt.policy)
+			}
+
+			if got := throttle.NextAttemptAt.Sub(now); got != tt.want {
+				t.Errorf("delay after %d failures = %v, want %v", tt.failures, got, tt.want)
+			}
+			if throttle.IsLocked(now) {
+				t.Error("IsLocked() = true without an attempt limit")
+			}
+		})
+	}
+}
+
+func TestRecordFailureLocksOut(t *testing.T) {
+	policy := LockoutPolicy{
+		MaxAttempts:     3,
+		LockoutDuration: 15 * time.Minute,
+		BaseDelay:       time.Second,
+	}
+
+	tests := []struct {
+		name       string
+		failures   int
+		at         time.Duration
+		wantLocked bool
+		wantErr    error
+	}{
+		{"below the limit is throttled", 2, 0, false, ErrTooManyAttempts},
+		{"below the limit after the backoff", 2, 2 * time.Second, false, nil},
+		{"reaching the limit locks", 3, 0, true, ErrAccountLocked},
+		{"lock lasts its duration", 3, 14 * time.Minute, true, ErrAccountLocked},
+		{"lock expires", 3, 15 * time.Minute, false, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Now()
+			throttle := NewLoginThrottle(ThrottleScopeAccount, types.NewID())
+			for i := 0; i < tt.failures; i++ {
+				throttle.RecordFailure(start, policy)
+			}
+
+			now := start.Add(tt.at)
+			if got := throttle.IsLocked(now); got != tt.wantLocked {
+				t.Errorf("IsLocked() = %v, want %v", got, tt.wantLocked)
+			}
+			if _, err := throttle.Check(now); err != tt.wantErr {
+				t.Errorf("Check() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFailureAfterExpiredLockoutStartsFreshSeries(t *testing.T) {
+	policy := LockoutPolicy{MaxAttempts: 2, LockoutDuration: time.Minute, BaseDelay: time.Second}
+	now := time.Now()
+	throttle := NewLoginThrottle(ThrottleScopeDevice, types.NewID())
+	throttle.RecordFailure(now, policy)
+	throttle.RecordFailure(now, policy)
+
+	later := now.Add(time.Minute)
+	throttle.RecordFailure(later, policy)
+	if throttle.FailedAttempts != 1 || throttle.IsLocked(later) {
+		t.Errorf("FailedAttempts = %d, locked = %v, want a fresh series", throttle.FailedAttempts, throttle.IsLocked(later))
+	}
+}
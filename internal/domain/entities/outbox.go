@@ -0,0 +1,95 @@
+package entities
+
+import (
+	"math"
+	"time"
+
+	"shadow-id/internal/domain/events"
+	"shadow-id/pkg/types"
+)
+
+// OutboxEntry is a domain event stored alongside the change that raised it,
+// waiting to be delivered to subscribers
+type OutboxEntry struct {
+	ID            types.ID   `json:"id"`
+	EventID       types.ID   `json:"event_id"`
+	EventName     string     `json:"event_name"`
+	AggregateID   types.ID   `json:"aggregate_id"`
+	Payload       []byte     `json:"payload"`
+	OccurredAt    time.Time  `json:"occurred_at"`
+	Attempts      int        `json:"attempts"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	LastError     string     `json:"last_error,omitempty"`
+	DeliveredAt   *time.Time `json:"delivered_at,omitempty"`
+	DeadAt        *time.Time `json:"dead_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// DeliveryPolicy controls how failed outbox deliveries are retried
+type DeliveryPolicy struct {
+	// MaxAttempts is the number of deliveries tried before an entry is
+	// parked as dead; zero retries forever
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// NewOutboxEntry serializes an event into an outbox entry ready for delivery
+func NewOutboxEntry(event events.Event) (*OutboxEntry, error) {
+	payload, err := events.Encode(event)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return &OutboxEntry{
+		ID:            types.NewID(),
+		EventID:       event.EventID(),
+		EventName:     event.EventName(),
+		AggregateID:   event.AggregateID(),
+		Payload:       payload,
+		OccurredAt:    event.OccurredAt(),
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}, nil
+}
+
+// Event rebuilds the stored domain event
+func (e *OutboxEntry) Event() (events.Event, error) {
+	return events.Decode(e.EventName, e.Payload)
+}
+
+// IsPending checks if the entry still awaits delivery
+func (e *OutboxEntry) IsPending() bool {
+	return e.DeliveredAt == nil && e.DeadAt == nil
+}
+
+// IsDue checks if a pending entry may be attempted at the given time
+func (e *OutboxEntry) IsDue(now time.Time) bool {
+	return e.IsPending() && !now.Before(e.NextAttemptAt)
+}
+
+// MarkDelivered records a successful delivery
+func (e *OutboxEntry) MarkDelivered(now time.Time) {
+	e.Attempts++
+	e.LastError = ""
+	e.DeliveredAt = &now
+}
+
+// MarkFailed records a failed delivery and schedules the next attempt with
+// exponential backoff, or parks the entry once the attempts are exhausted
+func (e *OutboxEntry) MarkFailed(now time.Time, cause error, policy DeliveryPolicy) {
+	e.Attempts++
+	e.LastError = cause.Error()
+
+	if policy.MaxAttempts > 0 && e.Attempts >= policy.MaxAttempts {
+		e.DeadAt = &now
+		return
+	}
+
+	delay := time.Duration(float64(policy.BaseDelay) * math.Pow(2, float64(e.Attempts-1)))
+	if policy.MaxDelay > 0 && (delay > policy.MaxDelay || delay <= 0) {
+		delay = policy.MaxDelay
+	}
+	e.NextAttemptAt = now.Add(delay)
+}
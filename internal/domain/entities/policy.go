@@ -0,0 +1,250 @@
+package entities
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// PolicyEffect is the outcome a matching policy produces
+type PolicyEffect string
+
+// Policy effects
+const (
+	PolicyEffectAllow PolicyEffect = "allow"
+	PolicyEffectDeny  PolicyEffect = "deny"
+	// PolicyEffectNotApplicable is reported when no policy matched a request
+	PolicyEffectNotApplicable PolicyEffect = "not_applicable"
+)
+
+// ConditionOperator compares a request attribute against a value
+type ConditionOperator string
+
+// Condition operators
+const (
+	OperatorEquals      ConditionOperator = "equals"
+	OperatorNotEquals   ConditionOperator = "not_equals"
+	OperatorIn          ConditionOperator = "in"
+	OperatorNotIn       ConditionOperator = "not_in"
+	OperatorContains    ConditionOperator = "contains"
+	OperatorNotContains ConditionOperator = "not_contains"
+	OperatorExists      ConditionOperator = "exists"
+	OperatorNotExists   ConditionOperator = "not_exists"
+)
+
+// Attribute namespaces a condition may reference
+const (
+	AttributeSubject  = "subject"
+	AttributeResource = "resource"
+	AttributeContext  = "context"
+	AttributeAction   = "action"
+)
+
+// PolicyAttributes holds the attributes of a subject, resource or request context
+type PolicyAttributes map[string]interface{}
+
+// PolicyRequest describes an access request evaluated against policies
+type PolicyRequest struct {
+	Subject  PolicyAttributes `json:"subject"`
+	Resource PolicyAttributes `json:"resource"`
+	Action   string           `json:"action"`
+	Context  PolicyAttributes `json:"context"`
+}
+
+// Attribute resolves a dotted attribute path such as "subject.id"
+func (r PolicyRequest) Attribute(path string) (interface{}, bool) {
+	if path == AttributeAction {
+		return r.Action, r.Action != ""
+	}
+
+	namespace, key, ok := strings.Cut(path, ".")
+	if !ok {
+		return nil, false
+	}
+	var attributes PolicyAttributes
+	switch namespace {
+	case AttributeSubject:
+		attributes = r.Subject
+	case AttributeResource:
+		attributes = r.Resource
+	case AttributeContext:
+		attributes = r.Context
+	default:
+		return nil, false
+	}
+	value, ok := attributes[key]
+	return value, ok
+}
+
+// PolicyCondition is a single attribute test. The right-hand side is either a
+// literal Value or another attribute named by Ref.
+type PolicyCondition struct {
+	Attribute string            `json:"attribute"`
+	Operator  ConditionOperator `json:"operator"`
+	Value     interface{}       `json:"value,omitempty"`
+	Ref       string            `json:"ref,omitempty"`
+}
+
+// Policy is a rule that allows or denies actions when all its conditions hold
+type Policy struct {
+	ID          string            `json:"id"`
+	Description string            `json:"description"`
+	Effect      PolicyEffect      `json:"effect"`
+	Actions     []string          `json:"actions"`
+	Conditions  []PolicyCondition `json:"conditions"`
+
+	// DryRun policies are evaluated and reported but never affect a decision
+	DryRun bool `json:"dry_run"`
+}
+
+// AppliesTo checks if the policy covers the action. Actions may be exact,
+// "*" or a prefix wildcard such as "devices:*".
+func (p *Policy) AppliesTo(action string) bool {
+	for _, pattern := range p.Actions {
+		if pattern == "*" || pattern == action {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok && strings.HasPrefix(action, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Matches checks if the policy applies to the request and every condition holds.
+// The returned reason explains the first condition that failed, or why it matched.
+func (p *Policy) Matches(request PolicyRequest) (bool, string) {
+	if !p.AppliesTo(request.Action) {
+		return false, fmt.Sprintf("action %q not covered", request.Action)
+	}
+	for _, condition := range p.Conditions {
+		if !condition.holds(request) {
+			return false, "condition failed: " + condition.String()
+		}
+	}
+	if len(p.Conditions) == 0 {
+		return true, "action matched"
+	}
+	return true, "all conditions held"
+}
+
+// Validate validates the policy definition
+func (p *Policy) Validate() error {
+	if p.ID == "" {
+		return fmt.Errorf("%w: missing id", ErrInvalidPolicy)
+	}
+	if p.Effect != PolicyEffectAllow && p.Effect != PolicyEffectDeny {
+		return fmt.Errorf("%w: %s: effect must be allow or deny", ErrInvalidPolicy, p.ID)
+	}
+	if len(p.Actions) == 0 {
+		return fmt.Errorf("%w: %s: no actions", ErrInvalidPolicy, p.ID)
+	}
+	for _, condition := range p.Conditions {
+		if err := condition.validate(); err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalidPolicy, p.ID, err)
+		}
+	}
+	return nil
+}
+
+// String renders the condition for decision explanations
+func (c PolicyCondition) String() string {
+	switch {
+	case c.Operator == OperatorExists || c.Operator == OperatorNotExists:
+		return fmt.Sprintf("%s %s", c.Attribute, c.Operator)
+	case c.Ref != "":
+		return fmt.Sprintf("%s %s %s", c.Attribute, c.Operator, c.Ref)
+	default:
+		return fmt.Sprintf("%s %s %v", c.Attribute, c.Operator, c.Value)
+	}
+}
+
+// validate checks the condition references known namespaces and operators
+func (c PolicyCondition) validate() error {
+	if !validAttributePath(c.Attribute) {
+		return fmt.Errorf("unknown attribute %q", c.Attribute)
+	}
+	if c.Ref != "" && !validAttributePath(c.Ref) {
+		return fmt.Errorf("unknown attribute %q", c.Ref)
+	}
+	switch c.Operator {
+	case OperatorExists, OperatorNotExists:
+		return nil
+	case OperatorEquals, OperatorNotEquals, OperatorIn, OperatorNotIn, OperatorContains, OperatorNotContains:
+		if c.Ref == "" && c.Value == nil {
+			return fmt.Errorf("condition on %q needs a value or ref", c.Attribute)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown operator %q", c.Operator)
+	}
+}
+
+// holds evaluates the condition against a request
+func (c PolicyCondition) holds(request PolicyRequest) bool {
+	actual, present := request.Attribute(c.Attribute)
+	switch c.Operator {
+	case OperatorExists:
+		return present
+	case OperatorNotExists:
+		return !present
+	}
+
+	expected := c.Value
+	if c.Ref != "" {
+		var ok bool
+		if expected, ok = request.Attribute(c.Ref); !ok {
+			expected = nil
+		}
+	}
+
+	switch c.Operator {
+	case OperatorEquals:
+		return present && valuesEqual(actual, expected)
+	case OperatorNotEquals:
+		return !present || !valuesEqual(actual, expected)
+	case OperatorIn:
+		return present && listContains(expected, actual)
+	case OperatorNotIn:
+		return !present || !listContains(expected, actual)
+	case OperatorContains:
+		return present && listContains(actual, expected)
+	case OperatorNotContains:
+		return !present || !listContains(actual, expected)
+	}
+	return false
+}
+
+// validAttributePath checks the path starts with a known namespace
+func validAttributePath(path string) bool {
+	if path == AttributeAction {
+		return true
+	}
+	namespace, key, ok := strings.Cut(path, ".")
+	if !ok || key == "" {
+		return false
+	}
+	return namespace == AttributeSubject || namespace == AttributeResource || namespace == AttributeContext
+}
+
+// valuesEqual compares attribute values, treating IDs and strings alike
+func valuesEqual(a, b interface{}) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return fmt.Sprint(a) == fmt.Sprint(b)
+}
+
+// listContains checks if a slice value holds the element
+func listContains(list, element interface{}) bool {
+	value := reflect.ValueOf(list)
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return valuesEqual(list, element)
+	}
+	for i := 0; i < value.Len(); i++ {
+		if valuesEqual(value.Index(i).Interface(), element) {
+			return true
+		}
+	}
+	return false
+}
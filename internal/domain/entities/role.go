@@ -0,0 +1,117 @@
+package entities
+
+import (
+	"time"
+)
+
+// Permission represents an action a role may perform
+type Permission string
+
+// Permissions
+const (
+	// PermissionNone marks operations that anyone, including anonymous callers, may perform
+	PermissionNone Permission = ""
+	// PermissionAll grants every permission
+	PermissionAll Permission = "*"
+
+	PermissionUsersCreate        Permission = "users:create"
+	PermissionUsersRead          Permission = "users:read"
+	PermissionUsersUpdate        Permission = "users:update"
+	PermissionUsersDelete        Permission = "users:delete"
+	PermissionUsersRestore       Permission = "users:restore"
+	PermissionUsersPurge         Permission = "users:purge"
+	PermissionTwoFactorManage    Permission = "two_factor:manage"
+	PermissionTwoFactorEnforce   Permission = "two_factor:enforce"
+	PermissionDevicesRegister    Permission = "devices:register"
+	PermissionDevicesRead        Permission = "devices:read"
+	PermissionDevicesRevoke      Permission = "devices:revoke"
+	PermissionDevicesApprove     Permission = "devices:approve"
+	PermissionDevicesHeartbeat   Permission = "devices:heartbeat"
+	PermissionDevicesSweep       Permission = "devices:sweep"
+	PermissionPasskeysManage     Permission = "passkeys:manage"
+	PermissionPasskeysRead       Permission = "passkeys:read"
+	PermissionAccountsUnlock     Permission = "accounts:unlock"
+	PermissionLockoutsRead       Permission = "lockouts:read"
+	PermissionRolesRead          Permission = "roles:read"
+	PermissionRolesAssign        Permission = "roles:assign"
+	PermissionPoliciesEvaluate   Permission = "policies:evaluate"
+	PermissionMetricsRead        Permission = "metrics:read"
+	PermissionAuditRead          Permission = "audit:read"
+	PermissionProjectionsRebuild Permission = "projections:rebuild"
+)
+
+// Built-in role names
+const (
+	RoleAdmin  = "admin"
+	RoleUser   = "user"
+	RoleViewer = "viewer"
+)
+
+// Role represents a named set of permissions
+type Role struct {
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	Permissions []Permission `json:"permissions"`
+	BuiltIn     bool         `json:"built_in"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+// NewRole creates a new role entity
+func NewRole(name, description string, permissions []Permission) *Role {
+	now := time.Now()
+	return &Role{
+		Name:        name,
+		Description: description,
+		Permissions: permissions,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+// BuiltInRoles returns the roles every installation starts with
+func BuiltInRoles() []*Role {
+	admin := NewRole(RoleAdmin, "Full access to every operation", []Permission{PermissionAll})
+	user := NewRole(RoleUser, "Self-service access to own credentials and devices", []Permission{
+		PermissionUsersRead,
+		PermissionUsersUpdate,
+		PermissionTwoFactorManage,
+		PermissionDevicesRegister,
+		PermissionDevicesRead,
+		PermissionDevicesRevoke,
+		PermissionDevicesHeartbeat,
+		PermissionPasskeysManage,
+		PermissionPasskeysRead,
+	})
+	viewer := NewRole(RoleViewer, "Read-only access", []Permission{
+		PermissionUsersRead,
+		PermissionDevicesRead,
+		PermissionPasskeysRead,
+		PermissionLockoutsRead,
+		PermissionRolesRead,
+	})
+
+	roles := []*Role{admin, user, viewer}
+	for _, role := range roles {
+		role.BuiltIn = true
+	}
+	return roles
+}
+
+// HasPermission checks if the role grants a permission
+func (r *Role) HasPermission(permission Permission) bool {
+	for _, granted := range r.Permissions {
+		if granted == PermissionAll || granted == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate validates the role entity
+func (r *Role) Validate() error {
+	if r.Name == "" {
+		return ErrInvalidRoleName
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package entities
+
+import (
+	"crypto/subtle"
+	"time"
+
+	"shadow-id/pkg/types"
+)
+
+// TwoFactor represents a user's TOTP enrollment and recovery codes
+type TwoFactor struct {
+	UserID        types.ID       `json:"user_id"`
+	Secret        string         `json:"-"`
+	Confirmed     bool           `json:"confirmed"`
+	LastUsedStep  int64          `json:"-"`
+	RecoveryCodes []RecoveryCode `json:"-"`
+	ConfirmedAt   *time.Time     `json:"confirmed_at,omitempty"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+}
+
+// RecoveryCode represents a hashed single-use recovery code
+type RecoveryCode struct {
+	Hash   string     `json:"-"`
+	UsedAt *time.Time `json:"used_at,omitempty"`
+}
+
+// NewTwoFactor creates a new, unconfirmed TOTP enrollment
+func NewTwoFactor(userID types.ID, secret string, recoveryCodeHashes []string) *TwoFactor {
+	now := time.Now()
+	twoFactor := &TwoFactor{
+		UserID:    userID,
+		Secret:    secret,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	twoFactor.ReplaceRecoveryCodes(recoveryCodeHashes)
+	return twoFactor
+}
+
+// Confirm marks the enrollment as confirmed after the first valid code
+func (t *TwoFactor) Confirm(step int64) error {
+	if t.Confirmed {
+		return ErrTwoFactorAlreadyEnabled
+	}
+	if err := t.UseStep(step); err != nil {
+		return err
+	}
+	now := time.Now()
+	t.Confirmed = true
+	t.ConfirmedAt = &now
+	t.UpdatedAt = now
+	return nil
+}
+
+// UseStep records a TOTP time step as used, rejecting replays of the
+// same or an earlier step
+func (t *TwoFactor) UseStep(step int64) error {
+	if step <= t.LastUsedStep {
+		return ErrTwoFactorCodeReused
+	}
+	t.LastUsedStep = step
+	t.UpdatedAt = time.Now()
+	return nil
+}
+
+// UseRecoveryCode consumes the recovery code matching the given hash
+func (t *TwoFactor) UseRecoveryCode(hash string) error {
+	for i := range t.RecoveryCodes {
+		code := &t.RecoveryCodes[i]
+		if subtle.ConstantTimeCompare([]byte(code.Hash), []byte(hash)) != 1 {
+			continue
+		}
+		if code.UsedAt != nil {
+			return ErrRecoveryCodeUsed
+		}
+		now := time.Now()
+		code.UsedAt = &now
+		t.UpdatedAt = now
+		return nil
+	}
+	return ErrInvalidTwoFactorCode
+}
+
+// ReplaceRecoveryCodes discards existing recovery codes and stores the given hashes
+func (t *TwoFactor) ReplaceRecoveryCodes(hashes []string) {
+	t.RecoveryCodes = make([]RecoveryCode, len(hashes))
+	for i, hash := range hashes {
+		t.RecoveryCodes[i] = RecoveryCode{Hash: hash}
+	}
+	t.UpdatedAt = time.Now()
+}
+
+// RemainingRecoveryCodes returns the number of unused recovery codes
+func (t *TwoFactor) RemainingRecoveryCodes() int {
+	remaining := 0
+	for _, code := range t.RecoveryCodes {
+		if code.UsedAt == nil {
+			remaining++
+		}
+	}
+	return remaining
+}
@@ -3,42 +3,229 @@ package entities
 import (
 	"time"
 
+	"shadow-id/internal/domain/events"
 	"shadow-id/pkg/types"
 )
 
 // User represents a user entity in the domain
 type User struct {
-	ID        types.ID    `json:"id"`
-	Name      string      `json:"name"`
-	Email     string      `json:"email"`
-	CreatedAt time.Time   `json:"created_at"`
-	UpdatedAt time.Time   `json:"updated_at"`
+	ID                types.ID   `json:"id"`
+	Name              string     `json:"name"`
+	Email             string     `json:"email"`
+	TwoFactorRequired bool       `json:"two_factor_required"`
+	Roles             []string   `json:"roles"`
+	LastLoginAt       *time.Time `json:"last_login_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+
+	// DeletedAt is set while the user is soft-deleted. Deleted users are
+	// hidden from lookups until they are restored or purged.
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+
+	// Version counts the changes made to the user; every recorded event
+	// advances it by one. Writes based on an outdated version are rejected.
+	Version int64 `json:"version"`
+
+	// ProfileVersion is the version of the last change that was not a sign-in
+	// or sign-out. Commands check the version callers read against it, so a
+	// session does not make every earlier read of the user stale.
+	ProfileVersion int64 `json:"profile_version"`
+
+	events.Recorder `json:"-"`
 }
 
 // NewUser creates a new user entity
 func NewUser(name, email string) *User {
 	now := time.Now()
-	return &User{
+	user := &User{
 		ID:        types.NewID(),
 		Name:      name,
 		Email:     email,
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
+	user.Record(events.NewUserCreated(user.ID, user.nextVersion(), name, email))
+	return user
 }
 
 // UpdateName updates the user's name
 func (u *User) UpdateName(name string) {
+	if name != u.Name {
+		u.Record(events.NewUserRenamed(u.ID, u.nextVersion(), u.Name, name))
+	}
 	u.Name = name
 	u.UpdatedAt = time.Now()
 }
 
 // UpdateEmail updates the user's email
 func (u *User) UpdateEmail(email string) {
+	if email != u.Email {
+		u.Record(events.NewUserEmailChanged(u.ID, u.nextVersion(), u.Email, email))
+	}
 	u.Email = email
 	u.UpdatedAt = time.Now()
 }
 
+// MarkDeleted soft-deletes the user; deleting a deleted user has no effect
+func (u *User) MarkDeleted() {
+	if u.IsDeleted() {
+		return
+	}
+	event := events.NewUserDeleted(u.ID, u.nextVersion(), u.Email)
+	deletedAt := event.OccurredAt()
+	u.DeletedAt = &deletedAt
+	u.UpdatedAt = deletedAt
+	u.Record(event)
+}
+
+// Restore brings back a soft-deleted user
+func (u *User) Restore() error {
+	if !u.IsDeleted() {
+		return ErrUserNotDeleted
+	}
+	u.DeletedAt = nil
+	u.UpdatedAt = time.Now()
+	u.Record(events.NewUserRestored(u.ID, u.nextVersion(), u.Email))
+	return nil
+}
+
+// MarkPurged records that a soft-deleted user is being removed for good
+func (u *User) MarkPurged() error {
+	if !u.IsDeleted() {
+		return ErrUserNotDeleted
+	}
+	u.Record(events.NewUserPurged(u.ID, u.nextVersion(), u.Email))
+	return nil
+}
+
+// IsDeleted checks if the user is soft-deleted
+func (u *User) IsDeleted() bool {
+	return u.DeletedAt != nil
+}
+
+// RequireTwoFactor sets whether the user must complete two-factor authentication
+func (u *User) RequireTwoFactor(required bool) {
+	if required != u.TwoFactorRequired {
+		u.Record(events.NewUserTwoFactorRequirementChanged(u.ID, u.nextVersion(), required))
+	}
+	u.TwoFactorRequired = required
+	u.UpdatedAt = time.Now()
+}
+
+// RecordLogin records that the user completed signing in with a method,
+// from a device if the sign-in was bound to one
+func (u *User) RecordLogin(method string, deviceID types.ID) {
+	event := events.NewUserLoggedIn(u.ID, u.nextSessionVersion(), method, deviceID)
+	loggedInAt := event.OccurredAt()
+	u.LastLoginAt = &loggedInAt
+	u.Record(event)
+}
+
+// RecordLogout records that the user ended their session
+func (u *User) RecordLogout(deviceID types.ID) {
+	u.Record(events.NewUserLoggedOut(u.ID, u.nextSessionVersion(), deviceID))
+}
+
+// nextVersion advances the version for a change about to be recorded
+func (u *User) nextVersion() int64 {
+	u.Version++
+	u.ProfileVersion = u.Version
+	return u.Version
+}
+
+// nextSessionVersion advances the version for a sign-in or sign-out about to
+// be recorded, leaving the profile version as it is
+func (u *User) nextSessionVersion() int64 {
+	u.Version++
+	return u.Version
+}
+
+// HasRole checks if the user has been assigned a role
+func (u *User) HasRole(role string) bool {
+	for _, assigned := range u.Roles {
+		if assigned == role {
+			return true
+		}
+	}
+	return false
+}
+
+// AssignRole assigns a role to the user
+func (u *User) AssignRole(role string) error {
+	if u.HasRole(role) {
+		return ErrRoleAlreadyAssigned
+	}
+	u.Roles = append(append([]string(nil), u.Roles...), role)
+	u.UpdatedAt = time.Now()
+	u.Record(events.NewUserRoleAssigned(u.ID, u.nextVersion(), role))
+	return nil
+}
+
+// RevokeRole removes a role from the user
+func (u *User) RevokeRole(role string) error {
+	roles := make([]string, 0, len(u.Roles))
+	for _, assigned := range u.Roles {
+		if assigned != role {
+			roles = append(roles, assigned)
+		}
+	}
+	if len(roles) == len(u.Roles) {
+		return ErrRoleNotAssigned
+	}
+	u.Roles = roles
+	u.UpdatedAt = time.Now()
+	u.Record(events.NewUserRoleRevoked(u.ID, u.nextVersion(), role))
+	return nil
+}
+
+// Apply replays an event from the user's history without recording it again.
+// Event-sourced storage rebuilds users by applying their events in order.
+func (u *User) Apply(event events.Event) {
+	u.Version = event.AggregateVersion()
+	if !events.IsSessionEvent(event) {
+		u.ProfileVersion = u.Version
+	}
+
+	switch e := event.(type) {
+	case events.UserCreated:
+		u.ID = e.AggregateID()
+		u.Name = e.Name
+		u.Email = e.Email
+		u.CreatedAt = e.OccurredAt()
+	case events.UserRenamed:
+		u.Name = e.NewName
+	case events.UserEmailChanged:
+		u.Email = e.NewEmail
+	case events.UserRoleAssigned:
+		if !u.HasRole(e.Role) {
+			u.Roles = append(append([]string(nil), u.Roles...), e.Role)
+		}
+	case events.UserRoleRevoked:
+		roles := make([]string, 0, len(u.Roles))
+		for _, assigned := range u.Roles {
+			if assigned != e.Role {
+				roles = append(roles, assigned)
+			}
+		}
+		u.Roles = roles
+	case events.UserTwoFactorRequirementChanged:
+		u.TwoFactorRequired = e.Required
+	case events.UserDeleted:
+		deletedAt := e.OccurredAt()
+		u.DeletedAt = &deletedAt
+	case events.UserRestored:
+		u.DeletedAt = nil
+	case events.UserLoggedIn:
+		// Signing in does not change the user's profile
+		loggedInAt := e.OccurredAt()
+		u.LastLoginAt = &loggedInAt
+		return
+	default:
+		return
+	}
+	u.UpdatedAt = event.OccurredAt()
+}
+
 // Validate validates the user entity
 func (u *User) Validate() error {
 	if u.Name == "" {
@@ -0,0 +1,59 @@
+package entities
+
+import (
+	"time"
+
+	"shadow-id/pkg/types"
+)
+
+// UserSummary is a denormalized read model of a user combining the profile
+// with figures from other aggregates. It is built from domain events and may
+// briefly lag behind the write model.
+type UserSummary struct {
+	ID                types.ID   `json:"id"`
+	Name              string     `json:"name"`
+	Email             string     `json:"email"`
+	Roles             []string   `json:"roles"`
+	TwoFactorRequired bool       `json:"two_factor_required"`
+	DeviceIDs         []types.ID `json:"device_ids"`
+	LastLoginAt       *time.Time `json:"last_login_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+	DeletedAt         *time.Time `json:"deleted_at,omitempty"`
+
+	// Version is the version of the user the summary reflects
+	Version int64 `json:"version"`
+	// ProfileVersion is the user's profile version at that point
+	ProfileVersion int64 `json:"profile_version"`
+}
+
+// IsDeleted reports whether the user is soft-deleted
+func (s *UserSummary) IsDeleted() bool {
+	return s.DeletedAt != nil
+}
+
+// DeviceCount returns the number of devices registered to the user
+func (s *UserSummary) DeviceCount() int {
+	return len(s.DeviceIDs)
+}
+
+// AddDevice counts a device; adding a device twice has no effect
+func (s *UserSummary) AddDevice(deviceID types.ID) {
+	for _, existing := range s.DeviceIDs {
+		if existing == deviceID {
+			return
+		}
+	}
+	s.DeviceIDs = append(append([]types.ID(nil), s.DeviceIDs...), deviceID)
+}
+
+// RemoveDevice stops counting a device
+func (s *UserSummary) RemoveDevice(deviceID types.ID) {
+	deviceIDs := make([]types.ID, 0, len(s.DeviceIDs))
+	for _, existing := range s.DeviceIDs {
+		if existing != deviceID {
+			deviceIDs = append(deviceIDs, existing)
+		}
+	}
+	s.DeviceIDs = deviceIDs
+}
@@ -0,0 +1,45 @@
+package entities
+
+import (
+	"testing"
+
+	"shadow-id/pkg/types"
+)
+
+func TestSessionsDoNotAdvanceProfileVersion(t *testing.T) {
+	user := NewUser("Alice", "alice@example.com")
+	user.UpdateName("Alice Liddell")
+	read := user.ProfileVersion
+
+	deviceID := types.NewID()
+	user.RecordLogin("password", deviceID)
+	user.RecordLogout(deviceID)
+
+	if user.ProfileVersion != read {
+		t.Errorf("ProfileVersion = %d after a session, want %d", user.ProfileVersion, read)
+	}
+	if user.Version != read+2 {
+		t.Errorf("Version = %d, want every event counted (%d)", user.Version, read+2)
+	}
+
+	user.UpdateEmail("liddell@example.com")
+	if user.ProfileVersion != user.Version {
+		t.Errorf("ProfileVersion = %d after a profile change, want %d", user.ProfileVersion, user.Version)
+	}
+}
+
+func TestApplyRestoresProfileVersion(t *testing.T) {
+	user := NewUser("Alice", "alice@example.com")
+	user.UpdateName("Alice Liddell")
+	user.RecordLogin("password", types.ID(""))
+
+	replayed := &User{}
+	for _, event := range user.PullEvents() {
+		replayed.Apply(event)
+	}
+
+	if replayed.Version != user.Version || replayed.ProfileVersion != user.ProfileVersion {
+		t.Errorf("replayed versions = %d/%d, want %d/%d",
+			replayed.Version, replayed.ProfileVersion, user.Version, user.ProfileVersion)
+	}
+}
@@ -0,0 +1,56 @@
+package entities
+
+import (
+	"slices"
+	"time"
+
+	"shadow-id/pkg/types"
+)
+
+// TimelineAction names what a user did in a timeline entry
+type TimelineAction string
+
+// Timeline actions
+const (
+	TimelineLogin            TimelineAction = "login"
+	TimelineLogout           TimelineAction = "logout"
+	TimelineDeviceRegistered TimelineAction = "device_registered"
+	TimelineHeartbeat        TimelineAction = "heartbeat"
+)
+
+// TimelineActions returns every timeline action
+func TimelineActions() []TimelineAction {
+	return []TimelineAction{TimelineLogin, TimelineLogout, TimelineDeviceRegistered, TimelineHeartbeat}
+}
+
+// IsValid checks if the action is a known timeline action
+func (a TimelineAction) IsValid() bool {
+	return slices.Contains(TimelineActions(), a)
+}
+
+// TimelineEntry is a single thing a user did, from the device it was done
+// on if known. It is a read model built from domain events; its ID is the ID
+// of the event it was built from.
+type TimelineEntry struct {
+	ID         types.ID          `json:"id"`
+	UserID     types.ID          `json:"user_id"`
+	DeviceID   types.ID          `json:"device_id,omitempty"`
+	Action     TimelineAction    `json:"action"`
+	Details    map[string]string `json:"details,omitempty"`
+	OccurredAt time.Time         `json:"occurred_at"`
+}
+
+// TimelineFilter narrows a user's timeline to a device and to actions; empty
+// fields match everything
+type TimelineFilter struct {
+	DeviceID types.ID
+	Actions  []TimelineAction
+}
+
+// Matches checks if an entry passes the filter
+func (f TimelineFilter) Matches(entry *TimelineEntry) bool {
+	if !f.DeviceID.IsEmpty() && entry.DeviceID != f.DeviceID {
+		return false
+	}
+	return len(f.Actions) == 0 || slices.Contains(f.Actions, entry.Action)
+}
@@ -0,0 +1,56 @@
+package entities
+
+import (
+	"time"
+
+	"shadow-id/pkg/types"
+)
+
+// WebAuthnCredential represents a passkey registered by a user
+type WebAuthnCredential struct {
+	ID                types.ID   `json:"id"`
+	UserID            types.ID   `json:"user_id"`
+	DeviceID          types.ID   `json:"device_id,omitempty"`
+	Name              string     `json:"name"`
+	CredentialID      []byte     `json:"credential_id"`
+	PublicKey         []byte     `json:"-"`
+	AAGUID            []byte     `json:"aaguid"`
+	AttestationFormat string     `json:"attestation_format"`
+	SignCount         uint32     `json:"sign_count"`
+	CloneWarning      bool       `json:"clone_warning"`
+	CreatedAt         time.Time  `json:"created_at"`
+	LastUsedAt        *time.Time `json:"last_used_at,omitempty"`
+}
+
+// NewWebAuthnCredential creates a new passkey credential entity
+func NewWebAuthnCredential(userID, deviceID types.ID, name string, credentialID, publicKey, aaguid []byte, format string, signCount uint32) *WebAuthnCredential {
+	return &WebAuthnCredential{
+		ID:                types.NewID(),
+		UserID:            userID,
+		DeviceID:          deviceID,
+		Name:              name,
+		CredentialID:      credentialID,
+		PublicKey:         publicKey,
+		AAGUID:            aaguid,
+		AttestationFormat: format,
+		SignCount:         signCount,
+		CreatedAt:         time.Now(),
+	}
+}
+
+// RecordAssertion records a successful assertion and applies sign-counter
+// clone detection. Authenticators that do not implement a counter always
+// report zero; otherwise the counter must strictly increase, and a counter
+// that does not is evidence the credential was cloned.
+func (c *WebAuthnCredential) RecordAssertion(signCount uint32) error {
+	if signCount != 0 || c.SignCount != 0 {
+		if signCount <= c.SignCount {
+			c.CloneWarning = true
+			return ErrCredentialCloned
+		}
+	}
+	now := time.Now()
+	c.SignCount = signCount
+	c.LastUsedAt = &now
+	return nil
+}
@@ -0,0 +1,40 @@
+package entities
+
+import (
+	"time"
+
+	"shadow-id/pkg/types"
+)
+
+// WebAuthn ceremony types
+const (
+	CeremonyRegistration   = "registration"
+	CeremonyAuthentication = "authentication"
+)
+
+// WebAuthnSession holds the state of an in-flight WebAuthn ceremony
+type WebAuthnSession struct {
+	ID        types.ID  `json:"id"`
+	Ceremony  string    `json:"ceremony"`
+	UserID    types.ID  `json:"user_id,omitempty"`
+	DeviceID  types.ID  `json:"device_id,omitempty"`
+	Challenge []byte    `json:"-"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// NewWebAuthnSession creates a new ceremony session
+func NewWebAuthnSession(ceremony string, userID, deviceID types.ID, challenge []byte, ttl time.Duration) *WebAuthnSession {
+	return &WebAuthnSession{
+		ID:        types.NewID(),
+		Ceremony:  ceremony,
+		UserID:    userID,
+		DeviceID:  deviceID,
+		Challenge: challenge,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+}
+
+// IsExpired checks if the ceremony has timed out
+func (s *WebAuthnSession) IsExpired() bool {
+	return time.Now().After(s.ExpiresAt)
+}
@@ -0,0 +1,166 @@
+package events
+
+import (
+	"time"
+
+	"shadow-id/pkg/types"
+)
+
+// Event names for devices
+const (
+	DeviceRegisteredEvent = "device.registered"
+	DeviceRenamedEvent    = "device.renamed"
+	DeviceRevokedEvent    = "device.revoked"
+	DeviceApprovedEvent   = "device.approved"
+	DeviceSuspendedEvent  = "device.suspended"
+	DeviceHeartbeatEvent  = "device.heartbeat"
+	DeviceStaleEvent      = "device.stale"
+
+	DeviceFingerprintUpdatedEvent    = "device.fingerprint_updated"
+	DeviceFingerprintSuspiciousEvent = "device.fingerprint_suspicious"
+)
+
+// DeviceRegistered is raised when a device is registered to a user
+type DeviceRegistered struct {
+	Base
+	UserID   types.ID `json:"user_id"`
+	Name     string   `json:"name"`
+	Platform string   `json:"platform"`
+}
+
+// EventName returns the event name
+func (DeviceRegistered) EventName() string { return DeviceRegisteredEvent }
+
+// NewDeviceRegistered creates a device registered event
+func NewDeviceRegistered(deviceID types.ID, version int64, userID types.ID, name, platform string) DeviceRegistered {
+	return DeviceRegistered{Base: NewBase(deviceID, version), UserID: userID, Name: name, Platform: platform}
+}
+
+// DeviceRenamed is raised when a device's display name changes
+type DeviceRenamed struct {
+	Base
+	UserID  types.ID `json:"user_id"`
+	OldName string   `json:"old_name"`
+	NewName string   `json:"new_name"`
+}
+
+// EventName returns the event name
+func (DeviceRenamed) EventName() string { return DeviceRenamedEvent }
+
+// NewDeviceRenamed creates a device renamed event
+func NewDeviceRenamed(deviceID types.ID, version int64, userID types.ID, oldName, newName string) DeviceRenamed {
+	return DeviceRenamed{Base: NewBase(deviceID, version), UserID: userID, OldName: oldName, NewName: newName}
+}
+
+// DeviceRevoked is raised when a device is revoked
+type DeviceRevoked struct {
+	Base
+	UserID types.ID `json:"user_id"`
+}
+
+// EventName returns the event name
+func (DeviceRevoked) EventName() string { return DeviceRevokedEvent }
+
+// NewDeviceRevoked creates a device revoked event
+func NewDeviceRevoked(deviceID types.ID, version int64, userID types.ID) DeviceRevoked {
+	return DeviceRevoked{Base: NewBase(deviceID, version), UserID: userID}
+}
+
+// DeviceApproved is raised when a privileged user approves a device
+type DeviceApproved struct {
+	Base
+	UserID     types.ID `json:"user_id"`
+	ApprovedBy types.ID `json:"approved_by"`
+}
+
+// EventName returns the event name
+func (DeviceApproved) EventName() string { return DeviceApprovedEvent }
+
+// NewDeviceApproved creates a device approved event
+func NewDeviceApproved(deviceID types.ID, version int64, userID, approvedBy types.ID) DeviceApproved {
+	return DeviceApproved{Base: NewBase(deviceID, version), UserID: userID, ApprovedBy: approvedBy}
+}
+
+// DeviceSuspended is raised when a device is suspended
+type DeviceSuspended struct {
+	Base
+	UserID types.ID `json:"user_id"`
+	Reason string   `json:"reason,omitempty"`
+}
+
+// EventName returns the event name
+func (DeviceSuspended) EventName() string { return DeviceSuspendedEvent }
+
+// NewDeviceSuspended creates a device suspended event
+func NewDeviceSuspended(deviceID types.ID, version int64, userID types.ID, reason string) DeviceSuspended {
+	return DeviceSuspended{Base: NewBase(deviceID, version), UserID: userID, Reason: reason}
+}
+
+// DeviceHeartbeat is raised when a device reports that it is alive
+type DeviceHeartbeat struct {
+	Base
+	UserID     types.ID `json:"user_id"`
+	AppVersion string   `json:"app_version,omitempty"`
+	IPAddress  string   `json:"ip_address,omitempty"`
+
+	// Resumed reports whether the device had been marked stale
+	Resumed bool `json:"resumed,omitempty"`
+}
+
+// EventName returns the event name
+func (DeviceHeartbeat) EventName() string { return DeviceHeartbeatEvent }
+
+// NewDeviceHeartbeat creates a device heartbeat event
+func NewDeviceHeartbeat(deviceID types.ID, version int64, userID types.ID, appVersion, ipAddress string, resumed bool) DeviceHeartbeat {
+	return DeviceHeartbeat{Base: NewBase(deviceID, version), UserID: userID, AppVersion: appVersion, IPAddress: ipAddress, Resumed: resumed}
+}
+
+// DeviceMarkedStale is raised when a device is marked stale for not sending
+// heartbeats
+type DeviceMarkedStale struct {
+	Base
+	UserID     types.ID  `json:"user_id"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+}
+
+// EventName returns the event name
+func (DeviceMarkedStale) EventName() string { return DeviceStaleEvent }
+
+// NewDeviceMarkedStale creates a device marked stale event
+func NewDeviceMarkedStale(deviceID types.ID, version int64, userID types.ID, lastSeenAt time.Time) DeviceMarkedStale {
+	return DeviceMarkedStale{Base: NewBase(deviceID, version), UserID: userID, LastSeenAt: lastSeenAt}
+}
+
+// DeviceFingerprintUpdated is raised when a device's stored fingerprint is
+// replaced after a presented fingerprint matched it despite drifted components
+type DeviceFingerprintUpdated struct {
+	Base
+	UserID     types.ID `json:"user_id"`
+	Changed    []string `json:"changed"`
+	Confidence float64  `json:"confidence"`
+}
+
+// EventName returns the event name
+func (DeviceFingerprintUpdated) EventName() string { return DeviceFingerprintUpdatedEvent }
+
+// NewDeviceFingerprintUpdated creates a device fingerprint updated event
+func NewDeviceFingerprintUpdated(deviceID types.ID, version int64, userID types.ID, changed []string, confidence float64) DeviceFingerprintUpdated {
+	return DeviceFingerprintUpdated{Base: NewBase(deviceID, version), UserID: userID, Changed: changed, Confidence: confidence}
+}
+
+// DeviceFingerprintSuspicious is raised when a presented fingerprint
+// resembles a device's but differs in too many components to be trusted
+type DeviceFingerprintSuspicious struct {
+	Base
+	UserID     types.ID `json:"user_id"`
+	Changed    []string `json:"changed"`
+	Confidence float64  `json:"confidence"`
+}
+
+// EventName returns the event name
+func (DeviceFingerprintSuspicious) EventName() string { return DeviceFingerprintSuspiciousEvent }
+
+// NewDeviceFingerprintSuspicious creates a device fingerprint suspicious event
+func NewDeviceFingerprintSuspicious(deviceID types.ID, version int64, userID types.ID, changed []string, confidence float64) DeviceFingerprintSuspicious {
+	return DeviceFingerprintSuspicious{Base: NewBase(deviceID, version), UserID: userID, Changed: changed, Confidence: confidence}
+}
@@ -0,0 +1,92 @@
+package events
+
+import (
+	"time"
+
+	"shadow-id/pkg/types"
+)
+
+// Event is something that happened in the domain
+type Event interface {
+	// EventName identifies the kind of event, e.g. "user.created"
+	EventName() string
+
+	// EventID uniquely identifies this occurrence, letting subscribers drop
+	// duplicate deliveries
+	EventID() types.ID
+
+	// AggregateID identifies the entity the event belongs to
+	AggregateID() types.ID
+
+	// AggregateVersion is the version of the entity the event produced
+	AggregateVersion() int64
+
+	// OccurredAt is when the event happened
+	OccurredAt() time.Time
+}
+
+// Base holds the fields shared by every event
+type Base struct {
+	ID        types.ID  `json:"id"`
+	Aggregate types.ID  `json:"aggregate_id"`
+	Version   int64     `json:"version"`
+	At        time.Time `json:"occurred_at"`
+}
+
+// NewBase creates the shared part of an event that brings an aggregate to a version
+func NewBase(aggregateID types.ID, version int64) Base {
+	return Base{
+		ID:        types.NewID(),
+		Aggregate: aggregateID,
+		Version:   version,
+		At:        time.Now(),
+	}
+}
+
+// EventID returns the unique ID of the event occurrence
+func (b Base) EventID() types.ID {
+	return b.ID
+}
+
+// AggregateID returns the ID of the entity the event belongs to
+func (b Base) AggregateID() types.ID {
+	return b.Aggregate
+}
+
+// AggregateVersion returns the version of the entity the event produced
+func (b Base) AggregateVersion() int64 {
+	return b.Version
+}
+
+// OccurredAt returns when the event happened
+func (b Base) OccurredAt() time.Time {
+	return b.At
+}
+
+// Recorder collects the events an entity raises until they are published.
+// Entities embed it; pending events are never persisted.
+type Recorder struct {
+	pending []Event
+}
+
+// Record appends an event to the pending events
+func (r *Recorder) Record(event Event) {
+	r.pending = append(r.pending, event)
+}
+
+// PendingCount returns the number of events waiting to be published
+func (r *Recorder) PendingCount() int {
+	return len(r.pending)
+}
+
+// PullEvents returns the pending events and clears them
+func (r *Recorder) PullEvents() []Event {
+	pending := r.pending
+	r.pending = nil
+	return pending
+}
+
+// Source is implemented by entities that record events
+type Source interface {
+	PullEvents() []Event
+}
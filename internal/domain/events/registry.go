@@ -0,0 +1,59 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// decoder rebuilds an event from its serialized payload
+type decoder func(payload []byte) (Event, error)
+
+// registry maps event names to their decoders
+var registry = map[string]decoder{
+	UserCreatedEvent:      decode[UserCreated],
+	UserEmailChangedEvent: decode[UserEmailChanged],
+	UserDeletedEvent:      decode[UserDeleted],
+	UserRenamedEvent:      decode[UserRenamed],
+	UserRoleAssignedEvent: decode[UserRoleAssigned],
+	UserRoleRevokedEvent:  decode[UserRoleRevoked],
+	UserLoggedInEvent:     decode[UserLoggedIn],
+	UserLoggedOutEvent:    decode[UserLoggedOut],
+	UserRestoredEvent:     decode[UserRestored],
+	UserPurgedEvent:       decode[UserPurged],
+
+	UserTwoFactorRequirementChangedEvent: decode[UserTwoFactorRequirementChanged],
+
+	DeviceRegisteredEvent: decode[DeviceRegistered],
+	DeviceRenamedEvent:    decode[DeviceRenamed],
+	DeviceRevokedEvent:    decode[DeviceRevoked],
+	DeviceApprovedEvent:   decode[DeviceApproved],
+	DeviceSuspendedEvent:  decode[DeviceSuspended],
+	DeviceHeartbeatEvent:  decode[DeviceHeartbeat],
+	DeviceStaleEvent:      decode[DeviceMarkedStale],
+
+	DeviceFingerprintUpdatedEvent:    decode[DeviceFingerprintUpdated],
+	DeviceFingerprintSuspiciousEvent: decode[DeviceFingerprintSuspicious],
+}
+
+// Encode serializes an event for storage
+func Encode(event Event) ([]byte, error) {
+	return json.Marshal(event)
+}
+
+// Decode rebuilds a stored event from its name and payload
+func Decode(name string, payload []byte) (Event, error) {
+	decodeEvent, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown event %q", name)
+	}
+	return decodeEvent(payload)
+}
+
+// decode unmarshals a payload into an event of type T
+func decode[T Event](payload []byte) (Event, error) {
+	var event T
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
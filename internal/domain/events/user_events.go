@@ -0,0 +1,192 @@
+package events
+
+import "shadow-id/pkg/types"
+
+// Event names for users
+const (
+	UserCreatedEvent      = "user.created"
+	UserEmailChangedEvent = "user.email_changed"
+	UserDeletedEvent      = "user.deleted"
+
+	UserRenamedEvent                     = "user.renamed"
+	UserRoleAssignedEvent                = "user.role_assigned"
+	UserRoleRevokedEvent                 = "user.role_revoked"
+	UserTwoFactorRequirementChangedEvent = "user.two_factor_requirement_changed"
+	UserLoggedInEvent                    = "user.logged_in"
+	UserLoggedOutEvent                   = "user.logged_out"
+	UserRestoredEvent                    = "user.restored"
+	UserPurgedEvent                      = "user.purged"
+)
+
+// UserCreated is raised when a user is created
+type UserCreated struct {
+	Base
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// EventName returns the event name
+func (UserCreated) EventName() string { return UserCreatedEvent }
+
+// NewUserCreated creates a user created event
+func NewUserCreated(userID types.ID, version int64, name, email string) UserCreated {
+	return UserCreated{Base: NewBase(userID, version), Name: name, Email: email}
+}
+
+// UserEmailChanged is raised when a user's email address changes
+type UserEmailChanged struct {
+	Base
+	OldEmail string `json:"old_email"`
+	NewEmail string `json:"new_email"`
+}
+
+// EventName returns the event name
+func (UserEmailChanged) EventName() string { return UserEmailChangedEvent }
+
+// NewUserEmailChanged creates a user email changed event
+func NewUserEmailChanged(userID types.ID, version int64, oldEmail, newEmail string) UserEmailChanged {
+	return UserEmailChanged{Base: NewBase(userID, version), OldEmail: oldEmail, NewEmail: newEmail}
+}
+
+// UserDeleted is raised when a user is deleted. The user is kept until it
+// is purged and may be restored in the meantime.
+type UserDeleted struct {
+	Base
+	Email string `json:"email"`
+}
+
+// EventName returns the event name
+func (UserDeleted) EventName() string { return UserDeletedEvent }
+
+// NewUserDeleted creates a user deleted event
+func NewUserDeleted(userID types.ID, version int64, email string) UserDeleted {
+	return UserDeleted{Base: NewBase(userID, version), Email: email}
+}
+
+// UserRenamed is raised when a user's name changes
+type UserRenamed struct {
+	Base
+	OldName string `json:"old_name"`
+	NewName string `json:"new_name"`
+}
+
+// EventName returns the event name
+func (UserRenamed) EventName() string { return UserRenamedEvent }
+
+// NewUserRenamed creates a user renamed event
+func NewUserRenamed(userID types.ID, version int64, oldName, newName string) UserRenamed {
+	return UserRenamed{Base: NewBase(userID, version), OldName: oldName, NewName: newName}
+}
+
+// UserRoleAssigned is raised when a role is assigned to a user
+type UserRoleAssigned struct {
+	Base
+	Role string `json:"role"`
+}
+
+// EventName returns the event name
+func (UserRoleAssigned) EventName() string { return UserRoleAssignedEvent }
+
+// NewUserRoleAssigned creates a user role assigned event
+func NewUserRoleAssigned(userID types.ID, version int64, role string) UserRoleAssigned {
+	return UserRoleAssigned{Base: NewBase(userID, version), Role: role}
+}
+
+// UserRoleRevoked is raised when a role is removed from a user
+type UserRoleRevoked struct {
+	Base
+	Role string `json:"role"`
+}
+
+// EventName returns the event name
+func (UserRoleRevoked) EventName() string { return UserRoleRevokedEvent }
+
+// NewUserRoleRevoked creates a user role revoked event
+func NewUserRoleRevoked(userID types.ID, version int64, role string) UserRoleRevoked {
+	return UserRoleRevoked{Base: NewBase(userID, version), Role: role}
+}
+
+// UserTwoFactorRequirementChanged is raised when two-factor authentication
+// becomes required or optional for a user
+type UserTwoFactorRequirementChanged struct {
+	Base
+	Required bool `json:"required"`
+}
+
+// EventName returns the event name
+func (UserTwoFactorRequirementChanged) EventName() string {
+	return UserTwoFactorRequirementChangedEvent
+}
+
+// NewUserTwoFactorRequirementChanged creates a user two-factor requirement changed event
+func NewUserTwoFactorRequirementChanged(userID types.ID, version int64, required bool) UserTwoFactorRequirementChanged {
+	return UserTwoFactorRequirementChanged{Base: NewBase(userID, version), Required: required}
+}
+
+// UserLoggedIn is raised when a user completes signing in, from a device if
+// the sign-in was bound to one
+type UserLoggedIn struct {
+	Base
+	Method   string   `json:"method"`
+	DeviceID types.ID `json:"device_id,omitempty"`
+}
+
+// EventName returns the event name
+func (UserLoggedIn) EventName() string { return UserLoggedInEvent }
+
+// NewUserLoggedIn creates a user logged in event
+func NewUserLoggedIn(userID types.ID, version int64, method string, deviceID types.ID) UserLoggedIn {
+	return UserLoggedIn{Base: NewBase(userID, version), Method: method, DeviceID: deviceID}
+}
+
+// UserLoggedOut is raised when a user ends their session
+type UserLoggedOut struct {
+	Base
+	DeviceID types.ID `json:"device_id,omitempty"`
+}
+
+// EventName returns the event name
+func (UserLoggedOut) EventName() string { return UserLoggedOutEvent }
+
+// NewUserLoggedOut creates a user logged out event
+func NewUserLoggedOut(userID types.ID, version int64, deviceID types.ID) UserLoggedOut {
+	return UserLoggedOut{Base: NewBase(userID, version), DeviceID: deviceID}
+}
+
+// UserRestored is raised when a deleted user is restored
+type UserRestored struct {
+	Base
+	Email string `json:"email"`
+}
+
+// EventName returns the event name
+func (UserRestored) EventName() string { return UserRestoredEvent }
+
+// NewUserRestored creates a user restored event
+func NewUserRestored(userID types.ID, version int64, email string) UserRestored {
+	return UserRestored{Base: NewBase(userID, version), Email: email}
+}
+
+// UserPurged is raised when a deleted user is removed for good
+type UserPurged struct {
+	Base
+	Email string `json:"email"`
+}
+
+// EventName returns the event name
+func (UserPurged) EventName() string { return UserPurgedEvent }
+
+// NewUserPurged creates a user purged event
+func NewUserPurged(userID types.ID, version int64, email string) UserPurged {
+	return UserPurged{Base: NewBase(userID, version), Email: email}
+}
+
+// IsSessionEvent reports whether the event records a user signing in or out
+// rather than a change to the user
+func IsSessionEvent(event Event) bool {
+	switch event.(type) {
+	case UserLoggedIn, UserLoggedOut:
+		return true
+	}
+	return false
+}
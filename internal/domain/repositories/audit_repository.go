@@ -0,0 +1,27 @@
+package repositories
+
+import (
+	"context"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/pkg/types"
+)
+
+// AuditRepository defines the interface for the append-only audit log.
+// There is deliberately no way to update or delete an entry.
+type AuditRepository interface {
+	// Append chains the entry after the latest one and stores it
+	Append(ctx context.Context, entry *entities.AuditEntry) error
+
+	// List retrieves entries in sequence order with pagination
+	List(ctx context.Context, limit, offset int) ([]*entities.AuditEntry, error)
+
+	// ListByTarget retrieves the entries for commands that acted on an entity, in sequence order
+	ListByTarget(ctx context.Context, targetID types.ID, limit, offset int) ([]*entities.AuditEntry, error)
+
+	// ListByActor retrieves the entries for commands a user issued, in sequence order
+	ListByActor(ctx context.Context, actorID types.ID, limit, offset int) ([]*entities.AuditEntry, error)
+
+	// Count returns the total number of entries
+	Count(ctx context.Context) (int64, error)
+}
@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/pkg/types"
+)
+
+// DeviceRepository defines the interface for device data operations.
+// Writes store the events the device recorded in the outbox atomically with the change.
+type DeviceRepository interface {
+	// Create creates a new device
+	Create(ctx context.Context, device *entities.Device) error
+
+	// GetByID retrieves a device by ID
+	GetByID(ctx context.Context, id types.ID) (*entities.Device, error)
+
+	// ListByUserID retrieves all devices registered to a user
+	ListByUserID(ctx context.Context, userID types.ID) ([]*entities.Device, error)
+
+	// ListByTrustState retrieves a page of devices in a trust state, oldest first
+	ListByTrustState(ctx context.Context, state entities.DeviceTrustState, limit, offset int) ([]*entities.Device, error)
+
+	// CountByTrustState returns the number of devices in a trust state
+	CountByTrustState(ctx context.Context, state entities.DeviceTrustState) (int64, error)
+
+	// ListUnseenSince retrieves up to limit devices that are neither revoked
+	// nor marked stale and were last seen before a time, longest unseen first
+	ListUnseenSince(ctx context.Context, before time.Time, limit int) ([]*entities.Device, error)
+
+	// CountByStaleness returns the number of devices that are not revoked and
+	// are or are not marked stale
+	CountByStaleness(ctx context.Context, stale bool) (int64, error)
+
+	// Update updates an existing device
+	Update(ctx context.Context, device *entities.Device) error
+
+	// Delete deletes a device
+	Delete(ctx context.Context, device *entities.Device) error
+}
+
+// DeviceChallengeRepository defines the interface for challenges issued to devices
+type DeviceChallengeRepository interface {
+	// Create stores a new challenge
+	Create(ctx context.Context, challenge *entities.DeviceChallenge) error
+
+	// Take retrieves and removes a challenge so it can only be answered once
+	Take(ctx context.Context, id types.ID) (*entities.DeviceChallenge, error)
+}
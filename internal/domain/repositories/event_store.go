@@ -0,0 +1,34 @@
+package repositories
+
+import (
+	"context"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/events"
+	"shadow-id/pkg/types"
+)
+
+// AnyVersion lets an append proceed whatever the stream's current version is
+const AnyVersion int64 = -1
+
+// EventStore defines the interface for storing aggregates as event streams.
+// Appended events are stored in the outbox atomically with the stream.
+type EventStore interface {
+	// Append adds events to the end of a stream and returns the stream's new
+	// version. It fails with ErrVersionConflict unless the stream is at
+	// the expected version; zero expects a new stream.
+	Append(ctx context.Context, streamID types.ID, expectedVersion int64, evts []events.Event) (int64, error)
+
+	// Load retrieves a stream's events after the given version, in order
+	Load(ctx context.Context, streamID types.ID, afterVersion int64) ([]*entities.StoredEvent, error)
+
+	// SaveSnapshot stores a snapshot, replacing any older one for the stream
+	SaveSnapshot(ctx context.Context, snapshot *entities.Snapshot) error
+
+	// LoadSnapshot retrieves the latest snapshot of a stream, or nil if there is none
+	LoadSnapshot(ctx context.Context, streamID types.ID) (*entities.Snapshot, error)
+
+	// DeleteStream removes a stream and its snapshot for good. Events already
+	// stored in the outbox are kept.
+	DeleteStream(ctx context.Context, streamID types.ID) error
+}
@@ -0,0 +1,20 @@
+package repositories
+
+import (
+	"context"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/pkg/types"
+)
+
+// LoginThrottleRepository defines the interface for failed-attempt tracking data operations
+type LoginThrottleRepository interface {
+	// Get retrieves the throttle for a subject in a scope
+	Get(ctx context.Context, scope string, subjectID types.ID) (*entities.LoginThrottle, error)
+
+	// Save creates or replaces a throttle
+	Save(ctx context.Context, throttle *entities.LoginThrottle) error
+
+	// Delete removes the throttle for a subject in a scope
+	Delete(ctx context.Context, scope string, subjectID types.ID) error
+}
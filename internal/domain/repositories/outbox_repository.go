@@ -0,0 +1,26 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"shadow-id/internal/domain/entities"
+)
+
+// OutboxRepository defines the interface for outbox delivery bookkeeping.
+// Entries are written by the entity repositories in the same write as the
+// change that raised them. Delivered entries are retained, so the outbox
+// doubles as the event history projections are rebuilt from.
+type OutboxRepository interface {
+	// ListDue retrieves pending entries due for delivery in the order they
+	// were written, leaving out the entries of aggregates that have an
+	// earlier pending entry that is not due yet
+	ListDue(ctx context.Context, now time.Time, limit int) ([]*entities.OutboxEntry, error)
+
+	// Update saves the delivery state of an entry
+	Update(ctx context.Context, entry *entities.OutboxEntry) error
+
+	// History retrieves entries in the order they were written, whatever
+	// their delivery state, with pagination
+	History(ctx context.Context, limit, offset int) ([]*entities.OutboxEntry, error)
+}
@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"context"
+
+	"shadow-id/internal/domain/entities"
+)
+
+// RoleRepository defines the interface for role data operations
+type RoleRepository interface {
+	// Create creates a new role
+	Create(ctx context.Context, role *entities.Role) error
+
+	// GetByName retrieves a role by name
+	GetByName(ctx context.Context, name string) (*entities.Role, error)
+
+	// List retrieves all roles
+	List(ctx context.Context) ([]*entities.Role, error)
+}
@@ -0,0 +1,23 @@
+package repositories
+
+import (
+	"context"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/pkg/types"
+)
+
+// TwoFactorRepository defines the interface for two-factor enrollment data operations
+type TwoFactorRepository interface {
+	// Create stores a new enrollment, replacing any unconfirmed one for the user
+	Create(ctx context.Context, twoFactor *entities.TwoFactor) error
+
+	// GetByUserID retrieves the enrollment for a user
+	GetByUserID(ctx context.Context, userID types.ID) (*entities.TwoFactor, error)
+
+	// Update updates an existing enrollment
+	Update(ctx context.Context, twoFactor *entities.TwoFactor) error
+
+	// Delete deletes the enrollment for a user
+	Delete(ctx context.Context, userID types.ID) error
+}
@@ -0,0 +1,12 @@
+package repositories
+
+import "context"
+
+// UnitOfWork groups repository changes into a transaction
+type UnitOfWork interface {
+	// WithinTx runs fn in a transaction and commits the changes it made
+	// through the context it is given, or rolls all of them back if fn fails
+	// or panics. Calls made with a context that already carries a transaction
+	// join it instead of starting a new one.
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -2,12 +2,15 @@ package repositories
 
 import (
 	"context"
+	"time"
 
 	"shadow-id/internal/domain/entities"
 	"shadow-id/pkg/types"
 )
 
-// UserRepository defines the interface for user data operations
+// UserRepository defines the interface for user data operations.
+// Writes store the events the user recorded in the outbox atomically with the change.
+// Soft-deleted users are left out of every lookup except GetDeleted and ListDeleted.
 type UserRepository interface {
 	// Create creates a new user
 	Create(ctx context.Context, user *entities.User) error
@@ -18,11 +21,22 @@ type UserRepository interface {
 	// GetByEmail retrieves a user by email
 	GetByEmail(ctx context.Context, email string) (*entities.User, error)
 	
-	// Update updates an existing user
+	// Update updates an existing user, including a soft-deleted one
 	Update(ctx context.Context, user *entities.User) error
 	
-	// Delete deletes a user by ID
-	Delete(ctx context.Context, id types.ID) error
+	// Delete stores the soft deletion of a user marked with MarkDeleted; the
+	// record is kept until it is purged
+	Delete(ctx context.Context, user *entities.User) error
+	
+	// Purge removes a soft-deleted user marked with MarkPurged for good
+	Purge(ctx context.Context, user *entities.User) error
+	
+	// GetDeleted retrieves a soft-deleted user by ID
+	GetDeleted(ctx context.Context, id types.ID) (*entities.User, error)
+	
+	// ListDeleted retrieves up to limit users soft-deleted before the given
+	// time, longest deleted first
+	ListDeleted(ctx context.Context, deletedBefore time.Time, limit int) ([]*entities.User, error)
 	
 	// List retrieves all users with pagination
 	List(ctx context.Context, limit, offset int) ([]*entities.User, error)
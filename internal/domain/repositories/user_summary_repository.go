@@ -0,0 +1,31 @@
+package repositories
+
+import (
+	"context"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/pkg/types"
+)
+
+// UserSummaryRepository defines the interface for the user summary read model
+type UserSummaryRepository interface {
+	// Save creates or replaces a summary
+	Save(ctx context.Context, summary *entities.UserSummary) error
+
+	// GetByID retrieves a summary by user ID
+	GetByID(ctx context.Context, id types.ID) (*entities.UserSummary, error)
+
+	// List retrieves summaries ordered by creation time with pagination.
+	// Soft-deleted users are skipped unless includeDeleted is set.
+	List(ctx context.Context, limit, offset int, includeDeleted bool) ([]*entities.UserSummary, error)
+
+	// Count returns the number of summaries, counting soft-deleted users only
+	// if includeDeleted is set
+	Count(ctx context.Context, includeDeleted bool) (int64, error)
+
+	// Delete removes a summary; removing a missing summary is not an error
+	Delete(ctx context.Context, id types.ID) error
+
+	// Clear removes every summary ahead of a rebuild
+	Clear(ctx context.Context) error
+}
@@ -0,0 +1,26 @@
+package repositories
+
+import (
+	"context"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/pkg/types"
+)
+
+// UserTimelineRepository defines the interface for the user timeline read model
+type UserTimelineRepository interface {
+	// Append stores an entry; appending an entry with a stored ID has no effect
+	Append(ctx context.Context, entry *entities.TimelineEntry) error
+
+	// List retrieves a page of a user's entries that pass a filter, newest first
+	List(ctx context.Context, userID types.ID, filter entities.TimelineFilter, limit, offset int) ([]*entities.TimelineEntry, error)
+
+	// Count returns the number of a user's entries that pass a filter
+	Count(ctx context.Context, userID types.ID, filter entities.TimelineFilter) (int64, error)
+
+	// DeleteByUserID removes every entry of a user
+	DeleteByUserID(ctx context.Context, userID types.ID) error
+
+	// Clear removes every entry ahead of a rebuild
+	Clear(ctx context.Context) error
+}
@@ -0,0 +1,38 @@
+package repositories
+
+import (
+	"context"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/pkg/types"
+)
+
+// WebAuthnCredentialRepository defines the interface for passkey credential data operations
+type WebAuthnCredentialRepository interface {
+	// Create creates a new credential
+	Create(ctx context.Context, credential *entities.WebAuthnCredential) error
+
+	// GetByCredentialID retrieves a credential by its authenticator-assigned ID
+	GetByCredentialID(ctx context.Context, credentialID []byte) (*entities.WebAuthnCredential, error)
+
+	// ListByUserID retrieves all credentials registered to a user
+	ListByUserID(ctx context.Context, userID types.ID) ([]*entities.WebAuthnCredential, error)
+
+	// Update updates an existing credential
+	Update(ctx context.Context, credential *entities.WebAuthnCredential) error
+
+	// Delete deletes a credential by ID
+	Delete(ctx context.Context, id types.ID) error
+}
+
+// WebAuthnSessionRepository defines the interface for in-flight ceremony state
+type WebAuthnSessionRepository interface {
+	// Create stores a new ceremony session
+	Create(ctx context.Context, session *entities.WebAuthnSession) error
+
+	// GetByID retrieves a ceremony session without consuming it
+	GetByID(ctx context.Context, id types.ID) (*entities.WebAuthnSession, error)
+
+	// Take retrieves and removes a ceremony session so it can only be used once
+	Take(ctx context.Context, id types.ID) (*entities.WebAuthnSession, error)
+}
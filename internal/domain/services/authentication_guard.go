@@ -0,0 +1,20 @@
+package services
+
+import (
+	"context"
+
+	"shadow-id/pkg/types"
+)
+
+// AuthenticationGuard defines brute-force protection around credential checks.
+// Every check is keyed by account and, when known, by device.
+type AuthenticationGuard interface {
+	// Check returns an error if an attempt is currently not allowed
+	Check(ctx context.Context, userID, deviceID types.ID) error
+
+	// RecordFailure registers a failed attempt
+	RecordFailure(ctx context.Context, userID, deviceID types.ID) error
+
+	// RecordSuccess clears failures after a successful attempt
+	RecordSuccess(ctx context.Context, userID, deviceID types.ID) error
+}
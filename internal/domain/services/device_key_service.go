@@ -0,0 +1,17 @@
+package services
+
+// DeviceKeyService defines the key operations devices prove their identity with
+type DeviceKeyService interface {
+	// GenerateKeyPair creates a new device key pair
+	GenerateKeyPair() (publicKey, privateKey []byte, err error)
+
+	// NewNonce generates a random challenge nonce
+	NewNonce() ([]byte, error)
+
+	// Sign signs a message with a device's private key
+	Sign(privateKey, message []byte) ([]byte, error)
+
+	// Verify checks a device's signature over a message. It fails with
+	// ErrInvalidDeviceSignature if the signature does not match.
+	Verify(publicKey, message, signature []byte) error
+}
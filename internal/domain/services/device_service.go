@@ -0,0 +1,15 @@
+package services
+
+import (
+	"context"
+
+	"shadow-id/internal/domain/entities"
+)
+
+// DeviceService defines domain services for the machine the application runs on
+type DeviceService interface {
+	// CurrentFingerprint collects the local machine's attributes and returns
+	// their fingerprint. It fails with ErrFingerprintUnavailable if no
+	// attribute could be read.
+	CurrentFingerprint(ctx context.Context) (*entities.DeviceFingerprint, error)
+}
@@ -0,0 +1,15 @@
+package services
+
+import (
+	"context"
+
+	"shadow-id/internal/domain/events"
+)
+
+// EventPublisher delivers domain events to interested subscribers
+type EventPublisher interface {
+	// Publish delivers events in order. Every subscriber is invoked even if
+	// another fails; the returned error reports failed synchronous subscribers
+	// so the caller can retry the delivery.
+	Publish(ctx context.Context, evts ...events.Event) error
+}
@@ -0,0 +1,38 @@
+package services
+
+import (
+	"context"
+
+	"shadow-id/internal/domain/entities"
+)
+
+// PolicyEvaluation records how a single policy responded to a request
+type PolicyEvaluation struct {
+	PolicyID string                `json:"policy_id"`
+	Effect   entities.PolicyEffect `json:"effect"`
+	Matched  bool                  `json:"matched"`
+	DryRun   bool                  `json:"dry_run"`
+	Reason   string                `json:"reason"`
+}
+
+// PolicyDecision is the explained outcome of evaluating a request.
+// Effect is what the policies decided; Allowed is what is enforced, which
+// differs from Effect only when the engine runs in dry-run mode.
+type PolicyDecision struct {
+	Allowed     bool                  `json:"allowed"`
+	Effect      entities.PolicyEffect `json:"effect"`
+	PolicyID    string                `json:"policy_id,omitempty"`
+	Reason      string                `json:"reason"`
+	DryRun      bool                  `json:"dry_run"`
+	Evaluations []PolicyEvaluation    `json:"evaluations"`
+}
+
+// PolicyEngine evaluates attribute-based access policies
+type PolicyEngine interface {
+	// Evaluate decides a request. Deny policies override allow policies and
+	// requests no policy matches are not applicable and allowed.
+	Evaluate(ctx context.Context, request entities.PolicyRequest) PolicyDecision
+
+	// Policies returns the loaded policies
+	Policies() []entities.Policy
+}
@@ -0,0 +1,23 @@
+package services
+
+import (
+	"context"
+	"time"
+)
+
+// ProjectionRebuild reports the outcome of rebuilding one projection
+type ProjectionRebuild struct {
+	Name     string        `json:"name"`
+	Events   int           `json:"events"`
+	Duration time.Duration `json:"duration"`
+}
+
+// ProjectionRebuilder regenerates read models from the event history
+type ProjectionRebuilder interface {
+	// Rebuild clears the named projection, or every projection if the name
+	// is empty, and replays the full event history into it
+	Rebuild(ctx context.Context, name string) ([]ProjectionRebuild, error)
+
+	// Projections returns the names of the registered projections
+	Projections() []string
+}
@@ -0,0 +1,16 @@
+package services
+
+import (
+	"context"
+	"time"
+)
+
+// RateLimiter defines a pluggable limiter for attempts keyed by an arbitrary string
+type RateLimiter interface {
+	// Allow consumes one unit for the key and reports whether it was permitted,
+	// along with how long to wait before retrying when it was not
+	Allow(ctx context.Context, key string) (bool, time.Duration)
+
+	// Reset clears any state held for the key
+	Reset(ctx context.Context, key string)
+}
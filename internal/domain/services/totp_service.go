@@ -0,0 +1,22 @@
+package services
+
+import "time"
+
+// TOTPService defines domain services for time-based one-time passwords
+type TOTPService interface {
+	// GenerateSecret generates a new base32-encoded shared secret
+	GenerateSecret() (string, error)
+
+	// ProvisioningURI builds the otpauth:// URI used to enroll an authenticator app
+	ProvisioningURI(secret, accountName string) string
+
+	// Validate checks a code against the secret within the configured clock-skew
+	// window and returns the matching time step
+	Validate(secret, code string, at time.Time) (int64, bool)
+
+	// GenerateRecoveryCodes generates a set of plaintext single-use recovery codes
+	GenerateRecoveryCodes() ([]string, error)
+
+	// HashRecoveryCode hashes a recovery code for storage and comparison
+	HashRecoveryCode(code string) string
+}
@@ -0,0 +1,54 @@
+package services
+
+// WebAuthnRelyingParty describes the relying party presented to authenticators
+type WebAuthnRelyingParty struct {
+	ID             string
+	Name           string
+	Algorithms     []int64
+	TimeoutSeconds int
+}
+
+// WebAuthnRegistrationResponse holds the authenticator response to a registration ceremony
+type WebAuthnRegistrationResponse struct {
+	ClientDataJSON    []byte
+	AttestationObject []byte
+}
+
+// WebAuthnAssertionResponse holds the authenticator response to an authentication ceremony
+type WebAuthnAssertionResponse struct {
+	ClientDataJSON    []byte
+	AuthenticatorData []byte
+	Signature         []byte
+}
+
+// WebAuthnAttestedCredential holds a credential verified during registration
+type WebAuthnAttestedCredential struct {
+	CredentialID      []byte
+	PublicKey         []byte
+	AAGUID            []byte
+	AttestationFormat string
+	SignCount         uint32
+	UserVerified      bool
+}
+
+// WebAuthnAssertion holds the verified result of an authentication ceremony
+type WebAuthnAssertion struct {
+	SignCount    uint32
+	UserVerified bool
+}
+
+// WebAuthnService defines the relying-party operations for WebAuthn ceremonies
+type WebAuthnService interface {
+	// RelyingParty returns the relying party description
+	RelyingParty() WebAuthnRelyingParty
+
+	// NewChallenge generates a random ceremony challenge
+	NewChallenge() ([]byte, error)
+
+	// VerifyRegistration verifies an attestation response against the issued challenge
+	VerifyRegistration(challenge []byte, response WebAuthnRegistrationResponse) (*WebAuthnAttestedCredential, error)
+
+	// VerifyAssertion verifies an assertion response against the issued challenge
+	// and the stored COSE public key
+	VerifyAssertion(challenge, publicKey []byte, response WebAuthnAssertionResponse) (*WebAuthnAssertion, error)
+}
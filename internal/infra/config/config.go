@@ -2,6 +2,9 @@ package config
 
 import (
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // Config holds application configuration
@@ -10,12 +13,30 @@ type Config struct {
 	Version     string `json:"version"`
 	Environment string `json:"environment"`
 	LogLevel    string `json:"log_level"`
-	
+
+	// HandlerTimeout bounds how long a single command or query may run
+	HandlerTimeout time.Duration `json:"handler_timeout"`
+
 	// Database configuration (for future use)
 	Database DatabaseConfig `json:"database"`
-	
-	// Server configuration (for future use)
+
+	// HTTP API server configuration
 	Server ServerConfig `json:"server"`
+
+	// Security configuration
+	Security SecurityConfig `json:"security"`
+
+	// Domain event configuration
+	Events EventsConfig `json:"events"`
+
+	// Background job configuration
+	Jobs JobsConfig `json:"jobs"`
+
+	// Local secret vault configuration
+	Vault VaultConfig `json:"vault"`
+
+	// Per-user device limits
+	DeviceQuota DeviceQuotaConfig `json:"device_quota"`
 }
 
 // DatabaseConfig holds database configuration
@@ -27,12 +48,137 @@ type DatabaseConfig struct {
 	User     string `json:"user"`
 	Password string `json:"password"`
 	SSLMode  string `json:"ssl_mode"`
+
+	// UserStorage selects how users are persisted: UserStorageState keeps
+	// the current state only, UserStorageEventSourced keeps every event
+	UserStorage string `json:"user_storage"`
+
+	// UserSnapshotEvery is the number of events between snapshots of an
+	// event-sourced user; zero disables snapshots
+	UserSnapshotEvery int `json:"user_snapshot_every"`
 }
 
-// ServerConfig holds server configuration
+// User storage options
+const (
+	UserStorageState        = "state"
+	UserStorageEventSourced = "event_sourced"
+)
+
+// ServerConfig holds HTTP API server configuration
 type ServerConfig struct {
-	Host string `json:"host"`
-	Port int    `json:"port"`
+	// Enabled starts the HTTP API next to the desktop application
+	Enabled bool   `json:"enabled"`
+	Host    string `json:"host"`
+	Port    int    `json:"port"`
+
+	ReadTimeout     time.Duration `json:"read_timeout"`
+	WriteTimeout    time.Duration `json:"write_timeout"`
+	IdleTimeout     time.Duration `json:"idle_timeout"`
+	ShutdownTimeout time.Duration `json:"shutdown_timeout"`
+
+	// APITokens maps bearer tokens to the IDs of the users they act as;
+	// requests without a token are anonymous
+	APITokens map[string]string `json:"-"`
+
+	// Requests without a valid token are limited per client address to a
+	// burst of AnonymousRateLimitBurst, refilled one every
+	// AnonymousRateLimitRefillRate
+	AnonymousRateLimitBurst      int           `json:"anonymous_rate_limit_burst"`
+	AnonymousRateLimitRefillRate time.Duration `json:"anonymous_rate_limit_refill_rate"`
+}
+
+// EventsConfig holds event bus and outbox relay configuration
+type EventsConfig struct {
+	Workers   int `json:"workers"`
+	QueueSize int `json:"queue_size"`
+
+	OutboxPollInterval   time.Duration `json:"outbox_poll_interval"`
+	OutboxBatchSize      int           `json:"outbox_batch_size"`
+	OutboxMaxAttempts    int           `json:"outbox_max_attempts"`
+	OutboxRetryBaseDelay time.Duration `json:"outbox_retry_base_delay"`
+	OutboxRetryMaxDelay  time.Duration `json:"outbox_retry_max_delay"`
+}
+
+// JobsConfig holds background job configuration
+type JobsConfig struct {
+	// UserRetention is how long soft-deleted users are kept before they are purged
+	UserRetention      time.Duration `json:"user_retention"`
+	UserPurgeInterval  time.Duration `json:"user_purge_interval"`
+	UserPurgeBatchSize int           `json:"user_purge_batch_size"`
+
+	// DeviceStaleAfter is how long a device may go without a heartbeat
+	// before the sweeper marks it stale
+	DeviceStaleAfter     time.Duration `json:"device_stale_after"`
+	DeviceSweepInterval  time.Duration `json:"device_sweep_interval"`
+	DeviceSweepBatchSize int           `json:"device_sweep_batch_size"`
+}
+
+// SecurityConfig holds authentication and credential configuration
+type SecurityConfig struct {
+	TOTPIssuer        string `json:"totp_issuer"`
+	TOTPDigits        int    `json:"totp_digits"`
+	TOTPPeriod        int    `json:"totp_period"`
+	TOTPSkew          int    `json:"totp_skew"`
+	RecoveryCodeCount int    `json:"recovery_code_count"`
+
+	// FingerprintSalt keys the hashes device fingerprints are made of. When it
+	// is not set, a salt generated for the installation is kept at
+	// FingerprintSaltPath.
+	FingerprintSalt     string `json:"fingerprint_salt"`
+	FingerprintSaltPath string `json:"fingerprint_salt_path"`
+
+	// A presented fingerprint matches a device from FingerprintMatchThreshold
+	// confidence and is flagged as suspicious from FingerprintSuspicionThreshold
+	FingerprintMatchThreshold     float64 `json:"fingerprint_match_threshold"`
+	FingerprintSuspicionThreshold float64 `json:"fingerprint_suspicion_threshold"`
+
+	// DeviceChallengeTTL is how long a device has to sign an issued nonce
+	DeviceChallengeTTL time.Duration `json:"device_challenge_ttl"`
+
+	WebAuthnRPID                    string   `json:"webauthn_rp_id"`
+	WebAuthnRPName                  string   `json:"webauthn_rp_name"`
+	WebAuthnOrigins                 []string `json:"webauthn_origins"`
+	WebAuthnTimeout                 int      `json:"webauthn_timeout"`
+	WebAuthnRequireUserVerification bool     `json:"webauthn_require_user_verification"`
+
+	LockoutMaxAttempts  int           `json:"lockout_max_attempts"`
+	LockoutDuration     time.Duration `json:"lockout_duration"`
+	BackoffBaseDelay    time.Duration `json:"backoff_base_delay"`
+	BackoffMaxDelay     time.Duration `json:"backoff_max_delay"`
+	RateLimitBurst      int           `json:"rate_limit_burst"`
+	RateLimitRefillRate time.Duration `json:"rate_limit_refill_rate"`
+
+	PolicyFile   string `json:"policy_file"`
+	PolicyDryRun bool   `json:"policy_dry_run"`
+}
+
+// VaultConfig holds the configuration of the encrypted local secret vault
+type VaultConfig struct {
+	Path string `json:"path"`
+
+	// Passphrase unlocks the vault at startup; without it the vault stays
+	// locked until it is unlocked from the frontend
+	Passphrase string `json:"-"`
+
+	// argon2id parameters used when the vault is created or rotated
+	KDFTime      uint32 `json:"kdf_time"`
+	KDFMemoryKiB uint32 `json:"kdf_memory_kib"`
+	KDFThreads   uint8  `json:"kdf_threads"`
+}
+
+// DeviceQuotaConfig holds the limits on how many devices a user may
+// activate. A limit of zero means unlimited.
+type DeviceQuotaConfig struct {
+	DefaultLimit int `json:"default_limit"`
+
+	// RoleLimits and UserLimits map role names and user IDs to limits
+	RoleLimits map[string]int `json:"role_limits"`
+	UserLimits map[string]int `json:"user_limits"`
+
+	// EvictOldestInactive revokes the device inactive the longest, for at
+	// least InactiveAfter, to make room for a new one
+	EvictOldestInactive bool          `json:"evict_oldest_inactive"`
+	InactiveAfter       time.Duration `json:"inactive_after"`
 }
 
 // Load loads configuration from environment variables with defaults
@@ -42,7 +188,9 @@ func Load() (*Config, error) {
 		Version:     getEnv("APP_VERSION", "1.0.0"),
 		Environment: getEnv("APP_ENV", "development"),
 		LogLevel:    getEnv("LOG_LEVEL", "info"),
-		
+
+		HandlerTimeout: getEnvDuration("HANDLER_TIMEOUT", 30*time.Second),
+
 		Database: DatabaseConfig{
 			Driver:   getEnv("DB_DRIVER", "memory"),
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -51,14 +199,99 @@ func Load() (*Config, error) {
 			User:     getEnv("DB_USER", "postgres"),
 			Password: getEnv("DB_PASSWORD", ""),
 			SSLMode:  getEnv("DB_SSL_MODE", "disable"),
+
+			UserStorage:       getEnv("USER_STORAGE", UserStorageState),
+			UserSnapshotEvery: getEnvInt("USER_SNAPSHOT_EVERY", 50),
 		},
-		
+
 		Server: ServerConfig{
-			Host: getEnv("SERVER_HOST", "localhost"),
-			Port: getEnvInt("SERVER_PORT", 8080),
+			Enabled: getEnvBool("SERVER_ENABLED", false),
+			Host:    getEnv("SERVER_HOST", "localhost"),
+			Port:    getEnvInt("SERVER_PORT", 8080),
+
+			ReadTimeout:     getEnvDuration("SERVER_READ_TIMEOUT", 30*time.Second),
+			WriteTimeout:    getEnvDuration("SERVER_WRITE_TIMEOUT", 30*time.Second),
+			IdleTimeout:     getEnvDuration("SERVER_IDLE_TIMEOUT", 120*time.Second),
+			ShutdownTimeout: getEnvDuration("SERVER_SHUTDOWN_TIMEOUT", 10*time.Second),
+
+			APITokens: getEnvMap("SERVER_API_TOKENS", map[string]string{}),
+
+			AnonymousRateLimitBurst:      getEnvInt("SERVER_ANONYMOUS_RATE_LIMIT_BURST", 10),
+			AnonymousRateLimitRefillRate: getEnvDuration("SERVER_ANONYMOUS_RATE_LIMIT_REFILL_RATE", 6*time.Second),
+		},
+
+		Security: SecurityConfig{
+			TOTPIssuer:        getEnv("TOTP_ISSUER", "Shadow ID"),
+			TOTPDigits:        getEnvInt("TOTP_DIGITS", 6),
+			TOTPPeriod:        getEnvInt("TOTP_PERIOD", 30),
+			TOTPSkew:          getEnvInt("TOTP_SKEW", 1),
+			RecoveryCodeCount: getEnvInt("RECOVERY_CODE_COUNT", 10),
+
+			FingerprintSalt:               getEnv("FINGERPRINT_SALT", ""),
+			FingerprintSaltPath:           getEnv("FINGERPRINT_SALT_PATH", "data/fingerprint.salt"),
+			FingerprintMatchThreshold:     getEnvFloat("FINGERPRINT_MATCH_THRESHOLD", 0.8),
+			FingerprintSuspicionThreshold: getEnvFloat("FINGERPRINT_SUSPICION_THRESHOLD", 0.5),
+			DeviceChallengeTTL:            getEnvDuration("DEVICE_CHALLENGE_TTL", 2*time.Minute),
+
+			WebAuthnRPID:   getEnv("WEBAUTHN_RP_ID", "wails.localhost"),
+			WebAuthnRPName: getEnv("WEBAUTHN_RP_NAME", "Shadow ID"),
+			WebAuthnOrigins: getEnvList("WEBAUTHN_ORIGINS", []string{
+				"wails://wails.localhost",
+				"http://wails.localhost",
+				"http://wails.localhost:34115",
+			}),
+			WebAuthnTimeout:                 getEnvInt("WEBAUTHN_TIMEOUT", 300),
+			WebAuthnRequireUserVerification: getEnvBool("WEBAUTHN_REQUIRE_USER_VERIFICATION", false),
+
+			LockoutMaxAttempts:  getEnvInt("LOCKOUT_MAX_ATTEMPTS", 5),
+			LockoutDuration:     getEnvDuration("LOCKOUT_DURATION", 15*time.Minute),
+			BackoffBaseDelay:    getEnvDuration("BACKOFF_BASE_DELAY", time.Second),
+			BackoffMaxDelay:     getEnvDuration("BACKOFF_MAX_DELAY", 5*time.Minute),
+			RateLimitBurst:      getEnvInt("RATE_LIMIT_BURST", 10),
+			RateLimitRefillRate: getEnvDuration("RATE_LIMIT_REFILL_RATE", 6*time.Second),
+
+			PolicyFile:   getEnv("POLICY_FILE", "configs/policies.json"),
+			PolicyDryRun: getEnvBool("POLICY_DRY_RUN", false),
+		},
+
+		Events: EventsConfig{
+			Workers:   getEnvInt("EVENT_BUS_WORKERS", 4),
+			QueueSize: getEnvInt("EVENT_BUS_QUEUE_SIZE", 256),
+
+			OutboxPollInterval:   getEnvDuration("OUTBOX_POLL_INTERVAL", time.Second),
+			OutboxBatchSize:      getEnvInt("OUTBOX_BATCH_SIZE", 100),
+			OutboxMaxAttempts:    getEnvInt("OUTBOX_MAX_ATTEMPTS", 10),
+			OutboxRetryBaseDelay: getEnvDuration("OUTBOX_RETRY_BASE_DELAY", time.Second),
+			OutboxRetryMaxDelay:  getEnvDuration("OUTBOX_RETRY_MAX_DELAY", 5*time.Minute),
+		},
+
+		Jobs: JobsConfig{
+			UserRetention:      getEnvDuration("USER_RETENTION", 30*24*time.Hour),
+			UserPurgeInterval:  getEnvDuration("USER_PURGE_INTERVAL", time.Hour),
+			UserPurgeBatchSize: getEnvInt("USER_PURGE_BATCH_SIZE", 100),
+
+			DeviceStaleAfter:     getEnvDuration("DEVICE_STALE_AFTER", 24*time.Hour),
+			DeviceSweepInterval:  getEnvDuration("DEVICE_SWEEP_INTERVAL", 5*time.Minute),
+			DeviceSweepBatchSize: getEnvInt("DEVICE_SWEEP_BATCH_SIZE", 100),
+		},
+
+		Vault: VaultConfig{
+			Path:         getEnv("VAULT_PATH", "data/vault.json"),
+			Passphrase:   getEnv("VAULT_PASSPHRASE", ""),
+			KDFTime:      uint32(getEnvInt("VAULT_KDF_TIME", 3)),
+			KDFMemoryKiB: uint32(getEnvInt("VAULT_KDF_MEMORY_KIB", 64*1024)),
+			KDFThreads:   uint8(getEnvInt("VAULT_KDF_THREADS", 4)),
+		},
+
+		DeviceQuota: DeviceQuotaConfig{
+			DefaultLimit:        getEnvInt("DEVICE_QUOTA_DEFAULT_LIMIT", 0),
+			RoleLimits:          getEnvIntMap("DEVICE_QUOTA_ROLE_LIMITS", map[string]int{}),
+			UserLimits:          getEnvIntMap("DEVICE_QUOTA_USER_LIMITS", map[string]int{}),
+			EvictOldestInactive: getEnvBool("DEVICE_QUOTA_EVICT_OLDEST_INACTIVE", false),
+			InactiveAfter:       getEnvDuration("DEVICE_QUOTA_INACTIVE_AFTER", 30*24*time.Hour),
 		},
 	}
-	
+
 	return config, nil
 }
 
@@ -73,15 +306,95 @@ func getEnv(key, defaultValue string) string {
 // getEnvInt gets an environment variable as integer with a default value
 func getEnvInt(key string, defaultValue int) int {
 	if value := os.Getenv(key); value != "" {
-		// Simple conversion, in production you might want proper error handling
-		if len(value) > 0 {
-			// For simplicity, return default if conversion fails
-			// In production, use strconv.Atoi with proper error handling
+		if intValue, err := strconv.Atoi(value); err == nil {
+			return intValue
+		}
+	}
+	return defaultValue
+}
+
+// getEnvFloat gets an environment variable as floating-point number with a default value
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
+// getEnvBool gets an environment variable as boolean with a default value
+func getEnvBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
 		}
 	}
 	return defaultValue
 }
 
+// getEnvDuration gets an environment variable as duration with a default value
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if duration, err := time.ParseDuration(value); err == nil {
+			return duration
+		}
+	}
+	return defaultValue
+}
+
+// getEnvList gets a comma-separated environment variable as a list with a default value
+func getEnvList(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	items := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// getEnvMap gets a comma-separated list of key=value pairs as a map with a
+// default value. Malformed pairs are skipped.
+func getEnvMap(key string, defaultValue map[string]string) map[string]string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	items := make(map[string]string)
+	for _, item := range getEnvList(key, nil) {
+		name, itemValue, found := strings.Cut(item, "=")
+		if !found {
+			continue
+		}
+		items[strings.TrimSpace(name)] = strings.TrimSpace(itemValue)
+	}
+	return items
+}
+
+// getEnvIntMap gets a comma-separated list of key=integer pairs as a map with
+// a default value. Malformed pairs are skipped.
+func getEnvIntMap(key string, defaultValue map[string]int) map[string]int {
+	pairs := getEnvMap(key, nil)
+	if pairs == nil {
+		return defaultValue
+	}
+
+	items := make(map[string]int)
+	for name, number := range pairs {
+		if intValue, err := strconv.Atoi(number); err == nil {
+			items[name] = intValue
+		}
+	}
+	return items
+}
+
 // IsDevelopment checks if the application is running in development mode
 func (c *Config) IsDevelopment() bool {
 	return c.Environment == "development"
@@ -0,0 +1,171 @@
+package eventbus
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"sync"
+
+	"shadow-id/internal/domain/events"
+	"shadow-id/pkg/errors"
+	"shadow-id/pkg/logger"
+)
+
+// AllEvents subscribes a handler to every event
+const AllEvents = "*"
+
+// Handler reacts to a published event
+type Handler func(ctx context.Context, event events.Event) error
+
+// Config holds event bus configuration
+type Config struct {
+	// Workers is the number of goroutines delivering to async subscribers
+	Workers int
+	// QueueSize is the number of async deliveries that may wait for a worker
+	QueueSize int
+}
+
+// subscription binds a handler to an event name
+type subscription struct {
+	subscriber string
+	eventName  string
+	handler    Handler
+	async      bool
+}
+
+// delivery is a queued async handler invocation
+type delivery struct {
+	ctx          context.Context
+	event        events.Event
+	subscription subscription
+}
+
+// Bus is an in-process event bus. Sync subscribers run before Publish returns;
+// async subscribers run on a worker pool. A failing or panicking subscriber is
+// logged and never prevents other subscribers from running. Sync failures are
+// reported to the publisher so the delivery can be retried.
+type Bus struct {
+	mu            sync.RWMutex
+	subscriptions []subscription
+	closed        bool
+
+	queue   chan delivery
+	workers sync.WaitGroup
+	logger  logger.Logger
+}
+
+// NewBus creates an event bus and starts its async workers
+func NewBus(cfg Config, log logger.Logger) *Bus {
+	if cfg.Workers < 1 {
+		cfg.Workers = 1
+	}
+	if cfg.QueueSize < 0 {
+		cfg.QueueSize = 0
+	}
+
+	b := &Bus{
+		queue:  make(chan delivery, cfg.QueueSize),
+		logger: log,
+	}
+	for i := 0; i < cfg.Workers; i++ {
+		b.workers.Add(1)
+		go b.work()
+	}
+	return b
+}
+
+// Subscribe registers a handler that runs synchronously during Publish
+func (b *Bus) Subscribe(subscriber, eventName string, handler Handler) {
+	b.subscribe(subscription{subscriber: subscriber, eventName: eventName, handler: handler})
+}
+
+// SubscribeAsync registers a handler that runs on the worker pool
+func (b *Bus) SubscribeAsync(subscriber, eventName string, handler Handler) {
+	b.subscribe(subscription{subscriber: subscriber, eventName: eventName, handler: handler, async: true})
+}
+
+// Publish delivers events to their subscribers in order
+func (b *Bus) Publish(ctx context.Context, evts ...events.Event) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.closed {
+		return errors.NewInternalError("event bus is closed")
+	}
+
+	var failures []error
+	for _, event := range evts {
+		for _, sub := range b.subscriptions {
+			if sub.eventName != AllEvents && sub.eventName != event.EventName() {
+				continue
+			}
+			if !sub.async {
+				if err := b.deliver(ctx, event, sub); err != nil {
+					failures = append(failures, err)
+				}
+				continue
+			}
+
+			// Async subscribers outlive the request, so detach from its cancellation
+			select {
+			case b.queue <- delivery{ctx: context.WithoutCancel(ctx), event: event, subscription: sub}:
+			case <-ctx.Done():
+				b.logger.Warn("Dropped async event delivery",
+					"subscriber", sub.subscriber, "event", event.EventName(), "error", ctx.Err())
+			}
+		}
+	}
+
+	if len(failures) > 0 {
+		return errors.WrapWithType(stderrors.Join(failures...), errors.ErrorTypeInternal, "event subscribers failed")
+	}
+	return nil
+}
+
+// Close stops accepting events and waits for queued async deliveries
+func (b *Bus) Close() {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return
+	}
+	b.closed = true
+	close(b.queue)
+	b.mu.Unlock()
+
+	b.workers.Wait()
+}
+
+// subscribe adds a subscription
+func (b *Bus) subscribe(sub subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.subscriptions = append(b.subscriptions, sub)
+}
+
+// work delivers queued async events until the queue is closed
+func (b *Bus) work() {
+	defer b.workers.Done()
+	for d := range b.queue {
+		_ = b.deliver(d.ctx, d.event, d.subscription)
+	}
+}
+
+// deliver invokes a subscriber, isolating and logging its errors and panics
+func (b *Bus) deliver(ctx context.Context, event events.Event, sub subscription) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			b.logger.Error("Event subscriber panicked",
+				"subscriber", sub.subscriber, "event", event.EventName(), "panic", fmt.Sprint(recovered))
+			err = fmt.Errorf("subscriber %s panicked: %v", sub.subscriber, recovered)
+		}
+	}()
+
+	if err := sub.handler(ctx, event); err != nil {
+		b.logger.Error("Event subscriber failed",
+			"subscriber", sub.subscriber, "event", event.EventName(), "aggregate_id", event.AggregateID(), "error", err)
+		return fmt.Errorf("subscriber %s: %w", sub.subscriber, err)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package fingerprint
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// saltSize is the size in bytes of a generated salt
+const saltSize = 32
+
+// LoadOrCreateSalt returns the installation's fingerprint salt stored at path.
+// The first call generates a random salt and stores it readable only by its
+// owner, so no two installations share a salt and fingerprints stay comparable
+// across restarts.
+func LoadOrCreateSalt(path string) (string, error) {
+	salt, err := readSalt(path)
+	if !errors.Is(err, fs.ErrNotExist) {
+		return salt, err
+	}
+
+	secret := make([]byte, saltSize)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	salt = hex.EncodeToString(secret)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return "", err
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if errors.Is(err, fs.ErrExist) {
+		// Another process created it first; use theirs
+		return readSalt(path)
+	}
+	if err != nil {
+		return "", err
+	}
+	if _, err := file.WriteString(salt + "\n"); err != nil {
+		file.Close()
+		return "", err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return "", err
+	}
+	return salt, file.Close()
+}
+
+// readSalt reads a stored salt, rejecting an empty file
+func readSalt(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	salt := strings.TrimSpace(string(data))
+	if salt == "" {
+		return "", fmt.Errorf("fingerprint salt file %s is empty", path)
+	}
+	return salt, nil
+}
@@ -0,0 +1,58 @@
+package fingerprint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadOrCreateSaltKeepsTheGeneratedSalt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "fingerprint.salt")
+
+	salt, err := LoadOrCreateSalt(path)
+	if err != nil {
+		t.Fatalf("LoadOrCreateSalt() error = %v", err)
+	}
+	if len(salt) != 2*saltSize {
+		t.Errorf("salt length = %d, want %d", len(salt), 2*saltSize)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+	if mode := info.Mode().Perm(); mode != 0o600 {
+		t.Errorf("salt file mode = %v, want 0600", mode)
+	}
+
+	again, err := LoadOrCreateSalt(path)
+	if err != nil {
+		t.Fatalf("LoadOrCreateSalt() again error = %v", err)
+	}
+	if again != salt {
+		t.Errorf("salt changed across loads: %q, then %q", salt, again)
+	}
+}
+
+func TestLoadOrCreateSaltDiffersPerInstallation(t *testing.T) {
+	first, err := LoadOrCreateSalt(filepath.Join(t.TempDir(), "fingerprint.salt"))
+	if err != nil {
+		t.Fatalf("LoadOrCreateSalt() error = %v", err)
+	}
+	second, err := LoadOrCreateSalt(filepath.Join(t.TempDir(), "fingerprint.salt"))
+	if err != nil {
+		t.Fatalf("LoadOrCreateSalt() error = %v", err)
+	}
+	if first == second {
+		t.Error("two installations generated the same salt")
+	}
+}
+
+func TestLoadOrCreateSaltRejectsEmptyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fingerprint.salt")
+	if err := os.WriteFile(path, []byte("\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if _, err := LoadOrCreateSalt(path); err == nil {
+		t.Error("LoadOrCreateSalt() accepted an empty salt file")
+	}
+}
@@ -0,0 +1,110 @@
+// Package fingerprint reads the stable attributes of the local machine that
+// make up its device fingerprint. Each kind of attribute comes from its own
+// Source, so platforms and tests can supply their own.
+package fingerprint
+
+import (
+	"context"
+	"net"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+
+	"shadow-id/internal/domain/entities"
+)
+
+// Source reads one kind of machine attribute
+type Source interface {
+	// Name identifies the fingerprint component the source produces
+	Name() string
+
+	// Collect returns the attribute's values. An attribute that does not
+	// exist or cannot be read on this machine yields no values rather than
+	// an error.
+	Collect(ctx context.Context) ([]string, error)
+}
+
+// funcSource adapts a function to a Source
+type funcSource struct {
+	name    string
+	collect func(ctx context.Context) ([]string, error)
+}
+
+// NewSource creates a source that collects its values with a function
+func NewSource(name string, collect func(ctx context.Context) ([]string, error)) Source {
+	return &funcSource{
+		name:    name,
+		collect: collect,
+	}
+}
+
+// Name identifies the fingerprint component the source produces
+func (s *funcSource) Name() string {
+	return s.name
+}
+
+// Collect returns the attribute's values
+func (s *funcSource) Collect(ctx context.Context) ([]string, error) {
+	return s.collect(ctx)
+}
+
+// HostnameSource reads the machine's host name
+func HostnameSource() Source {
+	return NewSource(entities.FingerprintHostname, func(ctx context.Context) ([]string, error) {
+		hostname, err := os.Hostname()
+		if err != nil || hostname == "" {
+			return nil, nil
+		}
+		return []string{hostname}, nil
+	})
+}
+
+// MACSource reads the hardware addresses of the network interfaces.
+// Loopback interfaces and locally administered addresses, which virtual
+// interfaces and MAC randomization generate afresh, are left out.
+func MACSource() Source {
+	return NewSource(entities.FingerprintMAC, func(ctx context.Context) ([]string, error) {
+		interfaces, err := net.Interfaces()
+		if err != nil {
+			return nil, err
+		}
+
+		addresses := make([]string, 0, len(interfaces))
+		for _, iface := range interfaces {
+			mac := iface.HardwareAddr
+			if iface.Flags&net.FlagLoopback != 0 || len(mac) == 0 || mac[0]&0x02 != 0 || isZero(mac) {
+				continue
+			}
+			addresses = append(addresses, mac.String())
+		}
+		sort.Strings(addresses)
+		return addresses, nil
+	})
+}
+
+// isZero checks if every byte of a hardware address is zero
+func isZero(mac net.HardwareAddr) bool {
+	for _, b := range mac {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// runtimePlatform describes the operating system and architecture the
+// application was built for
+func runtimePlatform() string {
+	return runtime.GOOS + "/" + runtime.GOARCH
+}
+
+// readTrimmed reads a small file and trims surrounding whitespace, returning
+// an empty string if the file cannot be read
+func readTrimmed(path string) string {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(content))
+}
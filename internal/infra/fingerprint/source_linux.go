@@ -0,0 +1,146 @@
+//go:build linux
+
+package fingerprint
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"shadow-id/internal/domain/entities"
+)
+
+// DefaultSources returns the sources read on Linux
+func DefaultSources() []Source {
+	return []Source{
+		MachineIDSource(),
+		HostnameSource(),
+		MACSource(),
+		CPUSource(),
+		OSSource(),
+		DiskSerialSource(),
+	}
+}
+
+// MachineIDSource reads the machine ID systemd or D-Bus generated when the
+// system was installed
+func MachineIDSource() Source {
+	return NewSource(entities.FingerprintMachineID, func(ctx context.Context) ([]string, error) {
+		for _, path := range []string{"/etc/machine-id", "/var/lib/dbus/machine-id"} {
+			if id := readTrimmed(path); id != "" {
+				return []string{id}, nil
+			}
+		}
+		return nil, nil
+	})
+}
+
+// CPUSource reads the processor vendor and model from /proc/cpuinfo. ARM
+// kernels report the implementer and part instead.
+func CPUSource() Source {
+	return NewSource(entities.FingerprintCPU, func(ctx context.Context) ([]string, error) {
+		file, err := os.Open("/proc/cpuinfo")
+		if err != nil {
+			return nil, nil
+		}
+		defer file.Close()
+
+		wanted := map[string]bool{
+			"vendor_id":       true,
+			"model name":      true,
+			"CPU implementer": true,
+			"CPU part":        true,
+			"Hardware":        true,
+		}
+		found := make(map[string]string)
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			key, value, ok := strings.Cut(scanner.Text(), ":")
+			if !ok {
+				continue
+			}
+			key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+			if wanted[key] && found[key] == "" && value != "" {
+				found[key] = key + "=" + value
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+
+		values := make([]string, 0, len(found))
+		for _, value := range found {
+			values = append(values, value)
+		}
+		sort.Strings(values)
+		return values, nil
+	})
+}
+
+// OSSource reads the platform and the distribution and release from
+// /etc/os-release. The kernel version is left out since it changes with
+// every update.
+func OSSource() Source {
+	return NewSource(entities.FingerprintOS, func(ctx context.Context) ([]string, error) {
+		values := []string{runtimePlatform()}
+
+		file, err := os.Open("/etc/os-release")
+		if err != nil {
+			return values, nil
+		}
+		defer file.Close()
+
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			key, value, ok := strings.Cut(scanner.Text(), "=")
+			if ok && (key == "ID" || key == "VERSION_ID") {
+				values = append(values, key+"="+strings.Trim(value, `"'`))
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+		return values, nil
+	})
+}
+
+// DiskSerialSource reads the serial numbers of the block devices the kernel
+// exposes in sysfs. Disks whose serial is only readable by root, and
+// virtual devices such as loop and RAM disks, are skipped.
+func DiskSerialSource() Source {
+	return NewSource(entities.FingerprintDisks, func(ctx context.Context) ([]string, error) {
+		devices, err := os.ReadDir("/sys/block")
+		if err != nil {
+			return nil, nil
+		}
+
+		serials := make([]string, 0, len(devices))
+		for _, device := range devices {
+			if isVirtualDisk(device.Name()) {
+				continue
+			}
+			for _, attribute := range []string{"serial", "wwid"} {
+				if serial := readTrimmed(filepath.Join("/sys/block", device.Name(), "device", attribute)); serial != "" {
+					serials = append(serials, serial)
+					break
+				}
+			}
+		}
+		sort.Strings(serials)
+		return serials, nil
+	})
+}
+
+// isVirtualDisk checks if a block device name belongs to a device that is
+// not backed by hardware
+func isVirtualDisk(name string) bool {
+	for _, prefix := range []string{"loop", "ram", "zram", "dm-", "md", "nbd"} {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
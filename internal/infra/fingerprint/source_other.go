@@ -0,0 +1,26 @@
+//go:build !linux
+
+package fingerprint
+
+import (
+	"context"
+
+	"shadow-id/internal/domain/entities"
+)
+
+// DefaultSources returns the portable sources read on platforms without
+// dedicated collectors
+func DefaultSources() []Source {
+	return []Source{
+		HostnameSource(),
+		MACSource(),
+		OSSource(),
+	}
+}
+
+// OSSource reads the platform the application was built for
+func OSSource() Source {
+	return NewSource(entities.FingerprintOS, func(ctx context.Context) ([]string, error) {
+		return []string{runtimePlatform()}, nil
+	})
+}
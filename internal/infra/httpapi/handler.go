@@ -0,0 +1,368 @@
+package httpapi
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"shadow-id/internal/app/auth"
+	"shadow-id/internal/app/commands"
+	"shadow-id/internal/app/pipeline"
+	"shadow-id/internal/domain/services"
+	"shadow-id/pkg/errors"
+	"shadow-id/pkg/logger"
+	"shadow-id/pkg/types"
+)
+
+// CorrelationHeader carries the correlation ID of a request. A request
+// without one gets a new ID; either way it is sent back with the response.
+const CorrelationHeader = "X-Correlation-ID"
+
+// maxBodySize bounds the size of a request body
+const maxBodySize = 1 << 20
+
+// ErrorResponse is the response body of a failed request
+type ErrorResponse struct {
+	Error ErrorDetails `json:"error"`
+}
+
+// ErrorDetails describes why a request failed
+type ErrorDetails struct {
+	Type    errors.ErrorType       `json:"type"`
+	Message string                 `json:"message"`
+	Details map[string]interface{} `json:"details,omitempty"`
+}
+
+// handler serves the API routes
+type handler struct {
+	bus    *pipeline.Bus
+	tokens map[string]types.ID
+	logger logger.Logger
+
+	// anonymousLimiter throttles requests without a valid token by the
+	// address they come from
+	anonymousLimiter services.RateLimiter
+
+	// openAPI is the encoded OpenAPI document, built once
+	openAPI []byte
+}
+
+// NewHandler returns the HTTP handler serving every route of the API.
+// Requests without a valid token are limited per client address by the
+// anonymous limiter.
+func NewHandler(bus *pipeline.Bus, tokens map[string]types.ID, anonymousLimiter services.RateLimiter, log logger.Logger) http.Handler {
+	h := &handler{
+		bus:              bus,
+		tokens:           tokens,
+		logger:           log,
+		anonymousLimiter: anonymousLimiter,
+	}
+	openAPI, err := json.Marshal(NewDocument(bus))
+	if err != nil {
+		log.Error("Failed to encode OpenAPI document", "error", err)
+	}
+	h.openAPI = openAPI
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+BasePath+"/health", h.health)
+	mux.HandleFunc("GET "+OpenAPIPath, h.openAPIDocument)
+	for _, route := range Routes() {
+		mux.Handle(route.Method+" "+route.Path, h.dispatch(route))
+	}
+	return mux
+}
+
+// health reports that the server accepts requests
+func (h *handler) health(w http.ResponseWriter, r *http.Request) {
+	h.writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// openAPIDocument serves the OpenAPI document describing the API
+func (h *handler) openAPIDocument(w http.ResponseWriter, r *http.Request) {
+	if h.openAPI == nil {
+		h.writeError(w, errors.NewInternalError("OpenAPI document is unavailable"))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(h.openAPI)
+}
+
+// dispatch decodes the request into the route's message and sends it through
+// the bus on behalf of the caller
+func (h *handler) dispatch(route Route) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		correlationID := r.Header.Get(CorrelationHeader)
+		if correlationID == "" {
+			correlationID = types.NewID().String()
+		}
+		w.Header().Set(CorrelationHeader, correlationID)
+
+		// Resolve the caller. Callers without a valid token are limited by
+		// address, so the credential checks open to anonymous callers and the
+		// tokens themselves cannot be guessed at full speed.
+		ctx, err := h.authenticate(r)
+		if err != nil || auth.PrincipalFromContext(ctx).IsAnonymous() {
+			if limitErr := h.limitAnonymous(w, r); limitErr != nil {
+				h.writeError(w, limitErr)
+				return
+			}
+		}
+		if err != nil {
+			h.writeError(w, err)
+			return
+		}
+		ctx = pipeline.WithCorrelationID(ctx, correlationID)
+
+		// Decode the message
+		msg, err := decode(w, r, route)
+		if err != nil {
+			h.writeError(w, err)
+			return
+		}
+		msg = bindConnection(msg, r)
+
+		result, err := h.bus.Dispatch(ctx, msg)
+		if err != nil {
+			h.writeError(w, err)
+			return
+		}
+
+		h.writeJSON(w, route.Status, result)
+	})
+}
+
+// authenticate returns the request context carrying the principal its bearer
+// token acts as. Requests without a token are anonymous; the bootstrap
+// principal is never granted over HTTP.
+func (h *handler) authenticate(r *http.Request) (context.Context, error) {
+	header := r.Header.Get("Authorization")
+	if header == "" {
+		return r.Context(), nil
+	}
+
+	token, found := strings.CutPrefix(header, "Bearer ")
+	if !found || token == "" {
+		return nil, errors.NewUnauthorizedError("malformed authorization header")
+	}
+
+	// Compare every token so the time taken does not reveal a match
+	var userID types.ID
+	for candidate, id := range h.tokens {
+		if subtle.ConstantTimeCompare([]byte(candidate), []byte(token)) == 1 {
+			userID = id
+		}
+	}
+	if userID.IsEmpty() {
+		return nil, errors.NewUnauthorizedError("invalid API token")
+	}
+
+	return auth.WithPrincipal(r.Context(), auth.Principal{UserID: userID}), nil
+}
+
+// limitAnonymous consumes an attempt for the client address of a request
+// without a valid token, failing once the address ran out
+func (h *handler) limitAnonymous(w http.ResponseWriter, r *http.Request) error {
+	allowed, retryAfter := h.anonymousLimiter.Allow(r.Context(), "http:"+clientAddress(r))
+	if allowed {
+		return nil
+	}
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	return errors.NewRateLimitedError("too many requests without a valid API token").
+		WithDetail("retry_after_seconds", seconds)
+}
+
+// clientAddress returns the address of the connection's peer. Forwarding
+// headers are not trusted.
+func clientAddress(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// bindConnection overwrites the fields of a message that describe the
+// caller's connection with what the server observed, so callers cannot
+// report an address other than their own
+func bindConnection(msg interface{}, r *http.Request) interface{} {
+	switch m := msg.(type) {
+	case commands.RecordDeviceHeartbeatCommand:
+		m.IPAddress = clientAddress(r)
+		return m
+	}
+	return msg
+}
+
+// decode reads the route's message from the request. The fields named by
+// path wildcards are taken from the path, overriding any given otherwise.
+func decode(w http.ResponseWriter, r *http.Request, route Route) (interface{}, error) {
+	value := reflect.New(route.Message)
+
+	var err error
+	if route.TakesQuery() {
+		err = decodeQuery(r.URL.Query(), value.Elem(), route.Wildcards())
+	} else {
+		err = decodeBody(w, r, value)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range route.Wildcards() {
+		if field, ok := fieldByJSONName(value.Elem(), name); ok {
+			field.SetString(r.PathValue(name))
+		}
+	}
+	return value.Elem().Interface(), nil
+}
+
+// decodeBody reads a message from the request body into the value it points
+// to. An empty body decodes to the zero message.
+func decodeBody(w http.ResponseWriter, r *http.Request, value reflect.Value) error {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(value.Interface()); err != nil && err != io.EOF {
+		var tooLarge *http.MaxBytesError
+		if stderrors.As(err, &tooLarge) {
+			return errors.NewValidationError("request body is too large").
+				WithDetail("max_bytes", tooLarge.Limit)
+		}
+		return errors.WrapWithType(err, errors.ErrorTypeValidation, "invalid request body")
+	}
+	if decoder.More() {
+		return errors.NewValidationError("request body must hold a single JSON object")
+	}
+	return nil
+}
+
+// decodeQuery sets the message fields the query parameters name by their
+// JSON names. Parameters naming no field or a path wildcard are rejected.
+func decodeQuery(query url.Values, msg reflect.Value, wildcards []string) error {
+	for name, values := range query {
+		field, ok := fieldByJSONName(msg, name)
+		if !ok || slices.Contains(wildcards, name) {
+			return errors.NewValidationError("unknown query parameter").WithDetail("parameter", name)
+		}
+		if err := setQueryValue(field, values); err != nil {
+			return errors.WrapWithType(err, errors.ErrorTypeValidation, "invalid query parameter").
+				WithDetail("parameter", name)
+		}
+	}
+	return nil
+}
+
+// setQueryValue parses the values of a query parameter into a field. Lists of
+// strings take every value; other fields take exactly one.
+func setQueryValue(field reflect.Value, values []string) error {
+	if field.Kind() == reflect.Slice && field.Type().Elem().Kind() == reflect.String {
+		list := reflect.MakeSlice(field.Type(), len(values), len(values))
+		for i, value := range values {
+			list.Index(i).SetString(value)
+		}
+		field.Set(list)
+		return nil
+	}
+	if len(values) != 1 {
+		return fmt.Errorf("expected a single value, got %d", len(values))
+	}
+
+	value := values[0]
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(parsed)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		parsed, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(parsed)
+	default:
+		return fmt.Errorf("%s values cannot be given in the query string", field.Kind())
+	}
+	return nil
+}
+
+// fieldByJSONName returns the field of a struct encoded under a JSON name
+func fieldByJSONName(msg reflect.Value, name string) (reflect.Value, bool) {
+	for i := 0; i < msg.NumField(); i++ {
+		tagged, _, _ := strings.Cut(msg.Type().Field(i).Tag.Get("json"), ",")
+		if tagged == name && msg.Type().Field(i).IsExported() {
+			return msg.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// writeError responds with the status matching the error's type. Internal
+// errors are logged and not described to the caller.
+func (h *handler) writeError(w http.ResponseWriter, err error) {
+	body := ErrorDetails{
+		Type:    errors.ErrorTypeInternal,
+		Message: "internal error",
+	}
+
+	var appErr *errors.AppError
+	if stderrors.As(err, &appErr) && appErr.Type != errors.ErrorTypeInternal {
+		body = ErrorDetails{
+			Type:    appErr.Type,
+			Message: appErr.Error(),
+			Details: appErr.Details,
+		}
+	} else {
+		h.logger.Error("HTTP API request failed", "error", err)
+	}
+
+	h.writeJSON(w, statusFor(body.Type), ErrorResponse{Error: body})
+}
+
+// writeJSON responds with the value encoded as JSON
+func (h *handler) writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		h.logger.Warn("Failed to write HTTP API response", "error", err)
+	}
+}
+
+// statusFor returns the HTTP status for an error type
+func statusFor(errorType errors.ErrorType) int {
+	switch errorType {
+	case errors.ErrorTypeValidation:
+		return http.StatusBadRequest
+	case errors.ErrorTypeUnauthorized:
+		return http.StatusUnauthorized
+	case errors.ErrorTypeForbidden:
+		return http.StatusForbidden
+	case errors.ErrorTypeNotFound:
+		return http.StatusNotFound
+	case errors.ErrorTypeConflict:
+		return http.StatusConflict
+	case errors.ErrorTypeRateLimited:
+		return http.StatusTooManyRequests
+	case errors.ErrorTypeExternal:
+		return http.StatusBadGateway
+	case errors.ErrorTypeTimeout:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,350 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"shadow-id/internal/app/auth"
+	"shadow-id/internal/app/commands"
+	"shadow-id/internal/app/pipeline"
+	"shadow-id/internal/app/queries"
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/infra/ratelimit"
+	"shadow-id/pkg/errors"
+	"shadow-id/pkg/logger"
+	"shadow-id/pkg/types"
+)
+
+const (
+	testToken  = "s3cret-token"
+	testUserID = types.ID("user-1")
+)
+
+// verifyStub answers two-factor checks with a fixed error, recording the
+// principal it ran as
+type verifyStub struct {
+	err       error
+	principal auth.Principal
+	calls     int
+}
+
+func (s *verifyStub) Handle(ctx context.Context, cmd commands.VerifyTwoFactorCommand) (*commands.VerifyTwoFactorResult, error) {
+	s.calls++
+	s.principal = auth.PrincipalFromContext(ctx)
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &commands.VerifyTwoFactorResult{UserID: cmd.UserID, Verified: true}, nil
+}
+
+// newTestHandler serves a bus whose only handler is the stub, allowing two
+// requests without a token per address
+func newTestHandler(stub *verifyStub) http.Handler {
+	bus := pipeline.NewBus(pipeline.NewDispatcher())
+	pipeline.RegisterCommand[commands.VerifyTwoFactorCommand, *commands.VerifyTwoFactorResult](bus, stub)
+
+	limiter := ratelimit.NewTokenBucket(ratelimit.TokenBucketConfig{Capacity: 2, RefillInterval: time.Hour})
+	return NewHandler(bus, map[string]types.ID{testToken: testUserID}, limiter, logger.New("error"))
+}
+
+// verifyRequest builds a two-factor check from an address
+func verifyRequest(remoteAddr, authorization, body string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, BasePath+"/commands/verify-two-factor", strings.NewReader(body))
+	r.RemoteAddr = remoteAddr
+	if authorization != "" {
+		r.Header.Set("Authorization", authorization)
+	}
+	return r
+}
+
+// serve sends a request and decodes the error in the response, if any
+func serve(t *testing.T, h http.Handler, r *http.Request) (*httptest.ResponseRecorder, ErrorDetails) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	var body ErrorResponse
+	if w.Code != http.StatusOK {
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("Unmarshal() error = %v, body %q", err, w.Body.String())
+		}
+	}
+	return w, body.Error
+}
+
+func TestBearerTokenSelectsPrincipal(t *testing.T) {
+	tests := []struct {
+		name          string
+		authorization string
+		wantStatus    int
+		wantUser      types.ID
+	}{
+		{"no token is anonymous", "", http.StatusOK, ""},
+		{"known token acts as its user", "Bearer " + testToken, http.StatusOK, testUserID},
+		{"unknown token", "Bearer guess", http.StatusUnauthorized, ""},
+		{"other scheme", "Basic " + testToken, http.StatusUnauthorized, ""},
+		{"empty token", "Bearer ", http.StatusUnauthorized, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &verifyStub{}
+			h := newTestHandler(stub)
+
+			w, _ := serve(t, h, verifyRequest("192.0.2.1:4000", tt.authorization, `{"user_id":"user-2","code":"123456"}`))
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if w.Header().Get(CorrelationHeader) == "" {
+				t.Error("response has no correlation ID")
+			}
+			if tt.wantStatus == http.StatusOK && stub.principal.UserID != tt.wantUser {
+				t.Errorf("principal = %q, want %q", stub.principal.UserID, tt.wantUser)
+			}
+			if tt.wantStatus != http.StatusOK && stub.calls != 0 {
+				t.Error("request with a rejected token was dispatched")
+			}
+		})
+	}
+}
+
+func TestRequestsWithoutValidTokenAreLimitedPerAddress(t *testing.T) {
+	stub := &verifyStub{}
+	h := newTestHandler(stub)
+	body := `{"user_id":"user-2","code":"123456"}`
+
+	// Anonymous requests and failed token guesses draw from the same budget
+	if w, _ := serve(t, h, verifyRequest("192.0.2.1:4000", "", body)); w.Code != http.StatusOK {
+		t.Fatalf("first request status = %d", w.Code)
+	}
+	if w, _ := serve(t, h, verifyRequest("192.0.2.1:4001", "Bearer guess", body)); w.Code != http.StatusUnauthorized {
+		t.Fatalf("token guess status = %d", w.Code)
+	}
+	w, details := serve(t, h, verifyRequest("192.0.2.1:4002", "", body))
+	if w.Code != http.StatusTooManyRequests || details.Type != errors.ErrorTypeRateLimited {
+		t.Fatalf("status = %d, type = %s, want rate limited", w.Code, details.Type)
+	}
+	if w.Header().Get("Retry-After") == "" {
+		t.Error("rate limited response has no Retry-After")
+	}
+	if stub.calls != 1 {
+		t.Errorf("handler ran %d times, want once", stub.calls)
+	}
+
+	// Other addresses and callers with a token are not affected
+	if w, _ := serve(t, h, verifyRequest("198.51.100.7:4000", "", body)); w.Code != http.StatusOK {
+		t.Errorf("other address status = %d", w.Code)
+	}
+	if w, _ := serve(t, h, verifyRequest("192.0.2.1:4003", "Bearer "+testToken, body)); w.Code != http.StatusOK {
+		t.Errorf("authenticated status = %d", w.Code)
+	}
+}
+
+func TestRequestBodyIsDecodedStrictly(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{"message", `{"user_id":"user-2","code":"123456"}`, http.StatusOK},
+		{"empty body is the zero message", ``, http.StatusOK},
+		{"unknown field", `{"user_id":"user-2","admin":true}`, http.StatusBadRequest},
+		{"malformed JSON", `{"user_id":`, http.StatusBadRequest},
+		{"several values", `{} {}`, http.StatusBadRequest},
+		{"too large", `{"code":"` + strings.Repeat("1", maxBodySize) + `"}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &verifyStub{}
+			h := newTestHandler(stub)
+
+			w, details := serve(t, h, verifyRequest("192.0.2.1:4000", "Bearer "+testToken, tt.body))
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (%s)", w.Code, tt.wantStatus, details.Message)
+			}
+			if tt.wantStatus != http.StatusOK && details.Type != errors.ErrorTypeValidation {
+				t.Errorf("type = %s, want %s", details.Type, errors.ErrorTypeValidation)
+			}
+		})
+	}
+}
+
+func TestErrorsMapToStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantStatus  int
+		wantType    errors.ErrorType
+		wantMessage string
+	}{
+		{"validation", errors.NewValidationError("bad code"), http.StatusBadRequest, errors.ErrorTypeValidation, "bad code"},
+		{"unauthorized", errors.NewUnauthorizedError("no"), http.StatusUnauthorized, errors.ErrorTypeUnauthorized, "no"},
+		{"forbidden", errors.WrapWithType(entities.ErrPolicyDenied, errors.ErrorTypeForbidden, "denied"), http.StatusForbidden, errors.ErrorTypeForbidden, ""},
+		{"not found", errors.NewNotFoundError("missing"), http.StatusNotFound, errors.ErrorTypeNotFound, "missing"},
+		{"conflict", errors.NewConflictError("stale"), http.StatusConflict, errors.ErrorTypeConflict, "stale"},
+		{"rate limited", errors.NewRateLimitedError("slow down"), http.StatusTooManyRequests, errors.ErrorTypeRateLimited, "slow down"},
+		{"external", errors.New(errors.ErrorTypeExternal, "upstream"), http.StatusBadGateway, errors.ErrorTypeExternal, "upstream"},
+		{"timeout", errors.NewTimeoutError("too slow"), http.StatusGatewayTimeout, errors.ErrorTypeTimeout, "too slow"},
+		{"wrapped", fmt.Errorf("handler: %w", errors.NewNotFoundError("missing")), http.StatusNotFound, errors.ErrorTypeNotFound, "missing"},
+		{"internal is not described", errors.NewInternalError("database password rejected"), http.StatusInternalServerError, errors.ErrorTypeInternal, "internal error"},
+		{"plain error is internal", fmt.Errorf("disk full"), http.StatusInternalServerError, errors.ErrorTypeInternal, "internal error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler(&verifyStub{err: tt.err})
+
+			w, details := serve(t, h, verifyRequest("192.0.2.1:4000", "Bearer "+testToken, `{}`))
+			if w.Code != tt.wantStatus || details.Type != tt.wantType {
+				t.Fatalf("status = %d, type = %s, want %d, %s", w.Code, details.Type, tt.wantStatus, tt.wantType)
+			}
+			if tt.wantMessage != "" && details.Message != tt.wantMessage {
+				t.Errorf("message = %q, want %q", details.Message, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestHealthAndDocumentNeedNoToken(t *testing.T) {
+	h := newTestHandler(&verifyStub{})
+
+	for _, path := range []string{BasePath + "/health", OpenAPIPath} {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != http.StatusOK {
+			t.Errorf("GET %s status = %d, want %d", path, w.Code, http.StatusOK)
+		}
+	}
+}
+
+// heartbeatStub records the heartbeats it receives
+type heartbeatStub struct {
+	cmd commands.RecordDeviceHeartbeatCommand
+}
+
+func (s *heartbeatStub) Handle(ctx context.Context, cmd commands.RecordDeviceHeartbeatCommand) (*commands.RecordDeviceHeartbeatResult, error) {
+	s.cmd = cmd
+	return &commands.RecordDeviceHeartbeatResult{DeviceID: cmd.DeviceID}, nil
+}
+
+func TestHeartbeatAddressIsTakenFromTheConnection(t *testing.T) {
+	tests := []struct {
+		name       string
+		remoteAddr string
+		body       string
+		want       string
+	}{
+		{"body address is ignored", "192.0.2.1:4000", `{"device_id":"device-1","ip_address":"203.0.113.9"}`, "192.0.2.1"},
+		{"no body address", "192.0.2.1:4000", `{"device_id":"device-1"}`, "192.0.2.1"},
+		{"IPv6 peer", "[2001:db8::1]:4000", `{"device_id":"device-1","ip_address":"203.0.113.9"}`, "2001:db8::1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &heartbeatStub{}
+			bus := pipeline.NewBus(pipeline.NewDispatcher())
+			pipeline.RegisterCommand[commands.RecordDeviceHeartbeatCommand, *commands.RecordDeviceHeartbeatResult](bus, stub)
+			limiter := ratelimit.NewTokenBucket(ratelimit.TokenBucketConfig{Capacity: 2, RefillInterval: time.Hour})
+			h := NewHandler(bus, map[string]types.ID{testToken: testUserID}, limiter, logger.New("error"))
+
+			r := httptest.NewRequest(http.MethodPost, BasePath+"/commands/record-device-heartbeat", strings.NewReader(tt.body))
+			r.RemoteAddr = tt.remoteAddr
+			r.Header.Set("Authorization", "Bearer "+testToken)
+			if w, details := serve(t, h, r); w.Code != http.StatusOK {
+				t.Fatalf("status = %d (%s)", w.Code, details.Message)
+			}
+			if stub.cmd.IPAddress != tt.want {
+				t.Errorf("IPAddress = %q, want %q", stub.cmd.IPAddress, tt.want)
+			}
+		})
+	}
+}
+
+// recordStub records the message it handles and answers with the zero result
+type recordStub[Req, Res any] struct {
+	got   Req
+	calls int
+}
+
+func (s *recordStub[Req, Res]) Handle(ctx context.Context, req Req) (Res, error) {
+	s.got = req
+	s.calls++
+	var zero Res
+	return zero, nil
+}
+
+func TestResourceRoutesReadPathQueryAndBody(t *testing.T) {
+	getUser := &recordStub[queries.GetUserQuery, *queries.GetUserResult]{}
+	timeline := &recordStub[queries.GetUserTimelineQuery, *queries.GetUserTimelineResult]{}
+	updateUser := &recordStub[commands.UpdateUserCommand, *commands.UpdateUserResult]{}
+	deleteUser := &recordStub[commands.DeleteUserCommand, *commands.DeleteUserResult]{}
+	createUser := &recordStub[commands.CreateUserCommand, *commands.CreateUserResult]{}
+
+	bus := pipeline.NewBus(pipeline.NewDispatcher())
+	pipeline.RegisterQuery[queries.GetUserQuery, *queries.GetUserResult](bus, getUser)
+	pipeline.RegisterQuery[queries.GetUserTimelineQuery, *queries.GetUserTimelineResult](bus, timeline)
+	pipeline.RegisterCommand[commands.UpdateUserCommand, *commands.UpdateUserResult](bus, updateUser)
+	pipeline.RegisterCommand[commands.DeleteUserCommand, *commands.DeleteUserResult](bus, deleteUser)
+	pipeline.RegisterCommand[commands.CreateUserCommand, *commands.CreateUserResult](bus, createUser)
+	limiter := ratelimit.NewTokenBucket(ratelimit.TokenBucketConfig{Capacity: 2, RefillInterval: time.Hour})
+	h := NewHandler(bus, map[string]types.ID{testToken: testUserID}, limiter, logger.New("error"))
+
+	request := func(method, target, body string) *http.Request {
+		r := httptest.NewRequest(method, BasePath+target, strings.NewReader(body))
+		r.Header.Set("Authorization", "Bearer "+testToken)
+		return r
+	}
+
+	tests := []struct {
+		name       string
+		request    *http.Request
+		wantStatus int
+		check      func(t *testing.T)
+	}{
+		{"GET reads the query string", request(http.MethodGet, "/users/user-2?include_deleted=true", ""), http.StatusOK, func(t *testing.T) {
+			if getUser.got != (queries.GetUserQuery{ID: "user-2", IncludeDeleted: true}) {
+				t.Errorf("query = %+v", getUser.got)
+			}
+		}},
+		{"repeated parameters fill lists", request(http.MethodGet, "/users/user-2/timeline?actions=login&actions=logout&limit=5", ""), http.StatusOK, func(t *testing.T) {
+			got := timeline.got
+			if got.UserID != "user-2" || got.Limit != 5 || len(got.Actions) != 2 || got.Actions[1] != "logout" {
+				t.Errorf("query = %+v", got)
+			}
+		}},
+		{"PATCH reads the body and the path wins", request(http.MethodPatch, "/users/user-2", `{"id":"user-3","name":"Alice"}`), http.StatusOK, func(t *testing.T) {
+			if updateUser.got != (commands.UpdateUserCommand{ID: "user-2", Name: "Alice"}) {
+				t.Errorf("command = %+v", updateUser.got)
+			}
+		}},
+		{"DELETE reads the query string", request(http.MethodDelete, "/users/user-2?version=3", ""), http.StatusOK, func(t *testing.T) {
+			if deleteUser.got != (commands.DeleteUserCommand{ID: "user-2", Version: 3}) {
+				t.Errorf("command = %+v", deleteUser.got)
+			}
+		}},
+		{"POST to a collection creates", request(http.MethodPost, "/users", `{"name":"Bob","email":"bob@example.com"}`), http.StatusCreated, nil},
+		{"unknown parameter", request(http.MethodGet, "/users/user-2?admin=true", ""), http.StatusBadRequest, nil},
+		{"wildcard given again", request(http.MethodGet, "/users/user-2?id=user-3", ""), http.StatusBadRequest, nil},
+		{"malformed value", request(http.MethodDelete, "/users/user-2?version=three", ""), http.StatusBadRequest, nil},
+		{"method not served", request(http.MethodPut, "/users/user-2", `{}`), http.StatusMethodNotAllowed, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, tt.request)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (%s)", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.check != nil {
+				tt.check(t)
+			}
+		})
+	}
+
+	if calls := getUser.calls + timeline.calls + updateUser.calls + deleteUser.calls + createUser.calls; calls != 5 {
+		t.Errorf("handlers ran %d times, want 5", calls)
+	}
+}
@@ -0,0 +1,485 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"path"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"shadow-id/internal/app/pipeline"
+)
+
+// OpenAPIPath serves the OpenAPI document describing the API
+const OpenAPIPath = BasePath + "/openapi.json"
+
+// APIVersion is the version of the API contract; it changes with BasePath
+// only on breaking changes
+const APIVersion = "1.0.0"
+
+// bearerScheme names the security scheme of API tokens
+const bearerScheme = "bearerToken"
+
+var rawMessageType = reflect.TypeOf(json.RawMessage(nil))
+
+// Document is an OpenAPI 3.1 document
+type Document struct {
+	OpenAPI    string                `json:"openapi"`
+	Info       Info                  `json:"info"`
+	Paths      map[string]PathItem   `json:"paths"`
+	Components Components            `json:"components"`
+	Security   []map[string][]string `json:"security"`
+}
+
+// Info describes the API
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// PathItem holds the operations of a path
+type PathItem struct {
+	Get    *Operation `json:"get,omitempty"`
+	Post   *Operation `json:"post,omitempty"`
+	Patch  *Operation `json:"patch,omitempty"`
+	Delete *Operation `json:"delete,omitempty"`
+}
+
+// Operation describes a single endpoint
+type Operation struct {
+	OperationID string                `json:"operationId"`
+	Tags        []string              `json:"tags"`
+	Summary     string                `json:"summary"`
+	Security    []map[string][]string `json:"security,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]Response   `json:"responses"`
+}
+
+// Parameter describes a path or query parameter of an operation
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required,omitempty"`
+	Schema   *Schema `json:"schema"`
+}
+
+// RequestBody describes the body an operation accepts
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response describes a response of an operation
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType holds the schema of a body
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Components holds the schemas and security schemes operations refer to
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes"`
+}
+
+// SecurityScheme describes how callers authenticate
+type SecurityScheme struct {
+	Type        string `json:"type"`
+	Scheme      string `json:"scheme"`
+	Description string `json:"description,omitempty"`
+}
+
+// Schema is a JSON Schema as used by OpenAPI 3.1. An empty schema accepts
+// any value.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	ContentEncoding      string             `json:"contentEncoding,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	MinProperties        *int               `json:"minProperties,omitempty"`
+	MaxProperties        *int               `json:"maxProperties,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+}
+
+// NewDocument describes every route of the API. Request schemas come from
+// the JSON and validate tags of the commands and queries, response schemas
+// from the results their handlers on the bus return.
+func NewDocument(bus *pipeline.Bus) *Document {
+	schemas := newSchemaSet()
+	errorResponse := Response{
+		Description: "The request failed",
+		Content:     jsonContent(schemas.of(reflect.TypeOf(ErrorResponse{}))),
+	}
+
+	doc := &Document{
+		OpenAPI: "3.1.0",
+		Info: Info{
+			Title:       "Shadow ID API",
+			Description: "Users, devices and sessions of Shadow ID as resources, and every command and query at its own endpoint taking the message as the JSON request body.",
+			Version:     APIVersion,
+		},
+		Paths: map[string]PathItem{
+			BasePath + "/health": {Get: &Operation{
+				OperationID: "health",
+				Tags:        []string{"server"},
+				Summary:     "Report that the server accepts requests",
+				Security:    []map[string][]string{{}},
+				Responses: map[string]Response{
+					"200": {Description: "The server accepts requests", Content: jsonContent(schemas.of(reflect.TypeOf(map[string]string{})))},
+				},
+			}},
+			OpenAPIPath: {Get: &Operation{
+				OperationID: "openAPI",
+				Tags:        []string{"server"},
+				Summary:     "Describe the API",
+				Security:    []map[string][]string{{}},
+				Responses: map[string]Response{
+					"200": {Description: "This document", Content: jsonContent(&Schema{Type: "object"})},
+				},
+			}},
+		},
+		// Operations that allow anonymous callers accept requests without a token
+		Security: []map[string][]string{{}, {bearerScheme: {}}},
+	}
+
+	for _, route := range Routes() {
+		result := &Schema{}
+		if resultType, ok := bus.ResultType(route.Message); ok {
+			result = schemas.of(resultType)
+		}
+		segment, _, _ := strings.Cut(strings.TrimPrefix(route.Path, BasePath+"/"), "/")
+
+		operation := &Operation{
+			OperationID: route.Operation,
+			Tags:        []string{segment},
+			Summary:     summarize(route.Name),
+			Parameters:  schemas.parameters(route),
+			Responses: map[string]Response{
+				strconv.Itoa(route.Status): {Description: "The " + string(route.Kind) + " succeeded", Content: jsonContent(result)},
+				"default":                  errorResponse,
+			},
+		}
+		switch {
+		case route.TakesQuery():
+		case len(route.Wildcards()) == 0:
+			operation.RequestBody = &RequestBody{
+				Required: true,
+				Content:  jsonContent(schemas.of(route.Message)),
+			}
+		default:
+			// The body holds the fields the path does not
+			body := schemas.object(route.Message)
+			for _, name := range route.Wildcards() {
+				delete(body.Properties, name)
+				body.Required = slices.DeleteFunc(body.Required, func(required string) bool { return required == name })
+			}
+			operation.RequestBody = &RequestBody{
+				Required: len(body.Required) > 0,
+				Content:  jsonContent(body),
+			}
+		}
+
+		item := doc.Paths[route.Path]
+		item.set(route.Method, operation)
+		doc.Paths[route.Path] = item
+	}
+
+	doc.Components = Components{
+		Schemas: schemas.schemas,
+		SecuritySchemes: map[string]SecurityScheme{
+			bearerScheme: {
+				Type:        "http",
+				Scheme:      "bearer",
+				Description: "API token configured in SERVER_API_TOKENS; requests act as the user it maps to",
+			},
+		},
+	}
+	return doc
+}
+
+// set adds the operation serving a method
+func (p *PathItem) set(method string, operation *Operation) {
+	switch method {
+	case http.MethodGet:
+		p.Get = operation
+	case http.MethodPost:
+		p.Post = operation
+	case http.MethodPatch:
+		p.Patch = operation
+	case http.MethodDelete:
+		p.Delete = operation
+	}
+}
+
+// Operation returns the operation serving a method, or nil if there is none
+func (p PathItem) Operation(method string) *Operation {
+	switch method {
+	case http.MethodGet:
+		return p.Get
+	case http.MethodPost:
+		return p.Post
+	case http.MethodPatch:
+		return p.Patch
+	case http.MethodDelete:
+		return p.Delete
+	}
+	return nil
+}
+
+// jsonContent returns a JSON body of the schema
+func jsonContent(schema *Schema) map[string]MediaType {
+	return map[string]MediaType{"application/json": {Schema: schema}}
+}
+
+// schemaSet collects the schemas of named struct types as components
+type schemaSet struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+}
+
+func newSchemaSet() *schemaSet {
+	return &schemaSet{
+		schemas: make(map[string]*Schema),
+		names:   make(map[reflect.Type]string),
+	}
+}
+
+// of returns the schema of a type, referring to named structs by component
+func (s *schemaSet) of(t reflect.Type) *Schema {
+	if t == rawMessageType {
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return s.of(t.Elem())
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		// Byte slices are encoded as base64 strings
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", ContentEncoding: "base64"}
+		}
+		return &Schema{Type: "array", Items: s.of(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: s.of(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return s.object(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + s.component(t)}
+	}
+	return &Schema{}
+}
+
+// component registers the schema of a named struct type and returns its
+// component name. Types of different packages sharing a name are told apart
+// by their package name.
+func (s *schemaSet) component(t reflect.Type) string {
+	if name, exists := s.names[t]; exists {
+		return name
+	}
+
+	name := t.Name()
+	if _, taken := s.schemas[name]; taken {
+		name = upperFirst(path.Base(t.PkgPath())) + name
+	}
+	s.names[t] = name
+	// Reserve the name before describing the fields so recursive types refer to it
+	s.schemas[name] = &Schema{}
+	*s.schemas[name] = *s.object(t)
+	return name
+}
+
+// parameters describes the path wildcards of a route and, for routes reading
+// the message from the query string, the other fields of its message
+func (s *schemaSet) parameters(route Route) []Parameter {
+	wildcards := route.Wildcards()
+	if len(wildcards) == 0 && !route.TakesQuery() {
+		return nil
+	}
+
+	var parameters []Parameter
+	for i := 0; i < route.Message.NumField(); i++ {
+		field := route.Message.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if !field.IsExported() || name == "" || name == "-" {
+			continue
+		}
+
+		schema := s.of(field.Type)
+		required := applyRules(schema, field.Type, field.Tag.Get("validate"))
+		switch {
+		case slices.Contains(wildcards, name):
+			parameters = append(parameters, Parameter{Name: name, In: "path", Required: true, Schema: schema})
+		case route.TakesQuery():
+			parameters = append(parameters, Parameter{Name: name, In: "query", Required: required, Schema: schema})
+		}
+	}
+	return parameters
+}
+
+// object describes the fields of a struct the way encoding/json encodes them
+func (s *schemaSet) object(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	s.addFields(schema, t)
+	return schema
+}
+
+// addFields adds the properties of a struct's fields to an object schema.
+// Fields of embedded structs without a JSON name are promoted.
+func (s *schemaSet) addFields(schema *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			s.addFields(schema, fieldType)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		property := s.of(field.Type)
+		if applyRules(property, fieldType, field.Tag.Get("validate")) {
+			schema.Required = append(schema.Required, name)
+		}
+		schema.Properties[name] = property
+	}
+}
+
+// applyRules adds the constraints of a validate tag to a property schema and
+// reports whether the property is required
+func applyRules(schema *Schema, t reflect.Type, tag string) bool {
+	if tag == "" {
+		return false
+	}
+
+	required, hasMin := false, false
+	for _, rule := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "required":
+			required = true
+		case "min", "max":
+			limit, err := strconv.ParseFloat(param, 64)
+			if err != nil {
+				continue
+			}
+			hasMin = hasMin || name == "min"
+			applyLimit(schema, t, name, limit)
+		case "email":
+			schema.Format = "email"
+		case "ip":
+			schema.Type = ""
+			schema.AnyOf = []*Schema{
+				{Type: "string", Format: "ipv4"},
+				{Type: "string", Format: "ipv6"},
+			}
+		}
+	}
+
+	// Required values must not be empty
+	if required && !hasMin {
+		switch t.Kind() {
+		case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+			applyLimit(schema, t, "min", 1)
+		}
+	}
+	return required
+}
+
+// applyLimit sets the bound a min or max rule puts on a value of the type:
+// its length in characters, its number of items or its value
+func applyLimit(schema *Schema, t reflect.Type, rule string, limit float64) {
+	size := int(limit)
+	var bound **int
+	switch t.Kind() {
+	case reflect.String:
+		bound = pick(rule, &schema.MinLength, &schema.MaxLength)
+	case reflect.Slice, reflect.Array:
+		bound = pick(rule, &schema.MinItems, &schema.MaxItems)
+	case reflect.Map:
+		bound = pick(rule, &schema.MinProperties, &schema.MaxProperties)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		if rule == "min" {
+			schema.Minimum = &limit
+		} else {
+			schema.Maximum = &limit
+		}
+		return
+	default:
+		return
+	}
+	*bound = &size
+}
+
+// pick returns the minimum or maximum bound for a min or max rule
+func pick(rule string, minimum, maximum **int) **int {
+	if rule == "min" {
+		return minimum
+	}
+	return maximum
+}
+
+// lowerFirst lower-cases the first letter of an ASCII name
+func lowerFirst(name string) string {
+	if name == "" {
+		return name
+	}
+	return strings.ToLower(name[:1]) + name[1:]
+}
+
+// upperFirst upper-cases the first letter of an ASCII name
+func upperFirst(name string) string {
+	if name == "" {
+		return name
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// summarize turns a name such as "GetUserTimeline" into "Get user timeline"
+func summarize(name string) string {
+	return upperFirst(strings.ReplaceAll(kebabCase(name), "-", " "))
+}
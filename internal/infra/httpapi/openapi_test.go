@@ -0,0 +1,132 @@
+package httpapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"shadow-id/internal/app/services"
+)
+
+// publishedDocument is the OpenAPI document integrators build against
+var publishedDocument = filepath.Join("..", "..", "..", "docs", "openapi.json")
+
+var update = flag.Bool("update", false, "rewrite the published OpenAPI document")
+
+// generateDocument encodes the document of every handler the application
+// registers. Handlers are only inspected, so they need no dependencies.
+func generateDocument(t *testing.T) []byte {
+	t.Helper()
+
+	appService, err := services.NewApplicationService(services.Dependencies{})
+	if err != nil {
+		t.Fatalf("NewApplicationService() error = %v", err)
+	}
+	encoded, err := json.MarshalIndent(NewDocument(appService.Bus), "", "  ")
+	if err != nil {
+		t.Fatalf("MarshalIndent() error = %v", err)
+	}
+	return append(encoded, '\n')
+}
+
+func TestPublishedDocumentMatchesCode(t *testing.T) {
+	generated := generateDocument(t)
+
+	if *update {
+		if err := os.WriteFile(publishedDocument, generated, 0o644); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+		return
+	}
+
+	published, err := os.ReadFile(publishedDocument)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if !bytes.Equal(published, generated) {
+		t.Fatalf("%s does not match the commands and queries; review the change and run go test ./internal/infra/httpapi -update", publishedDocument)
+	}
+}
+
+func TestDocumentDescribesEveryRoute(t *testing.T) {
+	var doc Document
+	if err := json.Unmarshal(generateDocument(t), &doc); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	for _, route := range Routes() {
+		operation := doc.Paths[route.Path].Operation(route.Method)
+		if operation == nil {
+			t.Errorf("no %s operation for %s", route.Method, route.Path)
+			continue
+		}
+		if operation.Responses[strconv.Itoa(route.Status)].Content["application/json"].Schema == nil {
+			t.Errorf("%s %s has no result schema", route.Method, route.Path)
+		}
+
+		// Every wildcard is a path parameter and the message is read from
+		// the query string or the body
+		parameters := make(map[string]string)
+		for _, parameter := range operation.Parameters {
+			parameters[parameter.Name] = parameter.In
+		}
+		for _, name := range route.Wildcards() {
+			if parameters[name] != "path" {
+				t.Errorf("%s %s does not describe path parameter %q", route.Method, route.Path, name)
+			}
+		}
+		if route.TakesQuery() {
+			if operation.RequestBody != nil {
+				t.Errorf("%s %s describes a body", route.Method, route.Path)
+			}
+			continue
+		}
+		if operation.RequestBody == nil {
+			t.Errorf("%s %s has no request body", route.Method, route.Path)
+			continue
+		}
+		body := operation.RequestBody.Content["application/json"].Schema
+		if ref := body.Ref; ref != "" {
+			if _, exists := doc.Components.Schemas[filepath.Base(ref)]; !exists {
+				t.Errorf("%s %s refers to missing request schema %q", route.Method, route.Path, ref)
+			}
+		}
+		for _, name := range route.Wildcards() {
+			if _, exists := body.Properties[name]; exists {
+				t.Errorf("%s %s takes path parameter %q in the body too", route.Method, route.Path, name)
+			}
+		}
+	}
+}
+
+func TestValidateTagsBecomeConstraints(t *testing.T) {
+	var doc Document
+	if err := json.Unmarshal(generateDocument(t), &doc); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	createUser := doc.Components.Schemas["CreateUserCommand"]
+	if createUser == nil {
+		t.Fatal("CreateUserCommand schema is missing")
+	}
+	if got, want := createUser.Required, []string{"name", "email"}; len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("CreateUserCommand required = %v, want %v", got, want)
+	}
+
+	name := createUser.Properties["name"]
+	if name.MinLength == nil || *name.MinLength != 2 || name.MaxLength == nil || *name.MaxLength != 100 {
+		t.Errorf("name length bounds = %v..%v, want 2..100", name.MinLength, name.MaxLength)
+	}
+	if email := createUser.Properties["email"]; email.Format != "email" {
+		t.Errorf("email format = %q, want %q", email.Format, "email")
+	}
+
+	limit := doc.Components.Schemas["ListUsersQuery"].Properties["limit"]
+	if limit.Type != "integer" || limit.Maximum == nil || *limit.Maximum != 500 {
+		t.Errorf("limit = %+v, want an integer of at most 500", limit)
+	}
+}
@@ -0,0 +1,153 @@
+package httpapi
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"shadow-id/internal/app/commands"
+	"shadow-id/internal/app/pipeline"
+	"shadow-id/internal/app/queries"
+)
+
+// BasePath prefixes every endpoint of the API
+const BasePath = "/api/v1"
+
+// localOnly lists the messages that only make sense for the machine the
+// application runs on. A remote caller would learn the fingerprint of the
+// server instead of its own, so they are not served over HTTP.
+var localOnly = map[reflect.Type]bool{
+	reflect.TypeOf(queries.GetCurrentDeviceQuery{}): true,
+}
+
+// resource is an endpoint of the users, devices and sessions resources and
+// the message it serves
+type resource struct {
+	method    string
+	path      string
+	operation string
+	message   interface{}
+	status    int
+}
+
+// resources map the main resources to endpoints with the verbs for what
+// their messages do. The command and query endpoints serve the same
+// messages and every other one.
+var resources = []resource{
+	{http.MethodGet, "/users", "usersList", queries.ListUsersQuery{}, http.StatusOK},
+	{http.MethodPost, "/users", "usersCreate", commands.CreateUserCommand{}, http.StatusCreated},
+	{http.MethodGet, "/users/{id}", "usersGet", queries.GetUserQuery{}, http.StatusOK},
+	{http.MethodPatch, "/users/{id}", "usersUpdate", commands.UpdateUserCommand{}, http.StatusOK},
+	{http.MethodDelete, "/users/{id}", "usersDelete", commands.DeleteUserCommand{}, http.StatusOK},
+	{http.MethodPost, "/users/{id}/restore", "usersRestore", commands.RestoreUserCommand{}, http.StatusOK},
+	{http.MethodGet, "/users/{user_id}/history", "usersHistory", queries.GetUserHistoryQuery{}, http.StatusOK},
+	{http.MethodGet, "/users/{user_id}/timeline", "usersTimeline", queries.GetUserTimelineQuery{}, http.StatusOK},
+	{http.MethodGet, "/users/{user_id}/devices", "userDevicesList", queries.ListUserDevicesQuery{}, http.StatusOK},
+	{http.MethodPost, "/users/{user_id}/devices", "userDevicesRegister", commands.RegisterDeviceCommand{}, http.StatusCreated},
+	{http.MethodGet, "/devices/pending", "devicesListPending", queries.ListPendingDevicesQuery{}, http.StatusOK},
+	{http.MethodDelete, "/devices/{device_id}", "devicesRevoke", commands.RevokeDeviceCommand{}, http.StatusOK},
+	{http.MethodPost, "/devices/{device_id}/approval", "devicesApprove", commands.ApproveDeviceCommand{}, http.StatusOK},
+	{http.MethodPost, "/devices/{device_id}/suspension", "devicesSuspend", commands.SuspendDeviceCommand{}, http.StatusOK},
+	{http.MethodPost, "/devices/{device_id}/heartbeats", "deviceHeartbeatsCreate", commands.RecordDeviceHeartbeatCommand{}, http.StatusCreated},
+	{http.MethodPost, "/sessions/challenges", "sessionChallengesCreate", commands.BeginPasskeyLoginCommand{}, http.StatusCreated},
+	{http.MethodPost, "/sessions", "sessionsCreate", commands.FinishPasskeyLoginCommand{}, http.StatusCreated},
+	{http.MethodDelete, "/sessions/current", "sessionsDelete", commands.SignOutCommand{}, http.StatusOK},
+}
+
+// Route is an endpoint serving a command or query. Command and query
+// endpoints accept a POST with the message as its JSON body; resource
+// endpoints fill the message fields named by their path wildcards from the
+// path and take the others from the query string of GET and DELETE requests
+// or from the JSON body of the rest. Every route answers with the result.
+type Route struct {
+	Kind      pipeline.Kind
+	Name      string
+	Method    string
+	Path      string
+	Operation string
+
+	// Message is the type of the command or query the request is decoded into
+	Message reflect.Type
+
+	// Status is the status of a successful response
+	Status int
+}
+
+// Routes returns an endpoint for every command and query served over HTTP,
+// commands first, each in the order they are listed, followed by the
+// resource endpoints
+func Routes() []Route {
+	routes := make([]Route, 0)
+	add := func(kind pipeline.Kind, segment, suffix string, messages []interface{}) {
+		for _, msg := range messages {
+			msgType := reflect.TypeOf(msg)
+			if localOnly[msgType] {
+				continue
+			}
+			name := strings.TrimSuffix(msgType.Name(), suffix)
+			routes = append(routes, Route{
+				Kind:      kind,
+				Name:      name,
+				Method:    http.MethodPost,
+				Path:      BasePath + "/" + segment + "/" + kebabCase(name),
+				Operation: lowerFirst(name),
+				Message:   msgType,
+				Status:    http.StatusOK,
+			})
+		}
+	}
+	add(pipeline.KindCommand, "commands", "Command", commands.All())
+	add(pipeline.KindQuery, "queries", "Query", queries.All())
+
+	for _, res := range resources {
+		msgType := reflect.TypeOf(res.message)
+		kind, name := pipeline.KindCommand, strings.TrimSuffix(msgType.Name(), "Command")
+		if strings.HasSuffix(msgType.Name(), "Query") {
+			kind, name = pipeline.KindQuery, strings.TrimSuffix(msgType.Name(), "Query")
+		}
+		routes = append(routes, Route{
+			Kind:      kind,
+			Name:      name,
+			Method:    res.method,
+			Path:      BasePath + res.path,
+			Operation: res.operation,
+			Message:   msgType,
+			Status:    res.status,
+		})
+	}
+	return routes
+}
+
+// Wildcards returns the names of the route's path wildcards, such as "id"
+// for "/users/{id}"
+func (r Route) Wildcards() []string {
+	var names []string
+	for _, segment := range strings.Split(r.Path, "/") {
+		if name, ok := strings.CutPrefix(segment, "{"); ok {
+			names = append(names, strings.TrimSuffix(name, "}"))
+		}
+	}
+	return names
+}
+
+// TakesQuery reports whether the route reads the message from the query
+// string rather than the body
+func (r Route) TakesQuery() bool {
+	return r.Method == http.MethodGet || r.Method == http.MethodDelete
+}
+
+// kebabCase turns a name such as "GetUserTimeline" into "get-user-timeline"
+func kebabCase(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('-')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,131 @@
+package httpapi
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"shadow-id/internal/app/pipeline"
+	"shadow-id/internal/infra/ratelimit"
+	"shadow-id/pkg/errors"
+	"shadow-id/pkg/logger"
+	"shadow-id/pkg/types"
+)
+
+// Config holds HTTP API server configuration
+type Config struct {
+	Addr string
+
+	ReadTimeout     time.Duration
+	WriteTimeout    time.Duration
+	IdleTimeout     time.Duration
+	ShutdownTimeout time.Duration
+
+	// Tokens maps bearer tokens to the IDs of the users they act as
+	Tokens map[string]types.ID
+
+	// AnonymousRateLimit bounds the requests each client address may make
+	// without a valid token
+	AnonymousRateLimit ratelimit.TokenBucketConfig
+}
+
+// Server exposes the commands and queries of the bus as JSON endpoints. It
+// serves the same handlers as the desktop application, so every request
+// goes through the same validation, authorization and audit middleware.
+type Server struct {
+	config Config
+	logger logger.Logger
+	server *http.Server
+
+	mu       sync.Mutex
+	listener net.Listener
+	done     chan struct{}
+}
+
+// NewServer creates a new HTTP API server
+func NewServer(bus *pipeline.Bus, config Config, log logger.Logger) *Server {
+	if config.ShutdownTimeout <= 0 {
+		config.ShutdownTimeout = 10 * time.Second
+	}
+	return &Server{
+		config: config,
+		logger: log,
+		server: &http.Server{
+			Addr:         config.Addr,
+			Handler:      NewHandler(bus, config.Tokens, ratelimit.NewTokenBucket(config.AnonymousRateLimit), log),
+			ReadTimeout:  config.ReadTimeout,
+			WriteTimeout: config.WriteTimeout,
+			IdleTimeout:  config.IdleTimeout,
+		},
+	}
+}
+
+// Start listens on the configured address and serves requests in the
+// background. Listening happens right away so a taken port is reported to
+// the caller.
+func (s *Server) Start() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.listener != nil {
+		return nil
+	}
+	listener, err := net.Listen("tcp", s.config.Addr)
+	if err != nil {
+		return errors.WrapWithType(err, errors.ErrorTypeInternal, "failed to listen for HTTP API requests").
+			WithDetail("addr", s.config.Addr)
+	}
+	s.listener = listener
+	done := make(chan struct{})
+	s.done = done
+
+	go func() {
+		defer close(done)
+		if err := s.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			s.logger.Error("HTTP API server failed", "error", err)
+		}
+	}()
+
+	s.logger.Info("HTTP API server started", "addr", listener.Addr().String())
+	return nil
+}
+
+// Addr returns the address the server listens on, or an empty string if it
+// was not started
+func (s *Server) Addr() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.listener == nil {
+		return ""
+	}
+	return s.listener.Addr().String()
+}
+
+// Shutdown stops accepting requests and waits for requests in flight to
+// finish, at most for the shutdown timeout. Connections still open then are
+// closed.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	listener, done := s.listener, s.done
+	s.mu.Unlock()
+
+	if listener == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.config.ShutdownTimeout)
+	defer cancel()
+
+	err := s.server.Shutdown(ctx)
+	if err != nil {
+		s.logger.Warn("HTTP API server did not shut down in time", "error", err)
+		err = s.server.Close()
+	}
+	<-done
+
+	s.logger.Info("HTTP API server stopped")
+	return err
+}
@@ -0,0 +1,114 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"shadow-id/internal/app/auth"
+	"shadow-id/internal/app/commands"
+	"shadow-id/internal/app/pipeline"
+	"shadow-id/pkg/logger"
+)
+
+// DeviceSweeperConfig holds stale device sweep job configuration
+type DeviceSweeperConfig struct {
+	Interval  time.Duration
+	BatchSize int
+}
+
+// DeviceSweeper periodically marks devices stale that stopped sending
+// heartbeats. Like the user purger, it dispatches its command through the
+// bus as the system principal.
+type DeviceSweeper struct {
+	bus    *pipeline.Bus
+	config DeviceSweeperConfig
+	logger logger.Logger
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewDeviceSweeper creates a new stale device sweep job
+func NewDeviceSweeper(bus *pipeline.Bus, config DeviceSweeperConfig, log logger.Logger) *DeviceSweeper {
+	if config.Interval <= 0 {
+		config.Interval = 5 * time.Minute
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 100
+	}
+	return &DeviceSweeper{
+		bus:    bus,
+		config: config,
+		logger: log,
+	}
+}
+
+// Start begins sweeping in the background
+func (s *DeviceSweeper) Start(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cancel != nil {
+		return
+	}
+	ctx, s.cancel = context.WithCancel(ctx)
+	done := make(chan struct{})
+	s.done = done
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(s.config.Interval)
+		defer ticker.Stop()
+
+		for {
+			s.run(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop stops sweeping and waits for a run in progress to finish
+func (s *DeviceSweeper) Stop() {
+	s.mu.Lock()
+	cancel, done := s.cancel, s.done
+	s.cancel, s.done = nil, nil
+	s.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-done
+}
+
+// SweepStale marks a batch of unseen devices stale and returns how many were marked
+func (s *DeviceSweeper) SweepStale(ctx context.Context) (int, error) {
+	ctx = auth.WithPrincipal(ctx, auth.SystemPrincipal())
+	result, err := pipeline.Send[*commands.MarkStaleDevicesResult](ctx, s.bus, commands.MarkStaleDevicesCommand{
+		Limit: s.config.BatchSize,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result.Marked, nil
+}
+
+// run sweeps a batch and logs the outcome
+func (s *DeviceSweeper) run(ctx context.Context) {
+	marked, err := s.SweepStale(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			s.logger.Error("Stale device sweep failed", "error", err)
+		}
+		return
+	}
+	if marked > 0 {
+		s.logger.Info("Marked devices stale", "count", marked)
+	}
+}
@@ -0,0 +1,116 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"shadow-id/internal/app/auth"
+	"shadow-id/internal/app/commands"
+	"shadow-id/internal/app/pipeline"
+	"shadow-id/pkg/logger"
+)
+
+// UserPurgerConfig holds user purge job configuration
+type UserPurgerConfig struct {
+	Interval  time.Duration
+	BatchSize int
+}
+
+// UserPurger periodically hard-deletes users whose soft-deletion retention
+// period has passed. It dispatches the purge command through the bus as the
+// system principal, so every run is authorized, transactional and audited
+// like any other command.
+type UserPurger struct {
+	bus    *pipeline.Bus
+	config UserPurgerConfig
+	logger logger.Logger
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewUserPurger creates a new user purge job
+func NewUserPurger(bus *pipeline.Bus, config UserPurgerConfig, log logger.Logger) *UserPurger {
+	if config.Interval <= 0 {
+		config.Interval = time.Hour
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 100
+	}
+	return &UserPurger{
+		bus:    bus,
+		config: config,
+		logger: log,
+	}
+}
+
+// Start begins purging in the background
+func (p *UserPurger) Start(ctx context.Context) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.cancel != nil {
+		return
+	}
+	ctx, p.cancel = context.WithCancel(ctx)
+	done := make(chan struct{})
+	p.done = done
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(p.config.Interval)
+		defer ticker.Stop()
+
+		for {
+			p.run(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop stops purging and waits for a run in progress to finish
+func (p *UserPurger) Stop() {
+	p.mu.Lock()
+	cancel, done := p.cancel, p.done
+	p.cancel, p.done = nil, nil
+	p.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-done
+}
+
+// PurgeDue purges a batch of users past their retention period and returns
+// how many were purged
+func (p *UserPurger) PurgeDue(ctx context.Context) (int, error) {
+	ctx = auth.WithPrincipal(ctx, auth.SystemPrincipal())
+	result, err := pipeline.Send[*commands.PurgeDeletedUsersResult](ctx, p.bus, commands.PurgeDeletedUsersCommand{
+		Limit: p.config.BatchSize,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result.Purged, nil
+}
+
+// run purges a batch and logs the outcome
+func (p *UserPurger) run(ctx context.Context) {
+	purged, err := p.PurgeDue(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			p.logger.Error("User purge failed", "error", err)
+		}
+		return
+	}
+	if purged > 0 {
+		p.logger.Info("Purged deleted users", "count", purged)
+	}
+}
@@ -0,0 +1,162 @@
+package outbox
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/repositories"
+	"shadow-id/internal/domain/services"
+	"shadow-id/pkg/errors"
+	"shadow-id/pkg/logger"
+	"shadow-id/pkg/types"
+)
+
+// Config holds outbox relay configuration
+type Config struct {
+	PollInterval time.Duration
+	BatchSize    int
+	Policy       entities.DeliveryPolicy
+}
+
+// Relay delivers outbox entries to the event publisher at least once.
+// Failed deliveries are retried with exponential backoff; an entry is only
+// marked delivered after the publisher accepted it, so a crash in between
+// causes a redelivery rather than a lost event.
+type Relay struct {
+	repo      repositories.OutboxRepository
+	publisher services.EventPublisher
+	config    Config
+	logger    logger.Logger
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewRelay creates a new outbox relay
+func NewRelay(
+	repo repositories.OutboxRepository,
+	publisher services.EventPublisher,
+	config Config,
+	log logger.Logger,
+) *Relay {
+	if config.PollInterval <= 0 {
+		config.PollInterval = time.Second
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 100
+	}
+	return &Relay{
+		repo:      repo,
+		publisher: publisher,
+		config:    config,
+		logger:    log,
+	}
+}
+
+// Start begins polling the outbox in the background
+func (r *Relay) Start(ctx context.Context) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.cancel != nil {
+		return
+	}
+	ctx, r.cancel = context.WithCancel(ctx)
+	done := make(chan struct{})
+	r.done = done
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(r.config.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			r.poll(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop stops polling and makes a final delivery pass so entries written just
+// before shutdown are not left waiting for the next start
+func (r *Relay) Stop(ctx context.Context) {
+	r.mu.Lock()
+	cancel, done := r.cancel, r.done
+	r.cancel, r.done = nil, nil
+	r.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-done
+	r.poll(ctx)
+}
+
+// DeliverDue makes one delivery pass over the entries that are due and
+// returns how many were delivered
+func (r *Relay) DeliverDue(ctx context.Context) (int, error) {
+	now := time.Now()
+	entries, err := r.repo.ListDue(ctx, now, r.config.BatchSize)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to list outbox entries")
+	}
+
+	delivered := 0
+	blocked := make(map[types.ID]bool)
+	for _, entry := range entries {
+		// Keep per-aggregate order: once an entry fails, later ones wait
+		if blocked[entry.AggregateID] {
+			continue
+		}
+
+		if err := r.deliver(ctx, entry); err != nil {
+			blocked[entry.AggregateID] = true
+			entry.MarkFailed(time.Now(), err, r.config.Policy)
+			r.logFailure(entry, err)
+		} else {
+			entry.MarkDelivered(time.Now())
+			delivered++
+		}
+
+		if err := r.repo.Update(ctx, entry); err != nil {
+			return delivered, errors.Wrap(err, "failed to update outbox entry")
+		}
+	}
+	return delivered, nil
+}
+
+// deliver decodes an entry and hands its event to the publisher
+func (r *Relay) deliver(ctx context.Context, entry *entities.OutboxEntry) error {
+	event, err := entry.Event()
+	if err != nil {
+		return errors.Wrap(err, "failed to decode event")
+	}
+	return r.publisher.Publish(ctx, event)
+}
+
+// poll runs a delivery pass and logs its failure
+func (r *Relay) poll(ctx context.Context) {
+	if _, err := r.DeliverDue(ctx); err != nil {
+		r.logger.Error("Outbox delivery pass failed", "error", err)
+	}
+}
+
+// logFailure reports a failed delivery, distinguishing parked entries
+func (r *Relay) logFailure(entry *entities.OutboxEntry, err error) {
+	if entry.DeadAt != nil {
+		r.logger.Error("Outbox entry parked after repeated failures",
+			"entry_id", entry.ID, "event", entry.EventName, "attempts", entry.Attempts, "error", err)
+		return
+	}
+	r.logger.Warn("Outbox delivery failed, will retry",
+		"entry_id", entry.ID, "event", entry.EventName, "attempts", entry.Attempts,
+		"next_attempt_at", entry.NextAttemptAt, "error", err)
+}
@@ -0,0 +1,120 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/events"
+	"shadow-id/internal/infra/storage/memory"
+	"shadow-id/pkg/logger"
+	"shadow-id/pkg/types"
+)
+
+// flakyPublisher fails the first delivery of the events it is told to and
+// records the events it accepted in order
+type flakyPublisher struct {
+	mu        sync.Mutex
+	failOnce  map[types.ID]bool
+	published []events.Event
+}
+
+func (p *flakyPublisher) Publish(ctx context.Context, evts ...events.Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, event := range evts {
+		if p.failOnce[event.EventID()] {
+			delete(p.failOnce, event.EventID())
+			return fmt.Errorf("subscriber unavailable")
+		}
+		p.published = append(p.published, event)
+	}
+	return nil
+}
+
+// names returns the names of the published events of an aggregate
+func (p *flakyPublisher) names(aggregateID types.ID) []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var names []string
+	for _, event := range p.published {
+		if event.AggregateID() == aggregateID {
+			names = append(names, event.EventName())
+		}
+	}
+	return names
+}
+
+func TestFailedEntryHoldsBackLaterEntriesOfItsAggregate(t *testing.T) {
+	ctx := context.Background()
+	outboxRepo := memory.NewOutboxRepository()
+	userRepo := memory.NewUserRepository(outboxRepo)
+
+	// Alice raises two events in order, Bob one
+	alice := entities.NewUser("Alice", "alice@example.com")
+	if err := userRepo.Create(ctx, alice); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	alice.UpdateName("Alice Liddell")
+	if err := userRepo.Update(ctx, alice); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	bob := entities.NewUser("Bob", "bob@example.com")
+	if err := userRepo.Create(ctx, bob); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	entries, err := outboxRepo.ListDue(ctx, time.Now(), 0)
+	if err != nil {
+		t.Fatalf("ListDue() error = %v", err)
+	}
+	var aliceEvents []string
+	publisher := &flakyPublisher{failOnce: make(map[types.ID]bool)}
+	for _, entry := range entries {
+		if entry.AggregateID == alice.ID {
+			aliceEvents = append(aliceEvents, entry.EventName)
+			if len(aliceEvents) == 1 {
+				publisher.failOnce[entry.EventID] = true
+			}
+		}
+	}
+	if len(aliceEvents) < 2 {
+		t.Fatalf("alice raised %d events, want at least 2", len(aliceEvents))
+	}
+
+	backoff := 20 * time.Millisecond
+	relay := NewRelay(outboxRepo, publisher, Config{
+		Policy: entities.DeliveryPolicy{BaseDelay: backoff, MaxDelay: backoff},
+	}, logger.New("error"))
+
+	// The first pass fails Alice's first event and delivers Bob's
+	if _, err := relay.DeliverDue(ctx); err != nil {
+		t.Fatalf("DeliverDue() error = %v", err)
+	}
+	if got := publisher.names(bob.ID); len(got) == 0 {
+		t.Error("Bob's events were held back by Alice's failure")
+	}
+
+	// While the failed event backs off, Alice's later events keep waiting
+	if _, err := relay.DeliverDue(ctx); err != nil {
+		t.Fatalf("DeliverDue() error = %v", err)
+	}
+	if got := publisher.names(alice.ID); len(got) != 0 {
+		t.Fatalf("delivered %v while an earlier event was backing off", got)
+	}
+
+	// Once it is due again, everything is delivered in the order it was raised
+	time.Sleep(backoff)
+	if _, err := relay.DeliverDue(ctx); err != nil {
+		t.Fatalf("DeliverDue() error = %v", err)
+	}
+	got := publisher.names(alice.ID)
+	if fmt.Sprint(got) != fmt.Sprint(aliceEvents) {
+		t.Errorf("delivered %v, want %v", got, aliceEvents)
+	}
+}
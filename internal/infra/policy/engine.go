@@ -0,0 +1,93 @@
+package policy
+
+import (
+	"context"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/services"
+	"shadow-id/pkg/logger"
+)
+
+// Engine evaluates attribute-based policies with deny-overrides combining:
+// a matching deny wins over any allow, and a request no policy matches is
+// not applicable and left to role-based checks.
+type Engine struct {
+	policies []entities.Policy
+	dryRun   bool
+	logger   logger.Logger
+}
+
+// NewEngine creates a policy engine. In dry-run mode decisions are computed
+// and logged but every request is allowed.
+func NewEngine(policies []entities.Policy, dryRun bool, log logger.Logger) *Engine {
+	return &Engine{
+		policies: append([]entities.Policy(nil), policies...),
+		dryRun:   dryRun,
+		logger:   log,
+	}
+}
+
+// Evaluate decides a request and explains which policy produced the outcome
+func (e *Engine) Evaluate(ctx context.Context, request entities.PolicyRequest) services.PolicyDecision {
+	decision := services.PolicyDecision{
+		Effect:      entities.PolicyEffectNotApplicable,
+		Reason:      "no policy matched",
+		DryRun:      e.dryRun,
+		Evaluations: make([]services.PolicyEvaluation, 0, len(e.policies)),
+	}
+
+	for i := range e.policies {
+		policy := &e.policies[i]
+		matched, reason := policy.Matches(request)
+		decision.Evaluations = append(decision.Evaluations, services.PolicyEvaluation{
+			PolicyID: policy.ID,
+			Effect:   policy.Effect,
+			Matched:  matched,
+			DryRun:   policy.DryRun,
+			Reason:   reason,
+		})
+		if !matched {
+			continue
+		}
+
+		// Dry-run policies are reported but never decide
+		if policy.DryRun {
+			e.logger.Info("Dry-run policy matched",
+				"policy", policy.ID, "effect", policy.Effect, "action", request.Action)
+			continue
+		}
+
+		switch {
+		case policy.Effect == entities.PolicyEffectDeny && decision.Effect != entities.PolicyEffectDeny:
+			decision.Effect = entities.PolicyEffectDeny
+			decision.PolicyID = policy.ID
+			decision.Reason = policyReason(policy, reason)
+		case policy.Effect == entities.PolicyEffectAllow && decision.Effect == entities.PolicyEffectNotApplicable:
+			decision.Effect = entities.PolicyEffectAllow
+			decision.PolicyID = policy.ID
+			decision.Reason = policyReason(policy, reason)
+		}
+	}
+
+	decision.Allowed = decision.Effect != entities.PolicyEffectDeny
+	if e.dryRun && !decision.Allowed {
+		e.logger.Warn("Dry-run mode allowed a request denied by policy",
+			"policy", decision.PolicyID, "action", request.Action, "reason", decision.Reason)
+		decision.Allowed = true
+	}
+
+	return decision
+}
+
+// Policies returns the loaded policies
+func (e *Engine) Policies() []entities.Policy {
+	return append([]entities.Policy(nil), e.policies...)
+}
+
+// policyReason builds the explanation for a deciding policy
+func policyReason(policy *entities.Policy, reason string) string {
+	if policy.Description == "" {
+		return reason
+	}
+	return policy.Description + " (" + reason + ")"
+}
@@ -0,0 +1,44 @@
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"shadow-id/internal/domain/entities"
+)
+
+// policyFile is the on-disk policy document format
+type policyFile struct {
+	Policies []entities.Policy `json:"policies"`
+}
+
+// LoadFile reads and validates policies from a JSON file
+func LoadFile(path string) ([]entities.Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Parse decodes and validates a policy document
+func Parse(data []byte) ([]entities.Policy, error) {
+	var file policyFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("%w: %v", entities.ErrInvalidPolicy, err)
+	}
+
+	seen := make(map[string]bool, len(file.Policies))
+	for i := range file.Policies {
+		policy := &file.Policies[i]
+		if err := policy.Validate(); err != nil {
+			return nil, err
+		}
+		if seen[policy.ID] {
+			return nil, fmt.Errorf("%w: %s", entities.ErrDuplicatePolicy, policy.ID)
+		}
+		seen[policy.ID] = true
+	}
+	return file.Policies, nil
+}
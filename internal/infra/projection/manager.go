@@ -0,0 +1,140 @@
+package projection
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"shadow-id/internal/domain/events"
+	"shadow-id/internal/domain/repositories"
+	"shadow-id/internal/domain/services"
+	"shadow-id/internal/infra/eventbus"
+	"shadow-id/pkg/errors"
+	"shadow-id/pkg/logger"
+)
+
+// historyBatchSize is the number of history entries replayed at a time
+const historyBatchSize = 500
+
+// Projection builds a read model from domain events. Events may be delivered
+// more than once, so handling an event must be idempotent.
+type Projection interface {
+	// Name identifies the projection
+	Name() string
+
+	// Handle applies an event to the read model
+	Handle(ctx context.Context, event events.Event) error
+
+	// Reset removes everything the projection has built
+	Reset(ctx context.Context) error
+}
+
+// Manager feeds domain events to projections and rebuilds them from the event
+// history. Live events and rebuilds are serialized so a rebuild never
+// interleaves with an incremental update.
+type Manager struct {
+	projections []Projection
+	history     repositories.OutboxRepository
+	logger      logger.Logger
+
+	mu sync.Mutex
+}
+
+// NewManager creates a projection manager replaying the outbox history
+func NewManager(history repositories.OutboxRepository, log logger.Logger, projections ...Projection) *Manager {
+	return &Manager{
+		projections: projections,
+		history:     history,
+		logger:      log,
+	}
+}
+
+// Subscribe keeps every projection up to date with published events. The
+// subscriptions are synchronous so events are applied in the order the
+// outbox relay delivers them, and a failure is retried by the relay.
+func (m *Manager) Subscribe(bus *eventbus.Bus) {
+	for _, projection := range m.projections {
+		projection := projection
+		bus.Subscribe("projection:"+projection.Name(), eventbus.AllEvents, func(ctx context.Context, event events.Event) error {
+			m.mu.Lock()
+			defer m.mu.Unlock()
+
+			return projection.Handle(ctx, event)
+		})
+	}
+}
+
+// Projections returns the names of the registered projections
+func (m *Manager) Projections() []string {
+	names := make([]string, len(m.projections))
+	for i, projection := range m.projections {
+		names[i] = projection.Name()
+	}
+	return names
+}
+
+// Rebuild clears the named projection, or every projection if the name is
+// empty, and replays the full event history into it
+func (m *Manager) Rebuild(ctx context.Context, name string) ([]services.ProjectionRebuild, error) {
+	selected := make([]Projection, 0, len(m.projections))
+	for _, projection := range m.projections {
+		if name == "" || projection.Name() == name {
+			selected = append(selected, projection)
+		}
+	}
+	if len(selected) == 0 {
+		return nil, errors.NewNotFoundError("projection not found").WithDetail("name", name)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	reports := make([]services.ProjectionRebuild, 0, len(selected))
+	for _, projection := range selected {
+		report, err := m.rebuild(ctx, projection)
+		if err != nil {
+			return reports, err
+		}
+		m.logger.Info("Projection rebuilt",
+			"projection", report.Name, "events", report.Events, "duration", report.Duration)
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+// rebuild resets a projection and replays the history into it
+func (m *Manager) rebuild(ctx context.Context, projection Projection) (services.ProjectionRebuild, error) {
+	started := time.Now()
+	report := services.ProjectionRebuild{Name: projection.Name()}
+
+	if err := projection.Reset(ctx); err != nil {
+		return report, errors.Wrap(err, "failed to reset projection").WithDetail("projection", report.Name)
+	}
+
+	for offset := 0; ; offset += historyBatchSize {
+		entries, err := m.history.History(ctx, historyBatchSize, offset)
+		if err != nil {
+			return report, errors.Wrap(err, "failed to load event history")
+		}
+		for _, entry := range entries {
+			event, err := entry.Event()
+			if err != nil {
+				return report, errors.Wrap(err, "failed to decode event").
+					WithDetail("projection", report.Name).
+					WithDetail("event_id", entry.EventID)
+			}
+			if err := projection.Handle(ctx, event); err != nil {
+				return report, errors.Wrap(err, "failed to replay event").
+					WithDetail("projection", report.Name).
+					WithDetail("event_id", entry.EventID)
+			}
+			report.Events++
+		}
+		if len(entries) < historyBatchSize {
+			break
+		}
+	}
+
+	report.Duration = time.Since(started)
+	return report, nil
+}
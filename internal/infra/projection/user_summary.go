@@ -0,0 +1,162 @@
+package projection
+
+import (
+	"context"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/events"
+	"shadow-id/internal/domain/repositories"
+	"shadow-id/pkg/types"
+)
+
+// UserSummaryProjectionName identifies the user summary projection
+const UserSummaryProjectionName = "user_summary"
+
+// UserSummaryProjection maintains the user summary read model: the profile
+// together with the number of registered devices and the last sign-in
+type UserSummaryProjection struct {
+	summaries repositories.UserSummaryRepository
+}
+
+// NewUserSummaryProjection creates a new user summary projection
+func NewUserSummaryProjection(summaries repositories.UserSummaryRepository) *UserSummaryProjection {
+	return &UserSummaryProjection{
+		summaries: summaries,
+	}
+}
+
+// Name identifies the projection
+func (p *UserSummaryProjection) Name() string {
+	return UserSummaryProjectionName
+}
+
+// Reset removes every summary
+func (p *UserSummaryProjection) Reset(ctx context.Context) error {
+	return p.summaries.Clear(ctx)
+}
+
+// Handle applies an event to the summary of the user it concerns. Events for
+// users without a summary are ignored, so a missed creation cannot block later
+// events; a rebuild restores the summary.
+func (p *UserSummaryProjection) Handle(ctx context.Context, event events.Event) error {
+	switch e := event.(type) {
+	case events.UserCreated:
+		existing, err := p.summaries.GetByID(ctx, e.AggregateID())
+		if err != nil || existing != nil {
+			return err
+		}
+		return p.summaries.Save(ctx, &entities.UserSummary{
+			ID:             e.AggregateID(),
+			Name:           e.Name,
+			Email:          e.Email,
+			Roles:          []string{},
+			DeviceIDs:      []types.ID{},
+			CreatedAt:      e.OccurredAt(),
+			UpdatedAt:      e.OccurredAt(),
+			Version:        e.AggregateVersion(),
+			ProfileVersion: e.AggregateVersion(),
+		})
+
+	case events.UserDeleted:
+		return p.updateUser(ctx, e, func(summary *entities.UserSummary) {
+			deletedAt := e.OccurredAt()
+			summary.DeletedAt = &deletedAt
+			summary.UpdatedAt = deletedAt
+		})
+
+	case events.UserRestored:
+		return p.updateUser(ctx, e, func(summary *entities.UserSummary) {
+			summary.DeletedAt = nil
+			summary.UpdatedAt = e.OccurredAt()
+		})
+
+	case events.UserPurged:
+		return p.summaries.Delete(ctx, e.AggregateID())
+
+	case events.UserRenamed:
+		return p.updateUser(ctx, e, func(summary *entities.UserSummary) {
+			summary.Name = e.NewName
+			summary.UpdatedAt = e.OccurredAt()
+		})
+
+	case events.UserEmailChanged:
+		return p.updateUser(ctx, e, func(summary *entities.UserSummary) {
+			summary.Email = e.NewEmail
+			summary.UpdatedAt = e.OccurredAt()
+		})
+
+	case events.UserRoleAssigned:
+		return p.updateUser(ctx, e, func(summary *entities.UserSummary) {
+			for _, role := range summary.Roles {
+				if role == e.Role {
+					return
+				}
+			}
+			summary.Roles = append(summary.Roles, e.Role)
+			summary.UpdatedAt = e.OccurredAt()
+		})
+
+	case events.UserRoleRevoked:
+		return p.updateUser(ctx, e, func(summary *entities.UserSummary) {
+			roles := make([]string, 0, len(summary.Roles))
+			for _, role := range summary.Roles {
+				if role != e.Role {
+					roles = append(roles, role)
+				}
+			}
+			summary.Roles = roles
+			summary.UpdatedAt = e.OccurredAt()
+		})
+
+	case events.UserTwoFactorRequirementChanged:
+		return p.updateUser(ctx, e, func(summary *entities.UserSummary) {
+			summary.TwoFactorRequired = e.Required
+			summary.UpdatedAt = e.OccurredAt()
+		})
+
+	case events.UserLoggedIn:
+		return p.updateUser(ctx, e, func(summary *entities.UserSummary) {
+			loggedInAt := e.OccurredAt()
+			if summary.LastLoginAt == nil || loggedInAt.After(*summary.LastLoginAt) {
+				summary.LastLoginAt = &loggedInAt
+			}
+		})
+
+	case events.DeviceRegistered:
+		return p.update(ctx, e.UserID, func(summary *entities.UserSummary) {
+			summary.AddDevice(e.AggregateID())
+		})
+
+	case events.DeviceRevoked:
+		return p.update(ctx, e.UserID, func(summary *entities.UserSummary) {
+			summary.RemoveDevice(e.AggregateID())
+		})
+	}
+	return nil
+}
+
+// updateUser applies a user event to the user's summary. Events the summary
+// already reflects are skipped, so redelivered or out-of-date events cannot
+// undo newer changes.
+func (p *UserSummaryProjection) updateUser(ctx context.Context, event events.Event, change func(summary *entities.UserSummary)) error {
+	return p.update(ctx, event.AggregateID(), func(summary *entities.UserSummary) {
+		if event.AggregateVersion() <= summary.Version {
+			return
+		}
+		change(summary)
+		summary.Version = event.AggregateVersion()
+		if !events.IsSessionEvent(event) {
+			summary.ProfileVersion = summary.Version
+		}
+	})
+}
+
+// update loads a user's summary, changes it and saves it
+func (p *UserSummaryProjection) update(ctx context.Context, userID types.ID, change func(summary *entities.UserSummary)) error {
+	summary, err := p.summaries.GetByID(ctx, userID)
+	if err != nil || summary == nil {
+		return err
+	}
+	change(summary)
+	return p.summaries.Save(ctx, summary)
+}
@@ -0,0 +1,85 @@
+package projection
+
+import (
+	"context"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/events"
+	"shadow-id/internal/domain/repositories"
+	"shadow-id/pkg/types"
+)
+
+// UserTimelineProjectionName identifies the user timeline projection
+const UserTimelineProjectionName = "user_timeline"
+
+// UserTimelineProjection maintains the user timeline read model: the
+// sign-ins, sign-outs, device registrations and heartbeats of each user,
+// together with the device they came from
+type UserTimelineProjection struct {
+	timelines repositories.UserTimelineRepository
+}
+
+// NewUserTimelineProjection creates a new user timeline projection
+func NewUserTimelineProjection(timelines repositories.UserTimelineRepository) *UserTimelineProjection {
+	return &UserTimelineProjection{
+		timelines: timelines,
+	}
+}
+
+// Name identifies the projection
+func (p *UserTimelineProjection) Name() string {
+	return UserTimelineProjectionName
+}
+
+// Reset removes every timeline entry
+func (p *UserTimelineProjection) Reset(ctx context.Context) error {
+	return p.timelines.Clear(ctx)
+}
+
+// Handle turns an event into a timeline entry. Entries are keyed by event ID,
+// so redelivered events are stored once.
+func (p *UserTimelineProjection) Handle(ctx context.Context, event events.Event) error {
+	switch e := event.(type) {
+	case events.UserLoggedIn:
+		return p.append(ctx, e, e.AggregateID(), e.DeviceID, entities.TimelineLogin, map[string]string{
+			"method": e.Method,
+		})
+
+	case events.UserLoggedOut:
+		return p.append(ctx, e, e.AggregateID(), e.DeviceID, entities.TimelineLogout, nil)
+
+	case events.DeviceRegistered:
+		return p.append(ctx, e, e.UserID, e.AggregateID(), entities.TimelineDeviceRegistered, map[string]string{
+			"name":     e.Name,
+			"platform": e.Platform,
+		})
+
+	case events.DeviceHeartbeat:
+		return p.append(ctx, e, e.UserID, e.AggregateID(), entities.TimelineHeartbeat, map[string]string{
+			"app_version": e.AppVersion,
+			"ip_address":  e.IPAddress,
+		})
+
+	case events.UserPurged:
+		return p.timelines.DeleteByUserID(ctx, e.AggregateID())
+	}
+	return nil
+}
+
+// append stores the timeline entry an event was turned into, leaving out
+// empty details
+func (p *UserTimelineProjection) append(ctx context.Context, event events.Event, userID, deviceID types.ID, action entities.TimelineAction, details map[string]string) error {
+	for key, value := range details {
+		if value == "" {
+			delete(details, key)
+		}
+	}
+	return p.timelines.Append(ctx, &entities.TimelineEntry{
+		ID:         event.EventID(),
+		UserID:     userID,
+		DeviceID:   deviceID,
+		Action:     action,
+		Details:    details,
+		OccurredAt: event.OccurredAt(),
+	})
+}
@@ -0,0 +1,115 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// TokenBucketConfig holds token bucket parameters
+type TokenBucketConfig struct {
+	// Capacity is the maximum burst of attempts allowed
+	Capacity int
+	// RefillInterval is how often one token is added back to a bucket
+	RefillInterval time.Duration
+}
+
+// TokenBucket implements an in-memory token-bucket rate limiter with one bucket per key
+type TokenBucket struct {
+	config  TokenBucketConfig
+	buckets map[string]*bucket
+	mutex   sync.Mutex
+	now     func() time.Time
+
+	// lastPrune is when buckets were last pruned
+	lastPrune time.Time
+}
+
+// bucket holds the token count for a single key
+type bucket struct {
+	tokens     float64
+	lastRefill time.Time
+}
+
+// NewTokenBucket creates a new token-bucket rate limiter
+func NewTokenBucket(config TokenBucketConfig) *TokenBucket {
+	if config.Capacity <= 0 {
+		config.Capacity = 1
+	}
+	if config.RefillInterval <= 0 {
+		config.RefillInterval = time.Second
+	}
+	return &TokenBucket{
+		config:  config,
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+// Allow consumes one token for the key and reports whether it was available,
+// along with how long until the next token when it was not. Buckets that have
+// refilled are evicted along the way, at most once per refill period.
+func (l *TokenBucket) Allow(ctx context.Context, key string) (bool, time.Duration) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := l.now()
+	if now.Sub(l.lastPrune) >= l.fullRefill() {
+		l.prune(now)
+	}
+
+	b, exists := l.buckets[key]
+	if !exists {
+		b = &bucket{tokens: float64(l.config.Capacity), lastRefill: now}
+		l.buckets[key] = b
+	}
+
+	// Refill proportionally to elapsed time
+	elapsed := now.Sub(b.lastRefill)
+	b.tokens += float64(elapsed) / float64(l.config.RefillInterval)
+	if b.tokens > float64(l.config.Capacity) {
+		b.tokens = float64(l.config.Capacity)
+	}
+	b.lastRefill = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+
+	wait := time.Duration((1 - b.tokens) * float64(l.config.RefillInterval))
+	return false, wait
+}
+
+// Reset clears the bucket for the key
+func (l *TokenBucket) Reset(ctx context.Context, key string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	delete(l.buckets, key)
+}
+
+// Prune drops buckets that have refilled completely, bounding memory use
+func (l *TokenBucket) Prune() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.prune(l.now())
+}
+
+// prune drops the buckets that have refilled by now. The caller must hold the
+// mutex.
+func (l *TokenBucket) prune(now time.Time) {
+	full := l.fullRefill()
+	for key, b := range l.buckets {
+		if now.Sub(b.lastRefill) >= full {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastPrune = now
+}
+
+// fullRefill returns how long an empty bucket takes to refill
+func (l *TokenBucket) fullRefill() time.Duration {
+	return time.Duration(l.config.Capacity) * l.config.RefillInterval
+}
@@ -0,0 +1,56 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestAllowEvictsRefilledBuckets(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	limiter := NewTokenBucket(TokenBucketConfig{Capacity: 2, RefillInterval: time.Second})
+	limiter.now = func() time.Time { return now }
+
+	for i := 0; i < 100; i++ {
+		limiter.Allow(ctx, fmt.Sprintf("client-%d", i))
+	}
+	if got := len(limiter.buckets); got != 100 {
+		t.Fatalf("buckets = %d, want 100", got)
+	}
+
+	// Once they have refilled, the next call drops the idle buckets
+	now = now.Add(2 * time.Second)
+	limiter.Allow(ctx, "client-0")
+	if got := len(limiter.buckets); got != 1 {
+		t.Errorf("buckets = %d, want only the one in use", got)
+	}
+}
+
+func TestAllowLimitsPerKey(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	limiter := NewTokenBucket(TokenBucketConfig{Capacity: 2, RefillInterval: time.Second})
+	limiter.now = func() time.Time { return now }
+
+	tests := []struct {
+		name  string
+		key   string
+		after time.Duration
+		want  bool
+	}{
+		{"first of the burst", "a", 0, true},
+		{"second of the burst", "a", 0, true},
+		{"burst exhausted", "a", 0, false},
+		{"other keys are independent", "b", 0, true},
+		{"a token refills", "a", time.Second, true},
+		{"only one token refilled", "a", 0, false},
+	}
+	for _, tt := range tests {
+		now = now.Add(tt.after)
+		if allowed, _ := limiter.Allow(ctx, tt.key); allowed != tt.want {
+			t.Errorf("%s: Allow(%q) = %v, want %v", tt.name, tt.key, allowed, tt.want)
+		}
+	}
+}
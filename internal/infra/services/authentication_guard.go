@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/repositories"
+	"shadow-id/internal/domain/services"
+	"shadow-id/pkg/errors"
+	"shadow-id/pkg/types"
+)
+
+// AuthenticationGuard implements brute-force protection using per-subject
+// throttles for backoff and lockouts, and a rate limiter for raw attempt volume
+type AuthenticationGuard struct {
+	throttleRepo repositories.LoginThrottleRepository
+	rateLimiter  services.RateLimiter
+	policy       entities.LockoutPolicy
+}
+
+// NewAuthenticationGuard creates a new authentication guard
+func NewAuthenticationGuard(
+	throttleRepo repositories.LoginThrottleRepository,
+	rateLimiter services.RateLimiter,
+	policy entities.LockoutPolicy,
+) *AuthenticationGuard {
+	return &AuthenticationGuard{
+		throttleRepo: throttleRepo,
+		rateLimiter:  rateLimiter,
+		policy:       policy,
+	}
+}
+
+// Check returns an error if an attempt is currently not allowed
+func (g *AuthenticationGuard) Check(ctx context.Context, userID, deviceID types.ID) error {
+	now := time.Now()
+	for _, subject := range guardSubjects(userID, deviceID) {
+		if allowed, retryAfter := g.rateLimiter.Allow(ctx, subject.scope+":"+subject.id.String()); !allowed {
+			return rateLimitedError(entities.ErrTooManyAttempts, retryAfter)
+		}
+
+		throttle, err := g.throttleRepo.Get(ctx, subject.scope, subject.id)
+		if err != nil {
+			return err
+		}
+		if throttle == nil {
+			continue
+		}
+		if retryAfter, err := throttle.Check(now); err != nil {
+			return rateLimitedError(err, retryAfter)
+		}
+	}
+	return nil
+}
+
+// RecordFailure registers a failed attempt against the account and device
+func (g *AuthenticationGuard) RecordFailure(ctx context.Context, userID, deviceID types.ID) error {
+	now := time.Now()
+	for _, subject := range guardSubjects(userID, deviceID) {
+		throttle, err := g.throttleRepo.Get(ctx, subject.scope, subject.id)
+		if err != nil {
+			return err
+		}
+		if throttle == nil {
+			throttle = entities.NewLoginThrottle(subject.scope, subject.id)
+		}
+
+		throttle.RecordFailure(now, g.policy)
+		if err := g.throttleRepo.Save(ctx, throttle); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RecordSuccess clears failures for the account and device after a successful attempt
+func (g *AuthenticationGuard) RecordSuccess(ctx context.Context, userID, deviceID types.ID) error {
+	for _, subject := range guardSubjects(userID, deviceID) {
+		if err := g.throttleRepo.Delete(ctx, subject.scope, subject.id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// guardSubject is a scoped identifier attempts are tracked against
+type guardSubject struct {
+	scope string
+	id    types.ID
+}
+
+// guardSubjects returns the account subject and, when known, the device subject
+func guardSubjects(userID, deviceID types.ID) []guardSubject {
+	subjects := make([]guardSubject, 0, 2)
+	if !userID.IsEmpty() {
+		subjects = append(subjects, guardSubject{scope: entities.ThrottleScopeAccount, id: userID})
+	}
+	if !deviceID.IsEmpty() {
+		subjects = append(subjects, guardSubject{scope: entities.ThrottleScopeDevice, id: deviceID})
+	}
+	return subjects
+}
+
+// rateLimitedError builds a rate limited error carrying the retry delay
+func rateLimitedError(cause error, retryAfter time.Duration) *errors.AppError {
+	return errors.WrapWithType(cause, errors.ErrorTypeRateLimited, "authentication attempt rejected").
+		WithDetail("retry_after_seconds", int(math.Ceil(retryAfter.Seconds())))
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+
+	"shadow-id/internal/domain/entities"
+)
+
+// deviceNonceSize is the length of challenge nonces in bytes
+const deviceNonceSize = 32
+
+// DeviceKeyService implements device keys with Ed25519
+type DeviceKeyService struct{}
+
+// NewDeviceKeyService creates a new device key service
+func NewDeviceKeyService() *DeviceKeyService {
+	return &DeviceKeyService{}
+}
+
+// GenerateKeyPair creates a new Ed25519 key pair. The private key is returned
+// as its 32-byte seed.
+func (s *DeviceKeyService) GenerateKeyPair() ([]byte, []byte, error) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	return publicKey, privateKey.Seed(), nil
+}
+
+// NewNonce generates a random challenge nonce
+func (s *DeviceKeyService) NewNonce() ([]byte, error) {
+	nonce := make([]byte, deviceNonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return nonce, nil
+}
+
+// Sign signs a message with the private key seed
+func (s *DeviceKeyService) Sign(privateKey, message []byte) ([]byte, error) {
+	if len(privateKey) != ed25519.SeedSize {
+		return nil, fmt.Errorf("device private key must be %d bytes, got %d", ed25519.SeedSize, len(privateKey))
+	}
+	return ed25519.Sign(ed25519.NewKeyFromSeed(privateKey), message), nil
+}
+
+// Verify checks an Ed25519 signature over a message
+func (s *DeviceKeyService) Verify(publicKey, message, signature []byte) error {
+	if len(publicKey) != ed25519.PublicKeySize || !ed25519.Verify(publicKey, message, signature) {
+		return entities.ErrInvalidDeviceSignature
+	}
+	return nil
+}
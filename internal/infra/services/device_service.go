@@ -1 +1,105 @@
 package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/infra/fingerprint"
+	"shadow-id/pkg/errors"
+)
+
+// DeviceService fingerprints the local machine from the attributes its
+// sources read. Every component is an HMAC-SHA256 of the attribute's values
+// keyed with the installation's salt, so the raw values never leave the
+// service.
+type DeviceService struct {
+	sources []fingerprint.Source
+	salt    []byte
+}
+
+// NewDeviceService creates a new device service
+func NewDeviceService(salt string, sources []fingerprint.Source) *DeviceService {
+	return &DeviceService{
+		sources: sources,
+		salt:    []byte(salt),
+	}
+}
+
+// CurrentFingerprint collects the local machine's attributes and returns
+// their fingerprint. Sources that fail or read nothing are left out of it.
+func (s *DeviceService) CurrentFingerprint(ctx context.Context) (*entities.DeviceFingerprint, error) {
+	components := make(map[string]string, len(s.sources))
+	for _, source := range s.sources {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		values, err := source.Collect(ctx)
+		if err != nil {
+			continue
+		}
+		if value := normalizeAttribute(values); value != "" {
+			components[source.Name()] = s.hash(source.Name(), value)
+		}
+	}
+	if len(components) == 0 {
+		return nil, errors.WrapWithType(entities.ErrFingerprintUnavailable, errors.ErrorTypeInternal, "device fingerprinting failed")
+	}
+
+	hostname, _ := os.Hostname()
+	return &entities.DeviceFingerprint{
+		Hash:        s.combine(components),
+		Components:  components,
+		Platform:    runtime.GOOS,
+		Hostname:    hostname,
+		CollectedAt: time.Now(),
+	}, nil
+}
+
+// hash returns the salted hash of a component's value. The component name is
+// part of the message so equal values of different components hash apart.
+func (s *DeviceService) hash(name, value string) string {
+	mac := hmac.New(sha256.New, s.salt)
+	mac.Write([]byte(name))
+	mac.Write([]byte{0})
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// combine returns the salted hash of every component in name order
+func (s *DeviceService) combine(components map[string]string) string {
+	names := make([]string, 0, len(components))
+	for name := range components {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	mac := hmac.New(sha256.New, s.salt)
+	for _, name := range names {
+		mac.Write([]byte(name + "=" + components[name] + "\n"))
+	}
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// normalizeAttribute joins an attribute's values into a single string that
+// does not depend on their order, case or surrounding whitespace
+func normalizeAttribute(values []string) string {
+	normalized := make([]string, 0, len(values))
+	seen := make(map[string]bool, len(values))
+	for _, value := range values {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if value != "" && !seen[value] {
+			seen[value] = true
+			normalized = append(normalized, value)
+		}
+	}
+	sort.Strings(normalized)
+	return strings.Join(normalized, "\n")
+}
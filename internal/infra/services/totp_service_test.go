@@ -0,0 +1,113 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/pkg/types"
+)
+
+// rfc6238Secret is the SHA-1 seed of RFC 6238 Appendix B, "12345678901234567890",
+// in base32
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestValidateAcceptsRFC6238Vectors(t *testing.T) {
+	service := NewTOTPService(TOTPConfig{Digits: 8, Period: 30})
+
+	tests := []struct {
+		unix int64
+		code string
+		step int64
+	}{
+		{59, "94287082", 0x1},
+		{1111111109, "07081804", 0x23523EC},
+		{1111111111, "14050471", 0x23523ED},
+		{1234567890, "89005924", 0x273EF07},
+		{2000000000, "69279037", 0x3F940AA},
+		{20000000000, "65353130", 0x27BC86AA},
+	}
+	for _, tt := range tests {
+		step, ok := service.Validate(rfc6238Secret, tt.code, time.Unix(tt.unix, 0))
+		if !ok || step != tt.step {
+			t.Errorf("Validate(%q) at %d = %d, %v, want step %d", tt.code, tt.unix, step, ok, tt.step)
+		}
+	}
+}
+
+func TestValidateHonoursSkewWindow(t *testing.T) {
+	// 94287082 is the code of step 1, which covers 30s to 59s
+	tests := []struct {
+		name string
+		skew int
+		at   int64
+		want bool
+	}{
+		{"within the step", 0, 45, true},
+		{"one step later without skew", 0, 75, false},
+		{"one step later with skew", 1, 75, true},
+		{"one step earlier with skew", 1, 15, true},
+		{"two steps later with skew of one", 1, 105, false},
+		{"two steps later with skew of two", 2, 105, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewTOTPService(TOTPConfig{Digits: 8, Period: 30, Skew: tt.skew})
+			step, ok := service.Validate(rfc6238Secret, "94287082", time.Unix(tt.at, 0))
+			if ok != tt.want {
+				t.Fatalf("Validate() = %v, want %v", ok, tt.want)
+			}
+			if ok && step != 1 {
+				t.Errorf("Validate() step = %d, want the code's own step 1", step)
+			}
+		})
+	}
+}
+
+func TestValidateRejectsMalformedCodes(t *testing.T) {
+	service := NewTOTPService(TOTPConfig{Digits: 8, Period: 30})
+	at := time.Unix(59, 0)
+
+	tests := []struct {
+		name   string
+		secret string
+		code   string
+		want   bool
+	}{
+		{"spaces are ignored", rfc6238Secret, " 9428 7082 ", true},
+		{"lowercase secret", "gezdgnbvgy3tqojqgezdgnbvgy3tqojq", "94287082", true},
+		{"wrong code", rfc6238Secret, "94287083", false},
+		{"too short", rfc6238Secret, "9428708", false},
+		{"invalid secret", "not base32!", "94287082", false},
+	}
+	for _, tt := range tests {
+		if _, ok := service.Validate(tt.secret, tt.code, at); ok != tt.want {
+			t.Errorf("%s: Validate() = %v, want %v", tt.name, ok, tt.want)
+		}
+	}
+}
+
+func TestReplayedCodeIsRejected(t *testing.T) {
+	service := NewTOTPService(TOTPConfig{Digits: 8, Period: 30, Skew: 1})
+	twoFactor := entities.NewTwoFactor(types.NewID(), rfc6238Secret, nil)
+
+	// The step 1 code is accepted once, even again within the skew window
+	for i, at := range []int64{45, 75} {
+		step, ok := service.Validate(rfc6238Secret, "94287082", time.Unix(at, 0))
+		if !ok {
+			t.Fatalf("Validate() at %d = false", at)
+		}
+		err := twoFactor.UseStep(step)
+		if i == 0 && err != nil {
+			t.Fatalf("UseStep() error = %v", err)
+		}
+		if i == 1 && err != entities.ErrTwoFactorCodeReused {
+			t.Fatalf("UseStep() of a replayed code error = %v, want %v", err, entities.ErrTwoFactorCodeReused)
+		}
+	}
+
+	// Codes of earlier steps are rejected too once a later one was used
+	if err := twoFactor.UseStep(0); err != entities.ErrTwoFactorCodeReused {
+		t.Errorf("UseStep() of an earlier step error = %v, want %v", err, entities.ErrTwoFactorCodeReused)
+	}
+}
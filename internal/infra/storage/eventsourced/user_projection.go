@@ -0,0 +1,74 @@
+package eventsourced
+
+import (
+	"context"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/events"
+	"shadow-id/internal/domain/repositories"
+	"shadow-id/pkg/types"
+)
+
+// ReadModel is a state-based user store the projection can overwrite. Save
+// and Remove skip the version check, since the event stream already enforced it.
+type ReadModel interface {
+	repositories.UserRepository
+	Save(ctx context.Context, user *entities.User) error
+	Remove(ctx context.Context, id types.ID) error
+}
+
+// UserProjection keeps a state-based user read model in sync with the user
+// event streams
+type UserProjection struct {
+	readModel ReadModel
+}
+
+// NewUserProjection creates a new user projection writing to the read model
+func NewUserProjection(readModel ReadModel) *UserProjection {
+	return &UserProjection{
+		readModel: readModel,
+	}
+}
+
+// Apply updates the read model with events in the order they were appended
+func (p *UserProjection) Apply(ctx context.Context, evts []events.Event) error {
+	for _, event := range evts {
+		if err := p.apply(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// apply updates the read model with a single event
+func (p *UserProjection) apply(ctx context.Context, event events.Event) error {
+	switch event.(type) {
+	case events.UserCreated:
+		user := &entities.User{}
+		user.Apply(event)
+		return p.readModel.Create(ctx, user)
+
+	case events.UserPurged:
+		return p.readModel.Remove(ctx, event.AggregateID())
+
+	default:
+		user, err := p.find(ctx, event.AggregateID())
+		if err != nil {
+			return err
+		}
+		if user == nil {
+			return entities.ErrUserNotFound
+		}
+		user.Apply(event)
+		return p.readModel.Save(ctx, user)
+	}
+}
+
+// find retrieves a user from the read model whether or not it is deleted
+func (p *UserProjection) find(ctx context.Context, id types.ID) (*entities.User, error) {
+	user, err := p.readModel.GetByID(ctx, id)
+	if err != nil || user != nil {
+		return user, err
+	}
+	return p.readModel.GetDeleted(ctx, id)
+}
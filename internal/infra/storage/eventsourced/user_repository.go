@@ -0,0 +1,230 @@
+package eventsourced
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/events"
+	"shadow-id/internal/domain/repositories"
+	"shadow-id/pkg/errors"
+	"shadow-id/pkg/types"
+)
+
+// UserRepository implements the user repository interface on top of an event
+// store. Each user is a stream of the events it recorded; GetByID rebuilds the
+// user from its latest snapshot and the events after it. Lookups by email,
+// listing and counting are served by a state-based read model that a
+// projection updates after every append.
+type UserRepository struct {
+	store         repositories.EventStore
+	readModel     ReadModel
+	projection    *UserProjection
+	snapshotEvery int64
+}
+
+// NewUserRepository creates a new event-sourced user repository. A snapshot
+// is taken every snapshotEvery events; zero disables snapshots.
+func NewUserRepository(store repositories.EventStore, readModel ReadModel, snapshotEvery int) *UserRepository {
+	return &UserRepository{
+		store:         store,
+		readModel:     readModel,
+		projection:    NewUserProjection(readModel),
+		snapshotEvery: int64(snapshotEvery),
+	}
+}
+
+// Create starts the stream of a new user
+func (r *UserRepository) Create(ctx context.Context, user *entities.User) error {
+	if err := r.append(ctx, user.ID, 0, user.PullEvents()); err != nil {
+		if errors.IsConflictError(err) {
+			return entities.ErrUserAlreadyExists
+		}
+		return err
+	}
+	return nil
+}
+
+// GetByID rebuilds a user from its event stream
+func (r *UserRepository) GetByID(ctx context.Context, id types.ID) (*entities.User, error) {
+	user, _, err := r.load(ctx, id)
+	if err != nil || user == nil || user.IsDeleted() {
+		return nil, err
+	}
+	return user, nil
+}
+
+// GetDeleted rebuilds a soft-deleted user from its event stream
+func (r *UserRepository) GetDeleted(ctx context.Context, id types.ID) (*entities.User, error) {
+	user, _, err := r.load(ctx, id)
+	if err != nil || user == nil || !user.IsDeleted() {
+		return nil, err
+	}
+	return user, nil
+}
+
+// ListDeleted retrieves users soft-deleted before the given time from the read model
+func (r *UserRepository) ListDeleted(ctx context.Context, deletedBefore time.Time, limit int) ([]*entities.User, error) {
+	return r.readModel.ListDeleted(ctx, deletedBefore, limit)
+}
+
+// GetByEmail retrieves a user by email from the read model
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*entities.User, error) {
+	return r.readModel.GetByEmail(ctx, email)
+}
+
+// Update appends the events the user recorded since it was loaded. The
+// stream must still be at the version the user was loaded at.
+func (r *UserRepository) Update(ctx context.Context, user *entities.User) error {
+	if err := r.ensureExists(ctx, user.ID); err != nil {
+		return err
+	}
+	return r.append(ctx, user.ID, loadedVersion(user), user.PullEvents())
+}
+
+// Delete appends the user's soft deletion to its stream
+func (r *UserRepository) Delete(ctx context.Context, user *entities.User) error {
+	if !user.IsDeleted() {
+		return entities.ErrUserNotDeleted
+	}
+	return r.Update(ctx, user)
+}
+
+// Purge appends the user's purge to its stream, so it reaches the outbox,
+// and then removes the stream
+func (r *UserRepository) Purge(ctx context.Context, user *entities.User) error {
+	stored, err := r.GetDeleted(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	if stored == nil {
+		return entities.ErrUserNotDeleted
+	}
+	if err := r.append(ctx, user.ID, loadedVersion(user), user.PullEvents()); err != nil {
+		return err
+	}
+	if err := r.store.DeleteStream(ctx, user.ID); err != nil {
+		return errors.Wrap(err, "failed to delete user stream")
+	}
+	return nil
+}
+
+// List retrieves users with pagination from the read model
+func (r *UserRepository) List(ctx context.Context, limit, offset int) ([]*entities.User, error) {
+	return r.readModel.List(ctx, limit, offset)
+}
+
+// Count returns the total number of users from the read model
+func (r *UserRepository) Count(ctx context.Context) (int64, error) {
+	return r.readModel.Count(ctx)
+}
+
+// append stores events in the user's stream, projects them into the read
+// model and takes a snapshot when the stream crosses a snapshot boundary
+func (r *UserRepository) append(ctx context.Context, id types.ID, expectedVersion int64, evts []events.Event) error {
+	if len(evts) == 0 {
+		return nil
+	}
+
+	version, err := r.store.Append(ctx, id, expectedVersion, evts)
+	if err != nil {
+		return errors.Wrap(err, "failed to append user events")
+	}
+
+	if err := r.projection.Apply(ctx, evts); err != nil {
+		return errors.Wrap(err, "failed to update user read model")
+	}
+
+	previousVersion := version - int64(len(evts))
+	if r.snapshotEvery > 0 && version/r.snapshotEvery > previousVersion/r.snapshotEvery {
+		if err := r.snapshot(ctx, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// snapshot stores the user's current state
+func (r *UserRepository) snapshot(ctx context.Context, id types.ID) error {
+	user, version, err := r.load(ctx, id)
+	if err != nil || user == nil {
+		return err
+	}
+
+	state, err := json.Marshal(user)
+	if err != nil {
+		return errors.Wrap(err, "failed to serialize user snapshot")
+	}
+	if err := r.store.SaveSnapshot(ctx, &entities.Snapshot{
+		StreamID:  id,
+		Version:   version,
+		State:     state,
+		CreatedAt: time.Now(),
+	}); err != nil {
+		return errors.Wrap(err, "failed to save user snapshot")
+	}
+	return nil
+}
+
+// load rebuilds a user, soft-deleted or not, and returns the stream version
+// it reflects. A user whose stream does not exist or ends in its purge is
+// returned as nil.
+func (r *UserRepository) load(ctx context.Context, id types.ID) (*entities.User, int64, error) {
+	user := &entities.User{}
+	var version int64
+
+	snapshot, err := r.store.LoadSnapshot(ctx, id)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "failed to load user snapshot")
+	}
+	if snapshot != nil {
+		if err := json.Unmarshal(snapshot.State, user); err != nil {
+			return nil, 0, errors.Wrap(err, "failed to decode user snapshot")
+		}
+		version = snapshot.Version
+	}
+
+	stored, err := r.store.Load(ctx, id, version)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "failed to load user events")
+	}
+	purged := false
+	for _, storedEvent := range stored {
+		event, err := storedEvent.Event()
+		if err != nil {
+			return nil, 0, errors.Wrap(err, "failed to decode user event").
+				WithDetail("version", storedEvent.Version)
+		}
+		if _, ok := event.(events.UserPurged); ok {
+			purged = true
+		}
+		user.Apply(event)
+		version = storedEvent.Version
+	}
+
+	if version == 0 || purged {
+		return nil, version, nil
+	}
+	user.Version = version
+	return user, version, nil
+}
+
+// loadedVersion returns the stream version the user was loaded at, before the
+// events it recorded since
+func loadedVersion(user *entities.User) int64 {
+	return user.Version - int64(user.PendingCount())
+}
+
+// ensureExists fails with ErrUserNotFound if the user has no live stream;
+// soft-deleted users still have one
+func (r *UserRepository) ensureExists(ctx context.Context, id types.ID) error {
+	user, _, err := r.load(ctx, id)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return entities.ErrUserNotFound
+	}
+	return nil
+}
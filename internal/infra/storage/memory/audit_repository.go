@@ -0,0 +1,112 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/pkg/types"
+)
+
+// AuditRepository implements the audit repository interface using in-memory storage
+type AuditRepository struct {
+	entries []*entities.AuditEntry
+	mutex   sync.RWMutex
+	participant
+}
+
+// NewAuditRepository creates a new in-memory audit repository
+func NewAuditRepository() *AuditRepository {
+	return &AuditRepository{
+		entries: make([]*entities.AuditEntry, 0),
+	}
+}
+
+// begin captures the length of the log before a transaction appends to it
+func (r *AuditRepository) begin() (commit, rollback func()) {
+	r.mutex.RLock()
+	length := len(r.entries)
+	r.mutex.RUnlock()
+
+	return noCommit, func() {
+		r.mutex.Lock()
+		defer r.mutex.Unlock()
+		r.entries = r.entries[:length]
+	}
+}
+
+// Append chains the entry after the latest one and stores it
+func (r *AuditRepository) Append(ctx context.Context, entry *entities.AuditEntry) error {
+	defer r.enter(ctx, r)()
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var previous *entities.AuditEntry
+	if len(r.entries) > 0 {
+		previous = r.entries[len(r.entries)-1]
+	}
+	entry.Chain(previous)
+
+	r.entries = append(r.entries, copyAuditEntry(entry))
+	return nil
+}
+
+// List retrieves entries in sequence order with pagination
+func (r *AuditRepository) List(ctx context.Context, limit, offset int) ([]*entities.AuditEntry, error) {
+	return r.filter(func(*entities.AuditEntry) bool { return true }, limit, offset), nil
+}
+
+// ListByTarget retrieves the entries for commands that acted on an entity, in sequence order
+func (r *AuditRepository) ListByTarget(ctx context.Context, targetID types.ID, limit, offset int) ([]*entities.AuditEntry, error) {
+	return r.filter(func(entry *entities.AuditEntry) bool {
+		return entry.Target.ID == targetID
+	}, limit, offset), nil
+}
+
+// ListByActor retrieves the entries for commands a user issued, in sequence order
+func (r *AuditRepository) ListByActor(ctx context.Context, actorID types.ID, limit, offset int) ([]*entities.AuditEntry, error) {
+	return r.filter(func(entry *entities.AuditEntry) bool {
+		return entry.ActorID == actorID
+	}, limit, offset), nil
+}
+
+// Count returns the total number of entries
+func (r *AuditRepository) Count(ctx context.Context) (int64, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return int64(len(r.entries)), nil
+}
+
+// filter returns copies of a page of the entries that match
+func (r *AuditRepository) filter(match func(*entities.AuditEntry) bool, limit, offset int) []*entities.AuditEntry {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	result := make([]*entities.AuditEntry, 0)
+	skipped := 0
+	for _, entry := range r.entries {
+		if !match(entry) {
+			continue
+		}
+		if skipped < offset {
+			skipped++
+			continue
+		}
+		if limit > 0 && len(result) >= limit {
+			break
+		}
+		result = append(result, copyAuditEntry(entry))
+	}
+	return result
+}
+
+// copyAuditEntry returns a copy of an entry that shares no slices with it
+func copyAuditEntry(entry *entities.AuditEntry) *entities.AuditEntry {
+	entryCopy := *entry
+	if entry.Changes != nil {
+		entryCopy.Changes = append([]entities.AuditChange(nil), entry.Changes...)
+	}
+	return &entryCopy
+}
@@ -0,0 +1,278 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"maps"
+	"sort"
+	"sync"
+	"time"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/pkg/types"
+)
+
+// DeviceRepository implements the device repository interface using in-memory storage
+type DeviceRepository struct {
+	devices map[types.ID]*entities.Device
+	mutex   sync.RWMutex
+	outbox  *OutboxRepository
+	participant
+}
+
+// NewDeviceRepository creates a new in-memory device repository that stores
+// recorded events in the outbox
+func NewDeviceRepository(outbox *OutboxRepository) *DeviceRepository {
+	return &DeviceRepository{
+		devices: make(map[types.ID]*entities.Device),
+		outbox:  outbox,
+	}
+}
+
+// begin captures the devices before a transaction changes them
+func (r *DeviceRepository) begin() (commit, rollback func()) {
+	r.mutex.RLock()
+	devices := maps.Clone(r.devices)
+	r.mutex.RUnlock()
+
+	return noCommit, func() {
+		r.mutex.Lock()
+		defer r.mutex.Unlock()
+		r.devices = devices
+	}
+}
+
+// Create creates a new device
+func (r *DeviceRepository) Create(ctx context.Context, device *entities.Device) error {
+	defer r.enter(ctx, r, r.outbox)()
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	entries, err := pendingEntries(device)
+	if err != nil {
+		return err
+	}
+
+	deviceCopy := copyDevice(device)
+	r.devices[device.ID] = deviceCopy
+	r.outbox.append(entries)
+	return nil
+}
+
+// GetByID retrieves a device by ID
+func (r *DeviceRepository) GetByID(ctx context.Context, id types.ID) (*entities.Device, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	device, exists := r.devices[id]
+	if !exists {
+		return nil, nil
+	}
+
+	// Return a copy to prevent external modifications
+	deviceCopy := copyDevice(device)
+	return deviceCopy, nil
+}
+
+// ListByUserID retrieves all devices registered to a user, oldest first
+func (r *DeviceRepository) ListByUserID(ctx context.Context, userID types.ID) ([]*entities.Device, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	devices := make([]*entities.Device, 0)
+	for _, device := range r.devices {
+		if device.UserID == userID {
+			deviceCopy := copyDevice(device)
+			devices = append(devices, deviceCopy)
+		}
+	}
+
+	sort.Slice(devices, func(i, j int) bool {
+		return devices[i].CreatedAt.Before(devices[j].CreatedAt)
+	})
+	return devices, nil
+}
+
+// ListByTrustState retrieves a page of devices in a trust state, oldest first
+func (r *DeviceRepository) ListByTrustState(ctx context.Context, state entities.DeviceTrustState, limit, offset int) ([]*entities.Device, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	devices := make([]*entities.Device, 0)
+	for _, device := range r.devices {
+		if device.TrustState == state {
+			devices = append(devices, device)
+		}
+	}
+
+	sort.Slice(devices, func(i, j int) bool {
+		return devices[i].CreatedAt.Before(devices[j].CreatedAt)
+	})
+
+	// Simple pagination
+	start := min(offset, len(devices))
+	end := min(start+limit, len(devices))
+
+	result := make([]*entities.Device, end-start)
+	for i, device := range devices[start:end] {
+		result[i] = copyDevice(device)
+	}
+	return result, nil
+}
+
+// CountByTrustState returns the number of devices in a trust state
+func (r *DeviceRepository) CountByTrustState(ctx context.Context, state entities.DeviceTrustState) (int64, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var count int64
+	for _, device := range r.devices {
+		if device.TrustState == state {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// ListUnseenSince retrieves up to limit devices that are neither revoked nor
+// marked stale and were last seen before a time, longest unseen first
+func (r *DeviceRepository) ListUnseenSince(ctx context.Context, before time.Time, limit int) ([]*entities.Device, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	devices := make([]*entities.Device, 0)
+	for _, device := range r.devices {
+		if !device.IsRevoked() && !device.Stale && device.SeenAt().Before(before) {
+			devices = append(devices, device)
+		}
+	}
+
+	sort.Slice(devices, func(i, j int) bool {
+		return devices[i].SeenAt().Before(devices[j].SeenAt())
+	})
+
+	result := make([]*entities.Device, min(limit, len(devices)))
+	for i := range result {
+		result[i] = copyDevice(devices[i])
+	}
+	return result, nil
+}
+
+// CountByStaleness returns the number of devices that are not revoked and are
+// or are not marked stale
+func (r *DeviceRepository) CountByStaleness(ctx context.Context, stale bool) (int64, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var count int64
+	for _, device := range r.devices {
+		if !device.IsRevoked() && device.Stale == stale {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// Update updates an existing device
+func (r *DeviceRepository) Update(ctx context.Context, device *entities.Device) error {
+	defer r.enter(ctx, r, r.outbox)()
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	stored, exists := r.devices[device.ID]
+	if !exists {
+		return entities.ErrDeviceNotFound
+	}
+	if err := checkVersion(stored.Version, device.Version, device.PendingCount()); err != nil {
+		return err
+	}
+
+	entries, err := pendingEntries(device)
+	if err != nil {
+		return err
+	}
+
+	deviceCopy := copyDevice(device)
+	r.devices[device.ID] = deviceCopy
+	r.outbox.append(entries)
+	return nil
+}
+
+// Delete deletes a device
+func (r *DeviceRepository) Delete(ctx context.Context, device *entities.Device) error {
+	defer r.enter(ctx, r, r.outbox)()
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	stored, exists := r.devices[device.ID]
+	if !exists {
+		return entities.ErrDeviceNotFound
+	}
+	if err := checkVersion(stored.Version, device.Version, device.PendingCount()); err != nil {
+		return err
+	}
+
+	entries, err := pendingEntries(device)
+	if err != nil {
+		return err
+	}
+
+	delete(r.devices, device.ID)
+	r.outbox.append(entries)
+	return nil
+}
+
+// copyDevice returns a copy of a device that shares no key or fingerprint with it
+func copyDevice(device *entities.Device) *entities.Device {
+	deviceCopy := *device
+	deviceCopy.PublicKey = bytes.Clone(device.PublicKey)
+	deviceCopy.Fingerprint = maps.Clone(device.Fingerprint)
+	return &deviceCopy
+}
+
+// DeviceChallengeRepository implements the device challenge repository using in-memory storage
+type DeviceChallengeRepository struct {
+	challenges map[types.ID]*entities.DeviceChallenge
+	mutex      sync.Mutex
+}
+
+// NewDeviceChallengeRepository creates a new in-memory device challenge repository
+func NewDeviceChallengeRepository() *DeviceChallengeRepository {
+	return &DeviceChallengeRepository{
+		challenges: make(map[types.ID]*entities.DeviceChallenge),
+	}
+}
+
+// Create stores a new challenge and drops any that have expired
+func (r *DeviceChallengeRepository) Create(ctx context.Context, challenge *entities.DeviceChallenge) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for id, existing := range r.challenges {
+		if existing.IsExpired() {
+			delete(r.challenges, id)
+		}
+	}
+
+	challengeCopy := *challenge
+	challengeCopy.Nonce = bytes.Clone(challenge.Nonce)
+	r.challenges[challenge.ID] = &challengeCopy
+	return nil
+}
+
+// Take retrieves and removes a challenge so it can only be answered once
+func (r *DeviceChallengeRepository) Take(ctx context.Context, id types.ID) (*entities.DeviceChallenge, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	challenge, exists := r.challenges[id]
+	if !exists {
+		return nil, nil
+	}
+
+	delete(r.challenges, id)
+	return challenge, nil
+}
@@ -0,0 +1,161 @@
+package memory
+
+import (
+	"context"
+	"maps"
+	"sync"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/events"
+	"shadow-id/internal/domain/repositories"
+	"shadow-id/pkg/errors"
+	"shadow-id/pkg/types"
+)
+
+// EventStore implements the event store interface using in-memory storage
+type EventStore struct {
+	streams   map[types.ID][]*entities.StoredEvent
+	snapshots map[types.ID]*entities.Snapshot
+	mutex     sync.RWMutex
+	outbox    *OutboxRepository
+	participant
+}
+
+// NewEventStore creates a new in-memory event store that stores appended
+// events in the outbox
+func NewEventStore(outbox *OutboxRepository) *EventStore {
+	return &EventStore{
+		streams:   make(map[types.ID][]*entities.StoredEvent),
+		snapshots: make(map[types.ID]*entities.Snapshot),
+		outbox:    outbox,
+	}
+}
+
+// begin captures the streams and snapshots before a transaction changes them
+func (s *EventStore) begin() (commit, rollback func()) {
+	s.mutex.RLock()
+	streams := maps.Clone(s.streams)
+	snapshots := maps.Clone(s.snapshots)
+	s.mutex.RUnlock()
+
+	return noCommit, func() {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		s.streams = streams
+		s.snapshots = snapshots
+	}
+}
+
+// Append adds events to the end of a stream and returns the stream's new version
+func (s *EventStore) Append(ctx context.Context, streamID types.ID, expectedVersion int64, evts []events.Event) (int64, error) {
+	defer s.enter(ctx, s, s.outbox)()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	stream := s.streams[streamID]
+	version := int64(len(stream))
+	if expectedVersion != repositories.AnyVersion && expectedVersion != version {
+		return version, errors.WrapWithType(entities.ErrVersionConflict, errors.ErrorTypeConflict, "failed to append events").
+			WithDetail("stream_id", streamID).
+			WithDetail("expected_version", expectedVersion).
+			WithDetail("current_version", version)
+	}
+
+	// Serialize everything before changing the stream so a failure stores nothing
+	stored := make([]*entities.StoredEvent, 0, len(evts))
+	entries := make([]*entities.OutboxEntry, 0, len(evts))
+	for i, event := range evts {
+		if event.AggregateID() != streamID {
+			return version, errors.NewValidationError("event belongs to another stream").
+				WithDetail("stream_id", streamID).
+				WithDetail("event", event.EventName())
+		}
+		storedEvent, err := entities.NewStoredEvent(event, version+int64(i)+1)
+		if err != nil {
+			return version, errors.Wrap(err, "failed to serialize event")
+		}
+		entry, err := entities.NewOutboxEntry(event)
+		if err != nil {
+			return version, errors.Wrap(err, "failed to serialize event")
+		}
+		stored = append(stored, storedEvent)
+		entries = append(entries, entry)
+	}
+
+	s.streams[streamID] = append(stream, stored...)
+	s.outbox.append(entries)
+	return version + int64(len(stored)), nil
+}
+
+// Load retrieves a stream's events after the given version, in order
+func (s *EventStore) Load(ctx context.Context, streamID types.ID, afterVersion int64) ([]*entities.StoredEvent, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	stream := s.streams[streamID]
+	if afterVersion < 0 {
+		afterVersion = 0
+	}
+	if afterVersion >= int64(len(stream)) {
+		return []*entities.StoredEvent{}, nil
+	}
+
+	result := make([]*entities.StoredEvent, 0, int64(len(stream))-afterVersion)
+	for _, storedEvent := range stream[afterVersion:] {
+		result = append(result, copyStoredEvent(storedEvent))
+	}
+	return result, nil
+}
+
+// SaveSnapshot stores a snapshot, replacing any older one for the stream
+func (s *EventStore) SaveSnapshot(ctx context.Context, snapshot *entities.Snapshot) error {
+	defer s.enter(ctx, s)()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if current, exists := s.snapshots[snapshot.StreamID]; exists && current.Version >= snapshot.Version {
+		return nil
+	}
+	s.snapshots[snapshot.StreamID] = copySnapshot(snapshot)
+	return nil
+}
+
+// LoadSnapshot retrieves the latest snapshot of a stream, or nil if there is none
+func (s *EventStore) LoadSnapshot(ctx context.Context, streamID types.ID) (*entities.Snapshot, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	snapshot, exists := s.snapshots[streamID]
+	if !exists {
+		return nil, nil
+	}
+	return copySnapshot(snapshot), nil
+}
+
+// DeleteStream removes a stream and its snapshot
+func (s *EventStore) DeleteStream(ctx context.Context, streamID types.ID) error {
+	defer s.enter(ctx, s)()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.streams, streamID)
+	delete(s.snapshots, streamID)
+	return nil
+}
+
+// copyStoredEvent returns a deep copy of a stored event
+func copyStoredEvent(storedEvent *entities.StoredEvent) *entities.StoredEvent {
+	eventCopy := *storedEvent
+	eventCopy.Payload = append([]byte(nil), storedEvent.Payload...)
+	return &eventCopy
+}
+
+// copySnapshot returns a deep copy of a snapshot
+func copySnapshot(snapshot *entities.Snapshot) *entities.Snapshot {
+	snapshotCopy := *snapshot
+	snapshotCopy.State = append([]byte(nil), snapshot.State...)
+	return &snapshotCopy
+}
@@ -0,0 +1,61 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/pkg/types"
+)
+
+// LoginThrottleRepository implements the login throttle repository using in-memory storage
+type LoginThrottleRepository struct {
+	throttles map[string]*entities.LoginThrottle
+	mutex     sync.RWMutex
+}
+
+// NewLoginThrottleRepository creates a new in-memory login throttle repository
+func NewLoginThrottleRepository() *LoginThrottleRepository {
+	return &LoginThrottleRepository{
+		throttles: make(map[string]*entities.LoginThrottle),
+	}
+}
+
+// Get retrieves the throttle for a subject in a scope
+func (r *LoginThrottleRepository) Get(ctx context.Context, scope string, subjectID types.ID) (*entities.LoginThrottle, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	throttle, exists := r.throttles[throttleKey(scope, subjectID)]
+	if !exists {
+		return nil, nil
+	}
+
+	// Return a copy to prevent external modifications
+	throttleCopy := *throttle
+	return &throttleCopy, nil
+}
+
+// Save creates or replaces a throttle
+func (r *LoginThrottleRepository) Save(ctx context.Context, throttle *entities.LoginThrottle) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	throttleCopy := *throttle
+	r.throttles[throttleKey(throttle.Scope, throttle.SubjectID)] = &throttleCopy
+	return nil
+}
+
+// Delete removes the throttle for a subject in a scope
+func (r *LoginThrottleRepository) Delete(ctx context.Context, scope string, subjectID types.ID) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	delete(r.throttles, throttleKey(scope, subjectID))
+	return nil
+}
+
+// throttleKey builds the map key for a scoped subject
+func throttleKey(scope string, subjectID types.ID) string {
+	return scope + ":" + subjectID.String()
+}
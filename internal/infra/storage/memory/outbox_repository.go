@@ -0,0 +1,172 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/events"
+	"shadow-id/pkg/errors"
+	"shadow-id/pkg/types"
+)
+
+// OutboxRepository implements the outbox repository interface using in-memory storage.
+// Entity repositories sharing it append entries while holding their own lock,
+// so an entry becomes visible together with the change that raised it.
+// Entries appended in a transaction stay hidden until it commits.
+type OutboxRepository struct {
+	entries map[types.ID]*entities.OutboxEntry
+	order   []types.ID
+	mutex   sync.RWMutex
+	participant
+
+	// committed is the number of entries in order visible outside the
+	// running transaction, or -1 if no transaction appended to the outbox
+	committed int
+}
+
+// NewOutboxRepository creates a new in-memory outbox repository
+func NewOutboxRepository() *OutboxRepository {
+	return &OutboxRepository{
+		entries:   make(map[types.ID]*entities.OutboxEntry),
+		committed: -1,
+	}
+}
+
+// begin hides the entries a transaction appends until it commits; a rollback
+// removes them
+func (r *OutboxRepository) begin() (commit, rollback func()) {
+	r.mutex.Lock()
+	r.committed = len(r.order)
+	r.mutex.Unlock()
+
+	commit = func() {
+		r.mutex.Lock()
+		defer r.mutex.Unlock()
+		r.committed = -1
+	}
+	rollback = func() {
+		r.mutex.Lock()
+		defer r.mutex.Unlock()
+		for _, id := range r.order[r.committed:] {
+			delete(r.entries, id)
+		}
+		r.order = r.order[:r.committed]
+		r.committed = -1
+	}
+	return commit, rollback
+}
+
+// visible returns the IDs of committed entries in the order they were written
+func (r *OutboxRepository) visible() []types.ID {
+	if r.committed < 0 {
+		return r.order
+	}
+	return r.order[:r.committed]
+}
+
+// ListDue retrieves pending entries due for delivery in the order they were
+// written. Once an aggregate has a pending entry that is not due yet, its
+// later entries are left out so they are never delivered ahead of it.
+func (r *OutboxRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]*entities.OutboxEntry, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	due := make([]*entities.OutboxEntry, 0)
+	waiting := make(map[types.ID]bool)
+	for _, id := range r.visible() {
+		entry := r.entries[id]
+		if !entry.IsPending() || waiting[entry.AggregateID] {
+			continue
+		}
+		if !entry.IsDue(now) {
+			waiting[entry.AggregateID] = true
+			continue
+		}
+
+		due = append(due, copyOutboxEntry(entry))
+		if limit > 0 && len(due) == limit {
+			break
+		}
+	}
+	return due, nil
+}
+
+// Update saves the delivery state of an entry
+func (r *OutboxRepository) Update(ctx context.Context, entry *entities.OutboxEntry) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.entries[entry.ID]; !exists {
+		return errors.NewNotFoundError("outbox entry not found")
+	}
+
+	r.entries[entry.ID] = copyOutboxEntry(entry)
+	return nil
+}
+
+// History retrieves entries in the order they were written, whatever their
+// delivery state, with pagination
+func (r *OutboxRepository) History(ctx context.Context, limit, offset int) ([]*entities.OutboxEntry, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	order := r.visible()
+	if offset > len(order) {
+		return []*entities.OutboxEntry{}, nil
+	}
+	end := len(order)
+	if limit > 0 && offset+limit < end {
+		end = offset + limit
+	}
+
+	result := make([]*entities.OutboxEntry, 0, end-offset)
+	for _, id := range order[offset:end] {
+		result = append(result, copyOutboxEntry(r.entries[id]))
+	}
+	return result, nil
+}
+
+// append stores entries prepared by an entity repository
+func (r *OutboxRepository) append(entries []*entities.OutboxEntry) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, entry := range entries {
+		if _, exists := r.entries[entry.ID]; !exists {
+			r.order = append(r.order, entry.ID)
+		}
+		r.entries[entry.ID] = copyOutboxEntry(entry)
+	}
+}
+
+// pendingEntries takes the events an entity recorded and serializes them
+// into outbox entries; nothing is stored until they are appended
+func pendingEntries(source events.Source) ([]*entities.OutboxEntry, error) {
+	recorded := source.PullEvents()
+	entries := make([]*entities.OutboxEntry, 0, len(recorded))
+	for _, event := range recorded {
+		entry, err := entities.NewOutboxEntry(event)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to serialize event")
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// copyOutboxEntry returns a deep copy of an entry
+func copyOutboxEntry(entry *entities.OutboxEntry) *entities.OutboxEntry {
+	entryCopy := *entry
+	entryCopy.Payload = append([]byte(nil), entry.Payload...)
+	if entry.DeliveredAt != nil {
+		deliveredAt := *entry.DeliveredAt
+		entryCopy.DeliveredAt = &deliveredAt
+	}
+	if entry.DeadAt != nil {
+		deadAt := *entry.DeadAt
+		entryCopy.DeadAt = &deadAt
+	}
+	return &entryCopy
+}
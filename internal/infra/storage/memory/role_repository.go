@@ -0,0 +1,93 @@
+package memory
+
+import (
+	"context"
+	"maps"
+	"sort"
+	"sync"
+
+	"shadow-id/internal/domain/entities"
+)
+
+// RoleRepository implements the role repository interface using in-memory storage
+type RoleRepository struct {
+	roles map[string]*entities.Role
+	mutex sync.RWMutex
+	participant
+}
+
+// NewRoleRepository creates a new in-memory role repository seeded with the built-in roles
+func NewRoleRepository() *RoleRepository {
+	repo := &RoleRepository{
+		roles: make(map[string]*entities.Role),
+	}
+	for _, role := range entities.BuiltInRoles() {
+		repo.roles[role.Name] = role
+	}
+	return repo
+}
+
+// begin captures the roles before a transaction changes them
+func (r *RoleRepository) begin() (commit, rollback func()) {
+	r.mutex.RLock()
+	roles := maps.Clone(r.roles)
+	r.mutex.RUnlock()
+
+	return noCommit, func() {
+		r.mutex.Lock()
+		defer r.mutex.Unlock()
+		r.roles = roles
+	}
+}
+
+// Create creates a new role
+func (r *RoleRepository) Create(ctx context.Context, role *entities.Role) error {
+	defer r.enter(ctx, r)()
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.roles[role.Name]; exists {
+		return entities.ErrRoleAlreadyExists
+	}
+
+	r.roles[role.Name] = copyRole(role)
+	return nil
+}
+
+// GetByName retrieves a role by name
+func (r *RoleRepository) GetByName(ctx context.Context, name string) (*entities.Role, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	role, exists := r.roles[name]
+	if !exists {
+		return nil, nil
+	}
+
+	// Return a copy to prevent external modifications
+	return copyRole(role), nil
+}
+
+// List retrieves all roles ordered by name
+func (r *RoleRepository) List(ctx context.Context) ([]*entities.Role, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	roles := make([]*entities.Role, 0, len(r.roles))
+	for _, role := range r.roles {
+		roles = append(roles, copyRole(role))
+	}
+
+	sort.Slice(roles, func(i, j int) bool {
+		return roles[i].Name < roles[j].Name
+	})
+	return roles, nil
+}
+
+// copyRole returns a deep copy of a role
+func copyRole(role *entities.Role) *entities.Role {
+	roleCopy := *role
+	roleCopy.Permissions = append([]entities.Permission(nil), role.Permissions...)
+	return &roleCopy
+}
@@ -0,0 +1,104 @@
+package memory
+
+import (
+	"context"
+	"maps"
+	"sync"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/pkg/types"
+)
+
+// TwoFactorRepository implements the two-factor repository interface using in-memory storage
+type TwoFactorRepository struct {
+	enrollments map[types.ID]*entities.TwoFactor
+	mutex       sync.RWMutex
+	participant
+}
+
+// NewTwoFactorRepository creates a new in-memory two-factor repository
+func NewTwoFactorRepository() *TwoFactorRepository {
+	return &TwoFactorRepository{
+		enrollments: make(map[types.ID]*entities.TwoFactor),
+	}
+}
+
+// begin captures the enrollments before a transaction changes them
+func (r *TwoFactorRepository) begin() (commit, rollback func()) {
+	r.mutex.RLock()
+	enrollments := maps.Clone(r.enrollments)
+	r.mutex.RUnlock()
+
+	return noCommit, func() {
+		r.mutex.Lock()
+		defer r.mutex.Unlock()
+		r.enrollments = enrollments
+	}
+}
+
+// Create stores a new enrollment, replacing any unconfirmed one for the user
+func (r *TwoFactorRepository) Create(ctx context.Context, twoFactor *entities.TwoFactor) error {
+	defer r.enter(ctx, r)()
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if existing, exists := r.enrollments[twoFactor.UserID]; exists && existing.Confirmed {
+		return entities.ErrTwoFactorAlreadyEnabled
+	}
+
+	r.enrollments[twoFactor.UserID] = copyTwoFactor(twoFactor)
+	return nil
+}
+
+// GetByUserID retrieves the enrollment for a user
+func (r *TwoFactorRepository) GetByUserID(ctx context.Context, userID types.ID) (*entities.TwoFactor, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	twoFactor, exists := r.enrollments[userID]
+	if !exists {
+		return nil, nil
+	}
+
+	// Return a copy to prevent external modifications
+	return copyTwoFactor(twoFactor), nil
+}
+
+// Update updates an existing enrollment
+func (r *TwoFactorRepository) Update(ctx context.Context, twoFactor *entities.TwoFactor) error {
+	defer r.enter(ctx, r)()
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.enrollments[twoFactor.UserID]; !exists {
+		return entities.ErrTwoFactorNotEnrolled
+	}
+
+	r.enrollments[twoFactor.UserID] = copyTwoFactor(twoFactor)
+	return nil
+}
+
+// Delete deletes the enrollment for a user
+func (r *TwoFactorRepository) Delete(ctx context.Context, userID types.ID) error {
+	defer r.enter(ctx, r)()
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.enrollments[userID]; !exists {
+		return entities.ErrTwoFactorNotEnrolled
+	}
+
+	delete(r.enrollments, userID)
+	return nil
+}
+
+// copyTwoFactor returns a deep copy of an enrollment
+func copyTwoFactor(twoFactor *entities.TwoFactor) *entities.TwoFactor {
+	twoFactorCopy := *twoFactor
+	twoFactorCopy.RecoveryCodes = make([]entities.RecoveryCode, len(twoFactor.RecoveryCodes))
+	copy(twoFactorCopy.RecoveryCodes, twoFactor.RecoveryCodes)
+	return &twoFactorCopy
+}
@@ -0,0 +1,146 @@
+package memory
+
+import (
+	"context"
+	"sync"
+)
+
+// Transactional is implemented by the in-memory repositories that can take
+// part in a unit of work
+type Transactional interface {
+	enlist(uow *UnitOfWork)
+
+	// begin captures the repository's state before a transaction first
+	// changes it and returns how to finish the transaction
+	begin() (commit, rollback func())
+}
+
+// UnitOfWork implements the unit of work interface for in-memory
+// repositories. Transactions run one at a time. Each enlisted repository is
+// copied the first time a transaction changes it, and the copy is restored if
+// the transaction fails. Writes made outside a transaction wait for the
+// running one to finish, so a rollback never discards them.
+type UnitOfWork struct {
+	mutex sync.Mutex
+}
+
+// txKey is the context key for the running in-memory transaction
+type txKey struct{}
+
+// memoryTx tracks the repositories a transaction changed
+type memoryTx struct {
+	uow       *UnitOfWork
+	enlisted  []Transactional
+	commits   []func()
+	rollbacks []func()
+}
+
+// NewUnitOfWork creates a new in-memory unit of work
+func NewUnitOfWork() *UnitOfWork {
+	return &UnitOfWork{}
+}
+
+// Enlist makes repositories take part in the unit of work's transactions
+func (u *UnitOfWork) Enlist(repos ...Transactional) {
+	for _, repo := range repos {
+		repo.enlist(u)
+	}
+}
+
+// WithinTx runs fn in a transaction, rolling back every enlisted repository
+// it changed if fn fails or panics
+func (u *UnitOfWork) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if u.txFrom(ctx) != nil {
+		return fn(ctx)
+	}
+
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+
+	tx := &memoryTx{uow: u}
+	committed := false
+	defer func() {
+		if !committed {
+			tx.rollback()
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+	tx.commit()
+	committed = true
+	return nil
+}
+
+// enter prepares repositories for a write. Inside a transaction the
+// repositories are captured on their first change; outside one the write
+// waits for the running transaction. The returned function ends the write.
+func (u *UnitOfWork) enter(ctx context.Context, repos ...Transactional) func() {
+	if tx := u.txFrom(ctx); tx != nil {
+		for _, repo := range repos {
+			tx.join(repo)
+		}
+		return func() {}
+	}
+
+	u.mutex.Lock()
+	return u.mutex.Unlock
+}
+
+// txFrom returns the unit of work's transaction carried by the context
+func (u *UnitOfWork) txFrom(ctx context.Context) *memoryTx {
+	if tx, ok := ctx.Value(txKey{}).(*memoryTx); ok && tx.uow == u {
+		return tx
+	}
+	return nil
+}
+
+// join captures a repository the first time the transaction changes it
+func (tx *memoryTx) join(repo Transactional) {
+	for _, enlisted := range tx.enlisted {
+		if enlisted == repo {
+			return
+		}
+	}
+	commit, rollback := repo.begin()
+	tx.enlisted = append(tx.enlisted, repo)
+	tx.commits = append(tx.commits, commit)
+	tx.rollbacks = append(tx.rollbacks, rollback)
+}
+
+// commit finishes the transaction keeping its changes
+func (tx *memoryTx) commit() {
+	for _, commit := range tx.commits {
+		commit()
+	}
+}
+
+// rollback restores the repositories in the reverse order they were changed
+func (tx *memoryTx) rollback() {
+	for i := len(tx.rollbacks) - 1; i >= 0; i-- {
+		tx.rollbacks[i]()
+	}
+}
+
+// participant is embedded by repositories that can be enlisted in a unit of work
+type participant struct {
+	uow *UnitOfWork
+}
+
+// enlist records the unit of work the repository takes part in
+func (p *participant) enlist(uow *UnitOfWork) {
+	p.uow = uow
+}
+
+// enter prepares repositories for a write; repositories that were never
+// enlisted write straight away
+func (p *participant) enter(ctx context.Context, repos ...Transactional) func() {
+	if p.uow == nil {
+		return func() {}
+	}
+	return p.uow.enter(ctx, repos...)
+}
+
+// noCommit is the commit step of repositories with nothing to finish
+func noCommit() {}
@@ -0,0 +1,171 @@
+package memory
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+
+	"shadow-id/internal/app/pipeline"
+	"shadow-id/internal/domain/entities"
+)
+
+var errHandlerFailed = stderrors.New("handler failed")
+
+// createRole creates a role of the name in the repository
+func createRole(ctx context.Context, t *testing.T, repo *RoleRepository, name string) {
+	t.Helper()
+	if err := repo.Create(ctx, entities.NewRole(name, "", nil)); err != nil {
+		t.Fatalf("Create(%s) error = %v", name, err)
+	}
+}
+
+// hasRole reports whether the repository holds a role of the name
+func hasRole(t *testing.T, repo *RoleRepository, name string) bool {
+	t.Helper()
+	role, err := repo.GetByName(context.Background(), name)
+	if err != nil {
+		t.Fatalf("GetByName(%s) error = %v", name, err)
+	}
+	return role != nil
+}
+
+func TestWithinTxCommitsChanges(t *testing.T) {
+	uow := NewUnitOfWork()
+	roles := NewRoleRepository()
+	uow.Enlist(roles)
+
+	err := uow.WithinTx(context.Background(), func(ctx context.Context) error {
+		createRole(ctx, t, roles, "auditor")
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithinTx() error = %v", err)
+	}
+	if !hasRole(t, roles, "auditor") {
+		t.Error("role created in a committed transaction is missing")
+	}
+}
+
+func TestWithinTxRollsBackEnlistedRepositories(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(ctx context.Context) error
+	}{
+		{"error", func(ctx context.Context) error { return errHandlerFailed }},
+		{"panic", func(ctx context.Context) error { panic(errHandlerFailed) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uow := NewUnitOfWork()
+			roles := NewRoleRepository()
+			uow.Enlist(roles)
+
+			func() {
+				defer func() { recover() }()
+				err := uow.WithinTx(context.Background(), func(ctx context.Context) error {
+					createRole(ctx, t, roles, "auditor")
+					return tt.fn(ctx)
+				})
+				if !stderrors.Is(err, errHandlerFailed) {
+					t.Errorf("WithinTx() error = %v, want %v", err, errHandlerFailed)
+				}
+			}()
+
+			if hasRole(t, roles, "auditor") {
+				t.Error("role created in a failed transaction was kept")
+			}
+			if !hasRole(t, roles, entities.RoleAdmin) {
+				t.Error("rollback lost the roles held before the transaction")
+			}
+		})
+	}
+}
+
+func TestWithinTxKeepsWritesOfRepositoriesNotEnlisted(t *testing.T) {
+	uow := NewUnitOfWork()
+	enlisted := NewRoleRepository()
+	standalone := NewRoleRepository()
+	uow.Enlist(enlisted)
+
+	err := uow.WithinTx(context.Background(), func(ctx context.Context) error {
+		createRole(ctx, t, enlisted, "auditor")
+		createRole(ctx, t, standalone, "auditor")
+		return errHandlerFailed
+	})
+	if !stderrors.Is(err, errHandlerFailed) {
+		t.Fatalf("WithinTx() error = %v, want %v", err, errHandlerFailed)
+	}
+	if hasRole(t, enlisted, "auditor") {
+		t.Error("enlisted repository kept a write of a failed transaction")
+	}
+	if !hasRole(t, standalone, "auditor") {
+		t.Error("repository that was not enlisted lost its write")
+	}
+}
+
+func TestNestedWithinTxJoinsTheRunningTransaction(t *testing.T) {
+	uow := NewUnitOfWork()
+	roles := NewRoleRepository()
+	uow.Enlist(roles)
+
+	err := uow.WithinTx(context.Background(), func(ctx context.Context) error {
+		if err := uow.WithinTx(ctx, func(ctx context.Context) error {
+			createRole(ctx, t, roles, "auditor")
+			return nil
+		}); err != nil {
+			return err
+		}
+		return errHandlerFailed
+	})
+	if !stderrors.Is(err, errHandlerFailed) {
+		t.Fatalf("WithinTx() error = %v, want %v", err, errHandlerFailed)
+	}
+	if hasRole(t, roles, "auditor") {
+		t.Error("write of a nested transaction survived the outer rollback")
+	}
+}
+
+func TestCommitHooksRunInTheTransaction(t *testing.T) {
+	tests := []struct {
+		name       string
+		handlerErr error
+		hookErr    error
+		wantHook   bool
+		wantKept   bool
+	}{
+		{"commit", nil, nil, true, true},
+		{"handler fails", errHandlerFailed, nil, false, false},
+		{"hook fails", nil, errHandlerFailed, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uow := NewUnitOfWork()
+			roles := NewRoleRepository()
+			uow.Enlist(roles)
+
+			hookRan := false
+			ctx := pipeline.WithCommitHook(context.Background(), func(ctx context.Context, result interface{}) error {
+				hookRan = true
+				createRole(ctx, t, roles, "hooked")
+				return tt.hookErr
+			})
+			handler := pipeline.Transaction(uow)(func(ctx context.Context, req *pipeline.Request) (interface{}, error) {
+				createRole(ctx, t, roles, "auditor")
+				return nil, tt.handlerErr
+			})
+
+			_, err := handler(ctx, &pipeline.Request{Kind: pipeline.KindCommand, Name: "CreateRole"})
+			if wantErr := tt.handlerErr != nil || tt.hookErr != nil; (err != nil) != wantErr {
+				t.Fatalf("Transaction() error = %v, want error %v", err, wantErr)
+			}
+			if hookRan != tt.wantHook {
+				t.Errorf("hook ran = %v, want %v", hookRan, tt.wantHook)
+			}
+			for _, name := range []string{"auditor", "hooked"} {
+				if got := hasRole(t, roles, name); got != tt.wantKept {
+					t.Errorf("role %s kept = %v, want %v", name, got, tt.wantKept)
+				}
+			}
+		})
+	}
+}
@@ -2,32 +2,62 @@ package memory
 
 import (
 	"context"
+	"maps"
+	"sort"
 	"sync"
+	"time"
 
 	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/events"
 	"shadow-id/pkg/types"
 )
 
 // UserRepository implements the user repository interface using in-memory storage
 type UserRepository struct {
-	users map[types.ID]*entities.User
-	mutex sync.RWMutex
+	users  map[types.ID]*entities.User
+	mutex  sync.RWMutex
+	outbox *OutboxRepository
+	participant
 }
 
-// NewUserRepository creates a new in-memory user repository
-func NewUserRepository() *UserRepository {
+// NewUserRepository creates a new in-memory user repository that stores
+// recorded events in the outbox
+func NewUserRepository(outbox *OutboxRepository) *UserRepository {
 	return &UserRepository{
-		users: make(map[types.ID]*entities.User),
-		mutex: sync.RWMutex{},
+		users:  make(map[types.ID]*entities.User),
+		mutex:  sync.RWMutex{},
+		outbox: outbox,
+	}
+}
+
+// begin captures the users before a transaction changes them
+func (r *UserRepository) begin() (commit, rollback func()) {
+	r.mutex.RLock()
+	users := maps.Clone(r.users)
+	r.mutex.RUnlock()
+
+	return noCommit, func() {
+		r.mutex.Lock()
+		defer r.mutex.Unlock()
+		r.users = users
 	}
 }
 
 // Create creates a new user
 func (r *UserRepository) Create(ctx context.Context, user *entities.User) error {
+	defer r.enter(ctx, r, r.outbox)()
+
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
-	
-	r.users[user.ID] = user
+
+	entries, err := pendingEntries(user)
+	if err != nil {
+		return err
+	}
+
+	userCopy := *user
+	r.users[user.ID] = &userCopy
+	r.outbox.append(entries)
 	return nil
 }
 
@@ -37,7 +67,7 @@ func (r *UserRepository) GetByID(ctx context.Context, id types.ID) (*entities.Us
 	defer r.mutex.RUnlock()
 	
 	user, exists := r.users[id]
-	if !exists {
+	if !exists || user.IsDeleted() {
 		return nil, nil
 	}
 	
@@ -46,13 +76,50 @@ func (r *UserRepository) GetByID(ctx context.Context, id types.ID) (*entities.Us
 	return &userCopy, nil
 }
 
+// GetDeleted retrieves a soft-deleted user by ID
+func (r *UserRepository) GetDeleted(ctx context.Context, id types.ID) (*entities.User, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	user, exists := r.users[id]
+	if !exists || !user.IsDeleted() {
+		return nil, nil
+	}
+
+	userCopy := *user
+	return &userCopy, nil
+}
+
+// ListDeleted retrieves up to limit users soft-deleted before the given time,
+// longest deleted first
+func (r *UserRepository) ListDeleted(ctx context.Context, deletedBefore time.Time, limit int) ([]*entities.User, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	users := make([]*entities.User, 0)
+	for _, user := range r.users {
+		if user.IsDeleted() && user.DeletedAt.Before(deletedBefore) {
+			userCopy := *user
+			users = append(users, &userCopy)
+		}
+	}
+
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].DeletedAt.Before(*users[j].DeletedAt)
+	})
+	if limit > 0 && len(users) > limit {
+		users = users[:limit]
+	}
+	return users, nil
+}
+
 // GetByEmail retrieves a user by email
 func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*entities.User, error) {
 	r.mutex.RLock()
 	defer r.mutex.RUnlock()
 	
 	for _, user := range r.users {
-		if user.Email == email {
+		if user.Email == email && !user.IsDeleted() {
 			// Return a copy to prevent external modifications
 			userCopy := *user
 			return &userCopy, nil
@@ -62,28 +129,90 @@ func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*entitie
 	return nil, nil
 }
 
-// Update updates an existing user
+// Update updates an existing user, including a soft-deleted one
 func (r *UserRepository) Update(ctx context.Context, user *entities.User) error {
+	defer r.enter(ctx, r, r.outbox)()
+
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 	
-	if _, exists := r.users[user.ID]; !exists {
+	stored, exists := r.users[user.ID]
+	if !exists {
 		return entities.ErrUserNotFound
 	}
-	
-	r.users[user.ID] = user
+	if err := checkVersion(stored.Version, user.Version, user.PendingCount()); err != nil {
+		return err
+	}
+
+	entries, err := pendingEntries(user)
+	if err != nil {
+		return err
+	}
+
+	userCopy := *user
+	r.users[user.ID] = &userCopy
+	r.outbox.append(entries)
 	return nil
 }
 
-// Delete deletes a user by ID
-func (r *UserRepository) Delete(ctx context.Context, id types.ID) error {
+// Delete stores the soft deletion of a user marked with MarkDeleted
+func (r *UserRepository) Delete(ctx context.Context, user *entities.User) error {
+	if !user.IsDeleted() {
+		return entities.ErrUserNotDeleted
+	}
+	return r.Update(ctx, user)
+}
+
+// Purge removes a soft-deleted user for good
+func (r *UserRepository) Purge(ctx context.Context, user *entities.User) error {
+	defer r.enter(ctx, r, r.outbox)()
+
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
-	
-	if _, exists := r.users[id]; !exists {
+
+	stored, exists := r.users[user.ID]
+	if !exists {
 		return entities.ErrUserNotFound
 	}
-	
+	if !stored.IsDeleted() {
+		return entities.ErrUserNotDeleted
+	}
+	if err := checkVersion(stored.Version, user.Version, user.PendingCount()); err != nil {
+		return err
+	}
+
+	entries, err := pendingEntries(user)
+	if err != nil {
+		return err
+	}
+
+	delete(r.users, user.ID)
+	r.outbox.append(entries)
+	return nil
+}
+
+// Save stores the user as given, without the version check and without
+// publishing its events. Projections use it to maintain read models.
+func (r *UserRepository) Save(ctx context.Context, user *entities.User) error {
+	defer r.enter(ctx, r)()
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	userCopy := *user
+	userCopy.Recorder = events.Recorder{}
+	r.users[user.ID] = &userCopy
+	return nil
+}
+
+// Remove deletes the user with the given ID, without the version check.
+// Projections use it to maintain read models.
+func (r *UserRepository) Remove(ctx context.Context, id types.ID) error {
+	defer r.enter(ctx, r)()
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
 	delete(r.users, id)
 	return nil
 }
@@ -95,7 +224,9 @@ func (r *UserRepository) List(ctx context.Context, limit, offset int) ([]*entiti
 	
 	users := make([]*entities.User, 0, len(r.users))
 	for _, user := range r.users {
-		users = append(users, user)
+		if !user.IsDeleted() {
+			users = append(users, user)
+		}
 	}
 	
 	// Simple pagination
@@ -123,5 +254,11 @@ func (r *UserRepository) Count(ctx context.Context) (int64, error) {
 	r.mutex.RLock()
 	defer r.mutex.RUnlock()
 	
-	return int64(len(r.users)), nil
+	count := 0
+	for _, user := range r.users {
+		if !user.IsDeleted() {
+			count++
+		}
+	}
+	return int64(count), nil
 }
@@ -0,0 +1,120 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/pkg/types"
+)
+
+// UserSummaryRepository implements the user summary repository interface using in-memory storage
+type UserSummaryRepository struct {
+	summaries map[types.ID]*entities.UserSummary
+	mutex     sync.RWMutex
+}
+
+// NewUserSummaryRepository creates a new in-memory user summary repository
+func NewUserSummaryRepository() *UserSummaryRepository {
+	return &UserSummaryRepository{
+		summaries: make(map[types.ID]*entities.UserSummary),
+	}
+}
+
+// Save creates or replaces a summary
+func (r *UserSummaryRepository) Save(ctx context.Context, summary *entities.UserSummary) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.summaries[summary.ID] = copyUserSummary(summary)
+	return nil
+}
+
+// GetByID retrieves a summary by user ID
+func (r *UserSummaryRepository) GetByID(ctx context.Context, id types.ID) (*entities.UserSummary, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	summary, exists := r.summaries[id]
+	if !exists {
+		return nil, nil
+	}
+	return copyUserSummary(summary), nil
+}
+
+// List retrieves summaries ordered by creation time with pagination
+func (r *UserSummaryRepository) List(ctx context.Context, limit, offset int, includeDeleted bool) ([]*entities.UserSummary, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	summaries := make([]*entities.UserSummary, 0, len(r.summaries))
+	for _, summary := range r.summaries {
+		if includeDeleted || !summary.IsDeleted() {
+			summaries = append(summaries, summary)
+		}
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].CreatedAt.Equal(summaries[j].CreatedAt) {
+			return summaries[i].ID < summaries[j].ID
+		}
+		return summaries[i].CreatedAt.Before(summaries[j].CreatedAt)
+	})
+
+	if offset > len(summaries) {
+		return []*entities.UserSummary{}, nil
+	}
+	end := len(summaries)
+	if limit > 0 && offset+limit < end {
+		end = offset + limit
+	}
+
+	result := make([]*entities.UserSummary, 0, end-offset)
+	for _, summary := range summaries[offset:end] {
+		result = append(result, copyUserSummary(summary))
+	}
+	return result, nil
+}
+
+// Count returns the number of summaries
+func (r *UserSummaryRepository) Count(ctx context.Context, includeDeleted bool) (int64, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if includeDeleted {
+		return int64(len(r.summaries)), nil
+	}
+	var count int64
+	for _, summary := range r.summaries {
+		if !summary.IsDeleted() {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// Delete removes a summary; removing a missing summary is not an error
+func (r *UserSummaryRepository) Delete(ctx context.Context, id types.ID) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	delete(r.summaries, id)
+	return nil
+}
+
+// Clear removes every summary ahead of a rebuild
+func (r *UserSummaryRepository) Clear(ctx context.Context) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.summaries = make(map[types.ID]*entities.UserSummary)
+	return nil
+}
+
+// copyUserSummary returns a copy of a summary that shares no slices with it
+func copyUserSummary(summary *entities.UserSummary) *entities.UserSummary {
+	summaryCopy := *summary
+	summaryCopy.Roles = append([]string(nil), summary.Roles...)
+	summaryCopy.DeviceIDs = append([]types.ID(nil), summary.DeviceIDs...)
+	return &summaryCopy
+}
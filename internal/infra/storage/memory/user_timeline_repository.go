@@ -0,0 +1,112 @@
+package memory
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sort"
+	"sync"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/pkg/types"
+)
+
+// UserTimelineRepository implements the user timeline repository interface using in-memory storage
+type UserTimelineRepository struct {
+	entries map[types.ID][]*entities.TimelineEntry
+	ids     map[types.ID]struct{}
+	mutex   sync.RWMutex
+}
+
+// NewUserTimelineRepository creates a new in-memory user timeline repository
+func NewUserTimelineRepository() *UserTimelineRepository {
+	return &UserTimelineRepository{
+		entries: make(map[types.ID][]*entities.TimelineEntry),
+		ids:     make(map[types.ID]struct{}),
+	}
+}
+
+// Append stores an entry; appending an entry with a stored ID has no effect
+func (r *UserTimelineRepository) Append(ctx context.Context, entry *entities.TimelineEntry) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.ids[entry.ID]; exists {
+		return nil
+	}
+	r.ids[entry.ID] = struct{}{}
+
+	// Keep each user's entries ordered newest first
+	entries := r.entries[entry.UserID]
+	at := sort.Search(len(entries), func(i int) bool {
+		return entries[i].OccurredAt.Before(entry.OccurredAt)
+	})
+	r.entries[entry.UserID] = slices.Insert(entries, at, copyTimelineEntry(entry))
+	return nil
+}
+
+// List retrieves a page of a user's entries that pass a filter, newest first
+func (r *UserTimelineRepository) List(ctx context.Context, userID types.ID, filter entities.TimelineFilter, limit, offset int) ([]*entities.TimelineEntry, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	result := make([]*entities.TimelineEntry, 0)
+	skipped := 0
+	for _, entry := range r.entries[userID] {
+		if len(result) >= limit {
+			break
+		}
+		if !filter.Matches(entry) {
+			continue
+		}
+		if skipped < offset {
+			skipped++
+			continue
+		}
+		result = append(result, copyTimelineEntry(entry))
+	}
+	return result, nil
+}
+
+// Count returns the number of a user's entries that pass a filter
+func (r *UserTimelineRepository) Count(ctx context.Context, userID types.ID, filter entities.TimelineFilter) (int64, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var count int64
+	for _, entry := range r.entries[userID] {
+		if filter.Matches(entry) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// DeleteByUserID removes every entry of a user
+func (r *UserTimelineRepository) DeleteByUserID(ctx context.Context, userID types.ID) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, entry := range r.entries[userID] {
+		delete(r.ids, entry.ID)
+	}
+	delete(r.entries, userID)
+	return nil
+}
+
+// Clear removes every entry ahead of a rebuild
+func (r *UserTimelineRepository) Clear(ctx context.Context) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.entries = make(map[types.ID][]*entities.TimelineEntry)
+	r.ids = make(map[types.ID]struct{})
+	return nil
+}
+
+// copyTimelineEntry returns a deep copy of an entry
+func copyTimelineEntry(entry *entities.TimelineEntry) *entities.TimelineEntry {
+	entryCopy := *entry
+	entryCopy.Details = maps.Clone(entry.Details)
+	return &entryCopy
+}
@@ -0,0 +1,18 @@
+package memory
+
+import (
+	"shadow-id/internal/domain/entities"
+	"shadow-id/pkg/errors"
+)
+
+// checkVersion fails with a conflict unless the stored entity is still at the
+// version the caller loaded, i.e. the entity's version before its pending events
+func checkVersion(currentVersion, version int64, pending int) error {
+	expectedVersion := version - int64(pending)
+	if currentVersion == expectedVersion {
+		return nil
+	}
+	return errors.WrapWithType(entities.ErrVersionConflict, errors.ErrorTypeConflict, "version check failed").
+		WithDetail("expected_version", expectedVersion).
+		WithDetail("current_version", currentVersion)
+}
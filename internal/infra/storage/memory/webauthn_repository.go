@@ -0,0 +1,183 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"maps"
+	"sort"
+	"sync"
+
+	"shadow-id/internal/domain/entities"
+	"shadow-id/pkg/types"
+)
+
+// WebAuthnCredentialRepository implements the passkey credential repository using in-memory storage
+type WebAuthnCredentialRepository struct {
+	credentials map[types.ID]*entities.WebAuthnCredential
+	mutex       sync.RWMutex
+	participant
+}
+
+// NewWebAuthnCredentialRepository creates a new in-memory credential repository
+func NewWebAuthnCredentialRepository() *WebAuthnCredentialRepository {
+	return &WebAuthnCredentialRepository{
+		credentials: make(map[types.ID]*entities.WebAuthnCredential),
+	}
+}
+
+// begin captures the credentials before a transaction changes them
+func (r *WebAuthnCredentialRepository) begin() (commit, rollback func()) {
+	r.mutex.RLock()
+	credentials := maps.Clone(r.credentials)
+	r.mutex.RUnlock()
+
+	return noCommit, func() {
+		r.mutex.Lock()
+		defer r.mutex.Unlock()
+		r.credentials = credentials
+	}
+}
+
+// Create creates a new credential
+func (r *WebAuthnCredentialRepository) Create(ctx context.Context, credential *entities.WebAuthnCredential) error {
+	defer r.enter(ctx, r)()
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, existing := range r.credentials {
+		if bytes.Equal(existing.CredentialID, credential.CredentialID) {
+			return entities.ErrCredentialAlreadyExists
+		}
+	}
+
+	r.credentials[credential.ID] = copyCredential(credential)
+	return nil
+}
+
+// GetByCredentialID retrieves a credential by its authenticator-assigned ID
+func (r *WebAuthnCredentialRepository) GetByCredentialID(ctx context.Context, credentialID []byte) (*entities.WebAuthnCredential, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for _, credential := range r.credentials {
+		if bytes.Equal(credential.CredentialID, credentialID) {
+			return copyCredential(credential), nil
+		}
+	}
+	return nil, nil
+}
+
+// ListByUserID retrieves all credentials registered to a user, oldest first
+func (r *WebAuthnCredentialRepository) ListByUserID(ctx context.Context, userID types.ID) ([]*entities.WebAuthnCredential, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	credentials := make([]*entities.WebAuthnCredential, 0)
+	for _, credential := range r.credentials {
+		if credential.UserID == userID {
+			credentials = append(credentials, copyCredential(credential))
+		}
+	}
+
+	sort.Slice(credentials, func(i, j int) bool {
+		return credentials[i].CreatedAt.Before(credentials[j].CreatedAt)
+	})
+	return credentials, nil
+}
+
+// Update updates an existing credential
+func (r *WebAuthnCredentialRepository) Update(ctx context.Context, credential *entities.WebAuthnCredential) error {
+	defer r.enter(ctx, r)()
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.credentials[credential.ID]; !exists {
+		return entities.ErrCredentialNotFound
+	}
+
+	r.credentials[credential.ID] = copyCredential(credential)
+	return nil
+}
+
+// Delete deletes a credential by ID
+func (r *WebAuthnCredentialRepository) Delete(ctx context.Context, id types.ID) error {
+	defer r.enter(ctx, r)()
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.credentials[id]; !exists {
+		return entities.ErrCredentialNotFound
+	}
+
+	delete(r.credentials, id)
+	return nil
+}
+
+// copyCredential returns a deep copy of a credential
+func copyCredential(credential *entities.WebAuthnCredential) *entities.WebAuthnCredential {
+	credentialCopy := *credential
+	credentialCopy.CredentialID = bytes.Clone(credential.CredentialID)
+	credentialCopy.PublicKey = bytes.Clone(credential.PublicKey)
+	credentialCopy.AAGUID = bytes.Clone(credential.AAGUID)
+	return &credentialCopy
+}
+
+// WebAuthnSessionRepository implements the ceremony session repository using in-memory storage
+type WebAuthnSessionRepository struct {
+	sessions map[types.ID]*entities.WebAuthnSession
+	mutex    sync.Mutex
+}
+
+// NewWebAuthnSessionRepository creates a new in-memory ceremony session repository
+func NewWebAuthnSessionRepository() *WebAuthnSessionRepository {
+	return &WebAuthnSessionRepository{
+		sessions: make(map[types.ID]*entities.WebAuthnSession),
+	}
+}
+
+// Create stores a new ceremony session and drops any that have expired
+func (r *WebAuthnSessionRepository) Create(ctx context.Context, session *entities.WebAuthnSession) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for id, existing := range r.sessions {
+		if existing.IsExpired() {
+			delete(r.sessions, id)
+		}
+	}
+
+	sessionCopy := *session
+	r.sessions[session.ID] = &sessionCopy
+	return nil
+}
+
+// GetByID retrieves a ceremony session without consuming it
+func (r *WebAuthnSessionRepository) GetByID(ctx context.Context, id types.ID) (*entities.WebAuthnSession, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	session, exists := r.sessions[id]
+	if !exists {
+		return nil, nil
+	}
+
+	sessionCopy := *session
+	return &sessionCopy, nil
+}
+
+// Take retrieves and removes a ceremony session so it can only be used once
+func (r *WebAuthnSessionRepository) Take(ctx context.Context, id types.ID) (*entities.WebAuthnSession, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	session, exists := r.sessions[id]
+	if !exists {
+		return nil, nil
+	}
+
+	delete(r.sessions, id)
+	return session, nil
+}
@@ -0,0 +1,94 @@
+package vault
+
+import (
+	"bytes"
+	"maps"
+	"sync"
+)
+
+// State is everything a vault persists. Secrets are only ever stored
+// encrypted; the passphrase and the key derived from it are never stored.
+type State struct {
+	Header  Header            `json:"header"`
+	Secrets map[string]Record `json:"secrets"`
+}
+
+// Header describes how the vault key is derived and lets a passphrase be
+// checked before any secret is decrypted
+type Header struct {
+	FormatVersion int       `json:"format_version"`
+	Cipher        string    `json:"cipher"`
+	KeyVersion    int       `json:"key_version"`
+	Salt          []byte    `json:"salt"`
+	KDF           KDFParams `json:"kdf"`
+	Check         Record    `json:"check"`
+}
+
+// Record is an encrypted value and the nonce it was sealed with
+type Record struct {
+	KeyVersion int    `json:"key_version"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// Backend stores a vault's state. Save replaces the stored state as a whole,
+// so a rotation is either fully persisted or not at all.
+type Backend interface {
+	// Load returns the stored state, or nil if nothing was stored yet
+	Load() (*State, error)
+
+	// Save replaces the stored state
+	Save(state *State) error
+}
+
+// MemoryBackend keeps the vault state in memory
+type MemoryBackend struct {
+	mu    sync.Mutex
+	state *State
+}
+
+// NewMemoryBackend creates an empty in-memory backend
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{}
+}
+
+// Load returns a copy of the stored state
+func (b *MemoryBackend) Load() (*State, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == nil {
+		return nil, nil
+	}
+	return copyState(b.state), nil
+}
+
+// Save stores a copy of the state
+func (b *MemoryBackend) Save(state *State) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = copyState(state)
+	return nil
+}
+
+// copyState returns a copy of a state that shares no slices or maps with it
+func copyState(state *State) *State {
+	stateCopy := &State{
+		Header:  state.Header,
+		Secrets: maps.Clone(state.Secrets),
+	}
+	stateCopy.Header.Salt = bytes.Clone(state.Header.Salt)
+	stateCopy.Header.Check = copyRecord(state.Header.Check)
+	for name, record := range stateCopy.Secrets {
+		stateCopy.Secrets[name] = copyRecord(record)
+	}
+	return stateCopy
+}
+
+// copyRecord returns a copy of a record
+func copyRecord(record Record) Record {
+	record.Nonce = bytes.Clone(record.Nonce)
+	record.Ciphertext = bytes.Clone(record.Ciphertext)
+	return record
+}
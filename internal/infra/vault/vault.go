@@ -0,0 +1,310 @@
+package vault
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+const (
+	// formatVersion is the version of the stored state this package writes
+	formatVersion = 1
+
+	// CipherXChaCha20Poly1305 names the AEAD secrets are sealed with
+	CipherXChaCha20Poly1305 = "xchacha20-poly1305"
+
+	saltSize = 16
+
+	// checkPlaintext is sealed into the header so a wrong passphrase is
+	// detected without touching any secret
+	checkPlaintext = "shadow-id vault"
+)
+
+// Vault errors
+var (
+	ErrEmptyPassphrase   = errors.New("vault passphrase must not be empty")
+	ErrWrongPassphrase   = errors.New("vault passphrase is incorrect")
+	ErrUnsupportedFormat = errors.New("vault format is not supported")
+	ErrInvalidName       = errors.New("secret name must not be empty")
+	ErrSecretNotFound    = errors.New("secret not found in vault")
+	ErrLocked            = errors.New("vault is locked")
+)
+
+// KDFParams are the argon2id parameters the vault key is derived with
+type KDFParams struct {
+	Time      uint32 `json:"time"`
+	MemoryKiB uint32 `json:"memory_kib"`
+	Threads   uint8  `json:"threads"`
+}
+
+// DefaultKDFParams returns the argon2id parameters recommended by RFC 9106
+// for memory-constrained environments
+func DefaultKDFParams() KDFParams {
+	return KDFParams{Time: 3, MemoryKiB: 64 * 1024, Threads: 4}
+}
+
+// Vault stores secrets encrypted with XChaCha20-Poly1305 under a key derived
+// from a passphrase with argon2id. Every secret is bound to its name and the
+// key version, so sealed values cannot be swapped between names or survive a
+// rotation unnoticed.
+type Vault struct {
+	mu      sync.RWMutex
+	backend Backend
+	state   *State
+	aead    cipher.AEAD
+}
+
+// Open unlocks the vault stored in the backend with a passphrase. An empty
+// backend is initialized with a new key derived with params; an existing
+// vault keeps the parameters it was created or last rotated with.
+func Open(backend Backend, passphrase []byte, params KDFParams) (*Vault, error) {
+	if len(passphrase) == 0 {
+		return nil, ErrEmptyPassphrase
+	}
+
+	state, err := backend.Load()
+	if err != nil {
+		return nil, fmt.Errorf("load vault: %w", err)
+	}
+
+	// Initialize a new vault
+	if state == nil {
+		header, aead, err := newHeader(passphrase, params, 1)
+		if err != nil {
+			return nil, err
+		}
+		state = &State{Header: header, Secrets: make(map[string]Record)}
+		if err := backend.Save(state); err != nil {
+			return nil, fmt.Errorf("save vault: %w", err)
+		}
+		return &Vault{backend: backend, state: state, aead: aead}, nil
+	}
+
+	// Unlock an existing vault
+	if state.Header.FormatVersion != formatVersion || state.Header.Cipher != CipherXChaCha20Poly1305 {
+		return nil, ErrUnsupportedFormat
+	}
+	aead, err := deriveAEAD(passphrase, state.Header.Salt, state.Header.KDF)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := open(aead, "", state.Header.Check); err != nil {
+		return nil, ErrWrongPassphrase
+	}
+	if state.Secrets == nil {
+		state.Secrets = make(map[string]Record)
+	}
+	return &Vault{backend: backend, state: state, aead: aead}, nil
+}
+
+// KeyVersion returns the version of the current vault key; it starts at one
+// and grows with every rotation
+func (v *Vault) KeyVersion() int {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	return v.state.Header.KeyVersion
+}
+
+// Names returns the names of the stored secrets in order
+func (v *Vault) Names() []string {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	names := make([]string, 0, len(v.state.Secrets))
+	for name := range v.state.Secrets {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Put encrypts and stores a secret, replacing any stored under the same name
+func (v *Vault) Put(name string, secret []byte) error {
+	if name == "" {
+		return ErrInvalidName
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.aead == nil {
+		return ErrLocked
+	}
+	record, err := seal(v.aead, v.state.Header.KeyVersion, name, secret)
+	if err != nil {
+		return err
+	}
+
+	next := copyState(v.state)
+	next.Secrets[name] = record
+	return v.save(next)
+}
+
+// Get decrypts a stored secret
+func (v *Vault) Get(name string) ([]byte, error) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	if v.aead == nil {
+		return nil, ErrLocked
+	}
+	record, exists := v.state.Secrets[name]
+	if !exists {
+		return nil, ErrSecretNotFound
+	}
+	secret, err := open(v.aead, name, record)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt secret %q: %w", name, err)
+	}
+	return secret, nil
+}
+
+// Delete removes a secret; removing a missing secret is not an error
+func (v *Vault) Delete(name string) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.aead == nil {
+		return ErrLocked
+	}
+	if _, exists := v.state.Secrets[name]; !exists {
+		return nil
+	}
+
+	next := copyState(v.state)
+	delete(next.Secrets, name)
+	return v.save(next)
+}
+
+// Rotate derives a new key from a passphrase, which may be the current one,
+// with a fresh salt and params, and re-encrypts every secret under it. The
+// re-encrypted vault replaces the stored one in a single save; if any secret
+// cannot be decrypted nothing is changed.
+func (v *Vault) Rotate(passphrase []byte, params KDFParams) error {
+	if len(passphrase) == 0 {
+		return ErrEmptyPassphrase
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.aead == nil {
+		return ErrLocked
+	}
+	header, aead, err := newHeader(passphrase, params, v.state.Header.KeyVersion+1)
+	if err != nil {
+		return err
+	}
+
+	// Re-encrypt every secret under the new key
+	next := &State{Header: header, Secrets: make(map[string]Record, len(v.state.Secrets))}
+	for name, record := range v.state.Secrets {
+		secret, err := open(v.aead, name, record)
+		if err != nil {
+			return fmt.Errorf("decrypt secret %q: %w", name, err)
+		}
+		next.Secrets[name], err = seal(aead, header.KeyVersion, name, secret)
+		clear(secret)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := v.save(next); err != nil {
+		return err
+	}
+	v.aead = aead
+	return nil
+}
+
+// Close locks the vault; it fails with ErrLocked until opened again
+func (v *Vault) Close() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.aead = nil
+}
+
+// save persists a new state and makes it current
+func (v *Vault) save(next *State) error {
+	if err := v.backend.Save(next); err != nil {
+		return fmt.Errorf("save vault: %w", err)
+	}
+	v.state = next
+	return nil
+}
+
+// newHeader derives a key from a passphrase with a fresh salt and returns the
+// header describing it together with the cipher using it
+func newHeader(passphrase []byte, params KDFParams, keyVersion int) (Header, cipher.AEAD, error) {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return Header{}, nil, err
+	}
+	aead, err := deriveAEAD(passphrase, salt, params)
+	if err != nil {
+		return Header{}, nil, err
+	}
+	check, err := seal(aead, keyVersion, "", []byte(checkPlaintext))
+	if err != nil {
+		return Header{}, nil, err
+	}
+	return Header{
+		FormatVersion: formatVersion,
+		Cipher:        CipherXChaCha20Poly1305,
+		KeyVersion:    keyVersion,
+		Salt:          salt,
+		KDF:           params,
+		Check:         check,
+	}, aead, nil
+}
+
+// deriveAEAD derives the vault key with argon2id and returns a cipher using it
+func deriveAEAD(passphrase, salt []byte, params KDFParams) (cipher.AEAD, error) {
+	if params.Time == 0 || params.MemoryKiB == 0 || params.Threads == 0 {
+		return nil, fmt.Errorf("invalid argon2id parameters %+v", params)
+	}
+	key := argon2.IDKey(passphrase, salt, params.Time, params.MemoryKiB, params.Threads, chacha20poly1305.KeySize)
+	defer clear(key)
+
+	// The cipher keeps its own copy of the key
+	return chacha20poly1305.NewX(key)
+}
+
+// seal encrypts a value stored under a name with a random nonce
+func seal(aead cipher.AEAD, keyVersion int, name string, plaintext []byte) (Record, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return Record{}, err
+	}
+	return Record{
+		KeyVersion: keyVersion,
+		Nonce:      nonce,
+		Ciphertext: aead.Seal(nil, nonce, plaintext, additionalData(keyVersion, name)),
+	}, nil
+}
+
+// open decrypts a value stored under a name
+func open(aead cipher.AEAD, name string, record Record) ([]byte, error) {
+	if len(record.Nonce) != aead.NonceSize() {
+		return nil, errors.New("invalid nonce")
+	}
+	return aead.Open(nil, record.Nonce, record.Ciphertext, additionalData(record.KeyVersion, name))
+}
+
+// additionalData binds a sealed value to the key version and its name. The
+// header check uses the empty name, which secrets cannot have.
+func additionalData(keyVersion int, name string) []byte {
+	data := []byte("shadow-id vault\x00")
+	data = strconv.AppendInt(data, int64(keyVersion), 10)
+	data = append(data, 0)
+	return append(data, name...)
+}
@@ -0,0 +1,181 @@
+package vault
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// testKDFParams keep key derivation fast in tests
+var testKDFParams = KDFParams{Time: 1, MemoryKiB: 64, Threads: 1}
+
+func newTestFileBackend(t *testing.T) *FileBackend {
+	t.Helper()
+	return NewFileBackend(filepath.Join(t.TempDir(), "vault.json"))
+}
+
+func TestPutGetAcrossReopen(t *testing.T) {
+	backend := newTestFileBackend(t)
+
+	v, err := Open(backend, []byte("correct horse"), testKDFParams)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if err := v.Put("device/1", []byte("private key")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	v.Close()
+	if _, err := v.Get("device/1"); !errors.Is(err, ErrLocked) {
+		t.Fatalf("Get() on locked vault error = %v, want %v", err, ErrLocked)
+	}
+
+	reopened, err := Open(backend, []byte("correct horse"), testKDFParams)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	secret, err := reopened.Get("device/1")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if string(secret) != "private key" {
+		t.Errorf("Get() = %q, want %q", secret, "private key")
+	}
+	if _, err := reopened.Get("device/2"); !errors.Is(err, ErrSecretNotFound) {
+		t.Errorf("Get() missing secret error = %v, want %v", err, ErrSecretNotFound)
+	}
+}
+
+func TestFileHoldsNoPlaintext(t *testing.T) {
+	backend := newTestFileBackend(t)
+
+	v, err := Open(backend, []byte("correct horse"), testKDFParams)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if err := v.Put("token", []byte("refresh-token-value")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	data, err := os.ReadFile(backend.Path())
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if bytes.Contains(data, []byte("refresh-token-value")) {
+		t.Error("vault file contains the secret in plaintext")
+	}
+	info, err := os.Stat(backend.Path())
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("vault file permissions = %o, want 600", perm)
+	}
+}
+
+func TestWrongPassphrase(t *testing.T) {
+	backend := NewMemoryBackend()
+
+	if _, err := Open(backend, []byte("correct horse"), testKDFParams); err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if _, err := Open(backend, []byte("battery staple"), testKDFParams); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("Open() error = %v, want %v", err, ErrWrongPassphrase)
+	}
+	if _, err := Open(backend, nil, testKDFParams); !errors.Is(err, ErrEmptyPassphrase) {
+		t.Errorf("Open() error = %v, want %v", err, ErrEmptyPassphrase)
+	}
+}
+
+func TestRotateReencryptsSecrets(t *testing.T) {
+	backend := newTestFileBackend(t)
+
+	v, err := Open(backend, []byte("old passphrase"), testKDFParams)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	secrets := map[string]string{"device/1": "first key", "device/2": "second key"}
+	for name, secret := range secrets {
+		if err := v.Put(name, []byte(secret)); err != nil {
+			t.Fatalf("Put() error = %v", err)
+		}
+	}
+	before, err := backend.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if err := v.Rotate([]byte("new passphrase"), testKDFParams); err != nil {
+		t.Fatalf("Rotate() error = %v", err)
+	}
+	if got := v.KeyVersion(); got != 2 {
+		t.Errorf("KeyVersion() = %d, want 2", got)
+	}
+
+	after, err := backend.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if bytes.Equal(before.Header.Salt, after.Header.Salt) {
+		t.Error("Rotate() kept the salt")
+	}
+	for name, record := range after.Secrets {
+		if record.KeyVersion != 2 {
+			t.Errorf("secret %q key version = %d, want 2", name, record.KeyVersion)
+		}
+		if bytes.Equal(record.Ciphertext, before.Secrets[name].Ciphertext) {
+			t.Errorf("secret %q was not re-encrypted", name)
+		}
+	}
+
+	if _, err := Open(backend, []byte("old passphrase"), testKDFParams); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("Open() with old passphrase error = %v, want %v", err, ErrWrongPassphrase)
+	}
+	reopened, err := Open(backend, []byte("new passphrase"), testKDFParams)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	for name, want := range secrets {
+		got, err := reopened.Get(name)
+		if err != nil {
+			t.Fatalf("Get(%q) error = %v", name, err)
+		}
+		if string(got) != want {
+			t.Errorf("Get(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestSwappedCiphertextIsRejected(t *testing.T) {
+	backend := NewMemoryBackend()
+
+	v, err := Open(backend, []byte("correct horse"), testKDFParams)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if err := v.Put("a", []byte("secret a")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if err := v.Put("b", []byte("secret b")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	// Store the record of "a" under "b"
+	state, err := backend.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	state.Secrets["b"] = state.Secrets["a"]
+	if err := backend.Save(state); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	reopened, err := Open(backend, []byte("correct horse"), testKDFParams)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if _, err := reopened.Get("b"); err == nil {
+		t.Error("Get() decrypted a record stored under another name")
+	}
+}
@@ -2,13 +2,32 @@ package wails
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"strconv"
 
 	"shadow-id/internal/app/commands"
+	"shadow-id/internal/app/pipeline"
 	"shadow-id/internal/app/queries"
 	"shadow-id/internal/app/services"
+	"shadow-id/internal/domain/entities"
+	"shadow-id/internal/domain/repositories"
 	"shadow-id/internal/infra/config"
+	"shadow-id/internal/infra/eventbus"
+	"shadow-id/internal/infra/fingerprint"
+	"shadow-id/internal/infra/httpapi"
+	"shadow-id/internal/infra/jobs"
+	"shadow-id/internal/infra/outbox"
+	"shadow-id/internal/infra/policy"
+	"shadow-id/internal/infra/projection"
+	"shadow-id/internal/infra/ratelimit"
 	infraservices "shadow-id/internal/infra/services"
+	"shadow-id/internal/infra/storage/eventsourced"
 	"shadow-id/internal/infra/storage/memory"
+	"shadow-id/internal/infra/vault"
+	"shadow-id/internal/infra/webauthn"
 	"shadow-id/pkg/logger"
 	"shadow-id/pkg/types"
 )
@@ -19,6 +38,26 @@ type App struct {
 	config *config.Config
 	logger logger.Logger
 
+	// Signed-in principal of the desktop session
+	session *session
+
+	// Private keys of the devices registered through this installation
+	deviceKeys *deviceKeyring
+
+	// In-process domain event bus, fed by the outbox relay
+	eventBus    *eventbus.Bus
+	outboxRelay *outbox.Relay
+
+	// Read models updated from domain events
+	projections *projection.Manager
+
+	// Background jobs
+	userPurger    *jobs.UserPurger
+	deviceSweeper *jobs.DeviceSweeper
+
+	// Optional HTTP API serving the same commands and queries
+	apiServer *httpapi.Server
+
 	// Application services
 	appService *services.ApplicationService
 }
@@ -35,63 +74,307 @@ func NewApp() (*App, error) {
 	appLogger := logger.New(cfg.LogLevel)
 
 	// Initialize repositories
-	userRepo := memory.NewUserRepository()
+	unitOfWork := memory.NewUnitOfWork()
+	outboxRepo := memory.NewOutboxRepository()
+	userRepo, userEventStore, err := newUserRepository(cfg.Database, outboxRepo, unitOfWork)
+	if err != nil {
+		return nil, err
+	}
+	twoFactorRepo := memory.NewTwoFactorRepository()
+	deviceRepo := memory.NewDeviceRepository(outboxRepo)
+	credentialRepo := memory.NewWebAuthnCredentialRepository()
+	webauthnSessionRepo := memory.NewWebAuthnSessionRepository()
+	deviceChallengeRepo := memory.NewDeviceChallengeRepository()
+	loginThrottleRepo := memory.NewLoginThrottleRepository()
+	roleRepo := memory.NewRoleRepository()
+	auditRepo := memory.NewAuditRepository()
+	userSummaryRepo := memory.NewUserSummaryRepository()
+	userTimelineRepo := memory.NewUserTimelineRepository()
+
+	// Failed sign-in attempts, WebAuthn ceremonies and device challenges must
+	// survive the rollback of the command that used them, so they stay out of
+	// transactions
+	unitOfWork.Enlist(outboxRepo, twoFactorRepo, deviceRepo, credentialRepo, roleRepo, auditRepo)
 
 	// Initialize domain services
 	userService := infraservices.NewUserService(userRepo)
+	totpService := infraservices.NewTOTPService(infraservices.TOTPConfig{
+		Issuer:            cfg.Security.TOTPIssuer,
+		Digits:            cfg.Security.TOTPDigits,
+		Period:            cfg.Security.TOTPPeriod,
+		Skew:              cfg.Security.TOTPSkew,
+		RecoveryCodeCount: cfg.Security.RecoveryCodeCount,
+	})
+	fingerprintSalt := cfg.Security.FingerprintSalt
+	if fingerprintSalt == "" {
+		fingerprintSalt, err = fingerprint.LoadOrCreateSalt(cfg.Security.FingerprintSaltPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+	deviceService := infraservices.NewDeviceService(fingerprintSalt, fingerprint.DefaultSources())
+	deviceKeyService := infraservices.NewDeviceKeyService()
+	fingerprintPolicy := entities.DefaultFingerprintMatchPolicy()
+	fingerprintPolicy.MatchThreshold = cfg.Security.FingerprintMatchThreshold
+	fingerprintPolicy.SuspicionThreshold = cfg.Security.FingerprintSuspicionThreshold
+	deviceQuotaPolicy := entities.DeviceQuotaPolicy{
+		DefaultLimit:        cfg.DeviceQuota.DefaultLimit,
+		RoleLimits:          cfg.DeviceQuota.RoleLimits,
+		UserLimits:          make(map[types.ID]int, len(cfg.DeviceQuota.UserLimits)),
+		EvictOldestInactive: cfg.DeviceQuota.EvictOldestInactive,
+		InactiveAfter:       cfg.DeviceQuota.InactiveAfter,
+	}
+	for userID, limit := range cfg.DeviceQuota.UserLimits {
+		deviceQuotaPolicy.UserLimits[types.ID(userID)] = limit
+	}
+	relyingParty := webauthn.NewRelyingParty(webauthn.Config{
+		RPID:                    cfg.Security.WebAuthnRPID,
+		RPName:                  cfg.Security.WebAuthnRPName,
+		Origins:                 cfg.Security.WebAuthnOrigins,
+		TimeoutSeconds:          cfg.Security.WebAuthnTimeout,
+		RequireUserVerification: cfg.Security.WebAuthnRequireUserVerification,
+	})
+	rateLimiter := ratelimit.NewTokenBucket(ratelimit.TokenBucketConfig{
+		Capacity:       cfg.Security.RateLimitBurst,
+		RefillInterval: cfg.Security.RateLimitRefillRate,
+	})
+	authGuard := infraservices.NewAuthenticationGuard(loginThrottleRepo, rateLimiter, entities.LockoutPolicy{
+		MaxAttempts:     cfg.Security.LockoutMaxAttempts,
+		LockoutDuration: cfg.Security.LockoutDuration,
+		BaseDelay:       cfg.Security.BackoffBaseDelay,
+		MaxDelay:        cfg.Security.BackoffMaxDelay,
+	})
+
+	policies, err := policy.LoadFile(cfg.Security.PolicyFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		appLogger.Warn("Policy file not found, only role checks and ownership rules apply", "path", cfg.Security.PolicyFile)
+	} else if err != nil {
+		return nil, err
+	}
+	policyEngine := policy.NewEngine(policies, cfg.Security.PolicyDryRun, appLogger)
+	eventBus := eventbus.NewBus(eventbus.Config{
+		Workers:   cfg.Events.Workers,
+		QueueSize: cfg.Events.QueueSize,
+	}, appLogger)
+	outboxRelay := outbox.NewRelay(outboxRepo, eventBus, outbox.Config{
+		PollInterval: cfg.Events.OutboxPollInterval,
+		BatchSize:    cfg.Events.OutboxBatchSize,
+		Policy: entities.DeliveryPolicy{
+			MaxAttempts: cfg.Events.OutboxMaxAttempts,
+			BaseDelay:   cfg.Events.OutboxRetryBaseDelay,
+			MaxDelay:    cfg.Events.OutboxRetryMaxDelay,
+		},
+	}, appLogger)
+	projections := projection.NewManager(outboxRepo, appLogger,
+		projection.NewUserSummaryProjection(userSummaryRepo),
+		projection.NewUserTimelineProjection(userTimelineRepo),
+	)
 
 	// Initialize application services
-	appService := services.NewApplicationService(userRepo, userService)
+	appService, err := services.NewApplicationService(services.Dependencies{
+		UserRepo:            userRepo,
+		UserEventStore:      userEventStore,
+		TwoFactorRepo:       twoFactorRepo,
+		DeviceRepo:          deviceRepo,
+		DeviceChallengeRepo: deviceChallengeRepo,
+		CredentialRepo:      credentialRepo,
+		WebAuthnSessionRepo: webauthnSessionRepo,
+		LoginThrottleRepo:   loginThrottleRepo,
+		RoleRepo:            roleRepo,
+		AuditRepo:           auditRepo,
+		UserSummaryRepo:     userSummaryRepo,
+		UserTimelineRepo:    userTimelineRepo,
+		UnitOfWork:          unitOfWork,
+		UserService:         userService,
+		TOTPService:         totpService,
+		DeviceService:       deviceService,
+		DeviceKeyService:    deviceKeyService,
+		WebAuthnService:     relyingParty,
+		RateLimiter:         rateLimiter,
+		AuthenticationGuard: authGuard,
+		PolicyEngine:        policyEngine,
+		ProjectionRebuilder: projections,
+		Logger:              appLogger,
+		HandlerTimeout:      cfg.HandlerTimeout,
+		UserRetention:       cfg.Jobs.UserRetention,
+		FingerprintPolicy:   fingerprintPolicy,
+		DeviceQuotaPolicy:   deviceQuotaPolicy,
+		DeviceChallengeTTL:  cfg.Security.DeviceChallengeTTL,
+		DeviceStaleAfter:    cfg.Jobs.DeviceStaleAfter,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Device private keys are kept in the encrypted local vault
+	deviceKeys := newDeviceKeyring(deviceKeyService, vault.NewFileBackend(cfg.Vault.Path), vault.KDFParams{
+		Time:      cfg.Vault.KDFTime,
+		MemoryKiB: cfg.Vault.KDFMemoryKiB,
+		Threads:   cfg.Vault.KDFThreads,
+	})
 
-	return &App{
-		config:     cfg,
-		logger:     appLogger,
+	app := &App{
+		config:      cfg,
+		logger:      appLogger,
+		session:     newSession(userRepo),
+		deviceKeys:  deviceKeys,
+		eventBus:    eventBus,
+		outboxRelay: outboxRelay,
+		projections: projections,
+		userPurger: jobs.NewUserPurger(appService.Bus, jobs.UserPurgerConfig{
+			Interval:  cfg.Jobs.UserPurgeInterval,
+			BatchSize: cfg.Jobs.UserPurgeBatchSize,
+		}, appLogger),
+		deviceSweeper: jobs.NewDeviceSweeper(appService.Bus, jobs.DeviceSweeperConfig{
+			Interval:  cfg.Jobs.DeviceSweepInterval,
+			BatchSize: cfg.Jobs.DeviceSweepBatchSize,
+		}, appLogger),
 		appService: appService,
-	}, nil
+	}
+	app.subscribeEvents()
+
+	if cfg.Server.Enabled {
+		apiTokens := make(map[string]types.ID, len(cfg.Server.APITokens))
+		for token, userID := range cfg.Server.APITokens {
+			apiTokens[token] = types.ID(userID)
+		}
+		app.apiServer = httpapi.NewServer(appService.Bus, httpapi.Config{
+			Addr:            net.JoinHostPort(cfg.Server.Host, strconv.Itoa(cfg.Server.Port)),
+			ReadTimeout:     cfg.Server.ReadTimeout,
+			WriteTimeout:    cfg.Server.WriteTimeout,
+			IdleTimeout:     cfg.Server.IdleTimeout,
+			ShutdownTimeout: cfg.Server.ShutdownTimeout,
+			Tokens:          apiTokens,
+			AnonymousRateLimit: ratelimit.TokenBucketConfig{
+				Capacity:       cfg.Server.AnonymousRateLimitBurst,
+				RefillInterval: cfg.Server.AnonymousRateLimitRefillRate,
+			},
+		}, appLogger)
+	}
+
+	// Unlock the vault right away if the passphrase was configured
+	if cfg.Vault.Passphrase != "" {
+		if err := app.deviceKeys.unlock([]byte(cfg.Vault.Passphrase)); err != nil {
+			return nil, err
+		}
+	}
+
+	return app, nil
+}
+
+// newUserRepository creates the user storage the configuration selects and
+// enlists it in the unit of work. The event store is nil unless users are
+// event sourced.
+func newUserRepository(cfg config.DatabaseConfig, outboxRepo *memory.OutboxRepository, unitOfWork *memory.UnitOfWork) (repositories.UserRepository, repositories.EventStore, error) {
+	switch cfg.UserStorage {
+	case config.UserStorageState:
+		userRepo := memory.NewUserRepository(outboxRepo)
+		unitOfWork.Enlist(userRepo)
+		return userRepo, nil, nil
+	case config.UserStorageEventSourced:
+		eventStore := memory.NewEventStore(outboxRepo)
+		readModel := memory.NewUserRepository(outboxRepo)
+		unitOfWork.Enlist(eventStore, readModel)
+		return eventsourced.NewUserRepository(eventStore, readModel, cfg.UserSnapshotEvery), eventStore, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown user storage %q", cfg.UserStorage)
+	}
 }
 
 // Startup is called when the app starts. The context is saved
 // so we can call the runtime methods
 func (a *App) Startup(ctx context.Context) {
 	a.ctx = ctx
+	a.outboxRelay.Start(ctx)
+	a.userPurger.Start(ctx)
+	a.deviceSweeper.Start(ctx)
+	if a.apiServer != nil {
+		if err := a.apiServer.Start(); err != nil {
+			a.logger.Error("Failed to start HTTP API server", "error", err)
+		}
+	}
 	a.logger.Info("Application started successfully")
 }
 
+// Shutdown is called when the app is closing. HTTP API requests in flight
+// finish first, then pending outbox entries get a final delivery pass and
+// queued event deliveries are drained before it returns.
+func (a *App) Shutdown(ctx context.Context) {
+	if a.apiServer != nil {
+		if err := a.apiServer.Shutdown(ctx); err != nil {
+			a.logger.Error("Failed to stop HTTP API server", "error", err)
+		}
+	}
+	a.userPurger.Stop()
+	a.deviceSweeper.Stop()
+	a.outboxRelay.Stop(ctx)
+	a.eventBus.Close()
+	a.deviceKeys.lock()
+	a.logger.Info("Application stopped")
+}
+
 // Greet returns a greeting for the given name
 func (a *App) Greet(name string) string {
 	a.logger.Info("Greet method called", "name", name)
 	return "Hello " + name + ", It's show time!"
 }
 
-// CreateUser creates a new user
-func (a *App) CreateUser(name, email string) (*commands.CreateUserResult, error) {
-	a.logger.Info("CreateUser method called", "name", name, "email", email)
+// CreateUser creates a new user, registering their first device too when a
+// device name is given
+func (a *App) CreateUser(name, email, deviceName, devicePlatform string) (*commands.CreateUserResult, error) {
+	a.logger.Info("CreateUser method called", "name", name, "email", email, "device_name", deviceName)
 
 	cmd := commands.CreateUserCommand{
-		Name:  name,
-		Email: email,
+		Name:           name,
+		Email:          email,
+		DeviceName:     deviceName,
+		DevicePlatform: devicePlatform,
+	}
+	var devicePrivateKey []byte
+	if deviceName != "" {
+		var err error
+		if cmd.DevicePublicKey, devicePrivateKey, err = a.newDeviceKey(); err != nil {
+			return nil, err
+		}
+		defer clear(devicePrivateKey)
 	}
 
-	result, err := a.appService.Commands.CreateUser.Handle(a.ctx, cmd)
+	bootstrap := a.GetSession().Bootstrap
+
+	result, err := pipeline.Send[*commands.CreateUserResult](a.requestContext(), a.appService.Bus, cmd)
 	if err != nil {
 		a.logger.Error("Failed to create user", "error", err)
 		return nil, err
 	}
 
+	// The first device's private key stays with this installation
+	if !result.DeviceID.IsEmpty() {
+		if err := a.keepDeviceKey(result.DeviceID, devicePrivateKey); err != nil {
+			return nil, err
+		}
+	}
+
+	// The first user becomes the administrator and takes over the session
+	if bootstrap {
+		a.session.signIn(result.ID)
+	}
+
 	a.logger.Info("User created successfully", "id", result.ID)
 	return result, nil
 }
 
-// GetUser retrieves a user by ID
-func (a *App) GetUser(id string) (*queries.GetUserResult, error) {
-	a.logger.Info("GetUser method called", "id", id)
+// GetUser retrieves a user by ID; soft-deleted users are only found if
+// includeDeleted is set
+func (a *App) GetUser(id string, includeDeleted bool) (*queries.GetUserResult, error) {
+	a.logger.Info("GetUser method called", "id", id, "include_deleted", includeDeleted)
 
 	userID := types.ID(id)
 	query := queries.GetUserQuery{
-		ID: userID,
+		ID:             userID,
+		IncludeDeleted: includeDeleted,
 	}
 
-	result, err := a.appService.Queries.GetUser.Handle(a.ctx, query)
+	result, err := pipeline.Send[*queries.GetUserResult](a.requestContext(), a.appService.Bus, query)
 	if err != nil {
 		a.logger.Error("Failed to get user", "error", err)
 		return nil, err
@@ -101,6 +384,141 @@ func (a *App) GetUser(id string) (*queries.GetUserResult, error) {
 	return result, nil
 }
 
+// GetUserHistory replays a user's events up to a version; zero replays all of them
+func (a *App) GetUserHistory(id string, version int64) (*queries.GetUserHistoryResult, error) {
+	a.logger.Info("GetUserHistory method called", "id", id, "version", version)
+
+	query := queries.GetUserHistoryQuery{
+		UserID:  types.ID(id),
+		Version: version,
+	}
+
+	result, err := pipeline.Send[*queries.GetUserHistoryResult](a.requestContext(), a.appService.Bus, query)
+	if err != nil {
+		a.logger.Error("Failed to get user history", "error", err)
+		return nil, err
+	}
+
+	a.logger.Info("User history replayed", "id", result.UserID, "version", result.Version)
+	return result, nil
+}
+
+// GetUserTimeline retrieves a page of what a user did from which device,
+// newest first. The device and actions narrow the entries when set.
+func (a *App) GetUserTimeline(userID, deviceID string, actions []string, limit, offset int) (*queries.GetUserTimelineResult, error) {
+	a.logger.Info("GetUserTimeline method called", "user_id", userID, "device_id", deviceID, "actions", actions)
+
+	query := queries.GetUserTimelineQuery{
+		UserID:   types.ID(userID),
+		DeviceID: types.ID(deviceID),
+		Actions:  actions,
+		Limit:    limit,
+		Offset:   offset,
+	}
+
+	result, err := pipeline.Send[*queries.GetUserTimelineResult](a.requestContext(), a.appService.Bus, query)
+	if err != nil {
+		a.logger.Error("Failed to get user timeline", "error", err)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// UpdateUser updates a user's name and email; empty values are left
+// unchanged. A non-zero version rejects the update if the user changed since.
+func (a *App) UpdateUser(id, name, email string, version int64) (*commands.UpdateUserResult, error) {
+	a.logger.Info("UpdateUser method called", "id", id, "version", version)
+
+	cmd := commands.UpdateUserCommand{
+		ID:      types.ID(id),
+		Name:    name,
+		Email:   email,
+		Version: version,
+	}
+
+	result, err := pipeline.Send[*commands.UpdateUserResult](a.requestContext(), a.appService.Bus, cmd)
+	if err != nil {
+		a.logger.Error("Failed to update user", "error", err)
+		return nil, err
+	}
+
+	a.logger.Info("User updated successfully", "id", result.ID)
+	return result, nil
+}
+
+// DeleteUser soft-deletes a user; they can be restored until the retention
+// period ends. A non-zero version rejects the deletion if the user changed since.
+func (a *App) DeleteUser(id string, version int64) (*commands.DeleteUserResult, error) {
+	a.logger.Info("DeleteUser method called", "id", id, "version", version)
+
+	cmd := commands.DeleteUserCommand{
+		ID:      types.ID(id),
+		Version: version,
+	}
+
+	result, err := pipeline.Send[*commands.DeleteUserResult](a.requestContext(), a.appService.Bus, cmd)
+	if err != nil {
+		a.logger.Error("Failed to delete user", "error", err)
+		return nil, err
+	}
+
+	a.logger.Info("User deleted successfully", "id", result.ID)
+	return result, nil
+}
+
+// RestoreUser brings back a soft-deleted user. A non-zero version rejects
+// the restoration if the user changed since.
+func (a *App) RestoreUser(id string, version int64) (*commands.RestoreUserResult, error) {
+	a.logger.Info("RestoreUser method called", "id", id, "version", version)
+
+	cmd := commands.RestoreUserCommand{
+		ID:      types.ID(id),
+		Version: version,
+	}
+
+	result, err := pipeline.Send[*commands.RestoreUserResult](a.requestContext(), a.appService.Bus, cmd)
+	if err != nil {
+		a.logger.Error("Failed to restore user", "error", err)
+		return nil, err
+	}
+
+	a.logger.Info("User restored successfully", "id", result.ID)
+	return result, nil
+}
+
+// PurgeDeletedUsers hard-deletes users past their retention period now
+// instead of waiting for the background job
+func (a *App) PurgeDeletedUsers() (*commands.PurgeDeletedUsersResult, error) {
+	a.logger.Info("PurgeDeletedUsers method called")
+
+	cmd := commands.PurgeDeletedUsersCommand{
+		Limit: a.config.Jobs.UserPurgeBatchSize,
+	}
+
+	result, err := pipeline.Send[*commands.PurgeDeletedUsersResult](a.requestContext(), a.appService.Bus, cmd)
+	if err != nil {
+		a.logger.Error("Failed to purge deleted users", "error", err)
+		return nil, err
+	}
+
+	a.logger.Info("Deleted users purged", "count", result.Purged)
+	return result, nil
+}
+
+// GetHandlerMetrics returns call statistics for every command and query handler
+func (a *App) GetHandlerMetrics() (*queries.GetHandlerMetricsResult, error) {
+	a.logger.Info("GetHandlerMetrics method called")
+
+	result, err := pipeline.Send[*queries.GetHandlerMetricsResult](a.requestContext(), a.appService.Bus, queries.GetHandlerMetricsQuery{})
+	if err != nil {
+		a.logger.Error("Failed to get handler metrics", "error", err)
+		return nil, err
+	}
+
+	return result, nil
+}
+
 // GetAppInfo returns application information
 func (a *App) GetAppInfo() map[string]interface{} {
 	return map[string]interface{}{
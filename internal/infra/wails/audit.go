@@ -0,0 +1,38 @@
+package wails
+
+import (
+	"shadow-id/internal/app/pipeline"
+	"shadow-id/internal/app/queries"
+)
+
+// ListAuditLog lists audit entries, optionally for a single target or actor
+func (a *App) ListAuditLog(query queries.ListAuditLogQuery) (*queries.ListAuditLogResult, error) {
+	a.logger.Info("ListAuditLog method called", "target_id", query.TargetID, "actor_id", query.ActorID)
+
+	result, err := pipeline.Send[*queries.ListAuditLogResult](a.requestContext(), a.appService.Bus, query)
+	if err != nil {
+		a.logger.Error("Failed to list audit log", "error", err)
+		return nil, err
+	}
+
+	a.logger.Info("Audit log listed", "count", len(result.Entries))
+	return result, nil
+}
+
+// VerifyAuditLog checks the audit log's hash chain for tampering
+func (a *App) VerifyAuditLog() (*queries.VerifyAuditLogResult, error) {
+	a.logger.Info("VerifyAuditLog method called")
+
+	result, err := pipeline.Send[*queries.VerifyAuditLogResult](a.requestContext(), a.appService.Bus, queries.VerifyAuditLogQuery{})
+	if err != nil {
+		a.logger.Error("Failed to verify audit log", "error", err)
+		return nil, err
+	}
+
+	if !result.Valid {
+		a.logger.Warn("Audit log failed verification", "broken_at", result.BrokenAt, "reason", result.Reason)
+		return result, nil
+	}
+	a.logger.Info("Audit log verified", "entries", result.Entries)
+	return result, nil
+}
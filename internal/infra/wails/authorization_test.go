@@ -0,0 +1,340 @@
+package wails
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"path/filepath"
+	"testing"
+
+	"shadow-id/internal/app/auth"
+	"shadow-id/internal/app/commands"
+	"shadow-id/internal/app/queries"
+	"shadow-id/pkg/errors"
+	"shadow-id/pkg/types"
+)
+
+// testUsers are the accounts every authorization test starts with
+type testUsers struct {
+	admin types.ID
+	alice types.ID
+	bob   types.ID
+}
+
+// newTestApp creates an application enforcing the shipped policies, with an
+// administrator and two regular users
+func newTestApp(t *testing.T) (*App, testUsers) {
+	t.Helper()
+	return newTestAppWithPolicies(t, filepath.Join("..", "..", "..", "configs", "policies.json"))
+}
+
+// newTestAppWithPolicies creates the application of newTestApp loading the
+// policy file at the path
+func newTestAppWithPolicies(t *testing.T, policyFile string) (*App, testUsers) {
+	t.Helper()
+	t.Setenv("POLICY_FILE", policyFile)
+	dataDir := t.TempDir()
+	t.Setenv("VAULT_PATH", filepath.Join(dataDir, "vault.json"))
+	t.Setenv("FINGERPRINT_SALT_PATH", filepath.Join(dataDir, "fingerprint.salt"))
+
+	app, err := NewApp()
+	if err != nil {
+		t.Fatalf("NewApp() error = %v", err)
+	}
+	app.ctx = context.Background()
+	t.Cleanup(func() { app.Shutdown(context.Background()) })
+
+	var users testUsers
+	admin, err := app.CreateUser("Admin", "admin@example.com", "", "")
+	if err != nil {
+		t.Fatalf("CreateUser() bootstrap error = %v", err)
+	}
+	users.admin = admin.ID
+
+	app.session.signIn(users.admin)
+	for _, user := range []struct {
+		id    *types.ID
+		name  string
+		email string
+	}{
+		{&users.alice, "Alice", "alice@example.com"},
+		{&users.bob, "Bob", "bob@example.com"},
+	} {
+		created, err := app.CreateUser(user.name, user.email, "", "")
+		if err != nil {
+			t.Fatalf("CreateUser(%s) error = %v", user.name, err)
+		}
+		*user.id = created.ID
+	}
+	app.session.signOut()
+
+	return app, users
+}
+
+// as returns a context acting as the user
+func as(userID types.ID) context.Context {
+	return auth.WithPrincipal(context.Background(), auth.Principal{UserID: userID})
+}
+
+// devicePublicKey returns the base64url encoded public key of a new device
+// key pair, as a client registering a device sends it
+func devicePublicKey(t *testing.T) string {
+	t.Helper()
+	publicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(publicKey)
+}
+
+// dispatch sends a command or query through the application's bus
+func dispatch(app *App, ctx context.Context, msg interface{}) error {
+	_, err := app.appService.Bus.Dispatch(ctx, msg)
+	return err
+}
+
+func TestCredentialsAreManagedByTheirOwner(t *testing.T) {
+	app, users := newTestApp(t)
+
+	crossUser := []struct {
+		name string
+		msg  interface{}
+	}{
+		{"EnrollTwoFactor", commands.EnrollTwoFactorCommand{UserID: users.alice}},
+		{"ConfirmTwoFactor", commands.ConfirmTwoFactorCommand{UserID: users.alice, Code: "123456"}},
+		{"DisableTwoFactor", commands.DisableTwoFactorCommand{UserID: users.alice, Code: "123456"}},
+		{"RegenerateRecoveryCodes", commands.RegenerateRecoveryCodesCommand{UserID: users.alice, Code: "123456"}},
+		{"BeginPasskeyRegistration", commands.BeginPasskeyRegistrationCommand{UserID: users.alice}},
+	}
+	for _, tt := range crossUser {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := dispatch(app, as(users.bob), tt.msg); !errors.IsForbiddenError(err) {
+				t.Errorf("%s for another user error = %v, want forbidden", tt.name, err)
+			}
+		})
+	}
+
+	// Owners and administrators are not affected
+	if err := dispatch(app, as(users.alice), commands.EnrollTwoFactorCommand{UserID: users.alice}); err != nil {
+		t.Errorf("EnrollTwoFactor for self error = %v", err)
+	}
+	if err := dispatch(app, as(users.admin), commands.BeginPasskeyRegistrationCommand{UserID: users.bob}); err != nil {
+		t.Errorf("BeginPasskeyRegistration by admin error = %v", err)
+	}
+}
+
+func TestCredentialOwnershipHoldsWithoutPolicyFile(t *testing.T) {
+	t.Setenv("POLICY_DRY_RUN", "true")
+	app, users := newTestAppWithPolicies(t, filepath.Join(t.TempDir(), "missing.json"))
+
+	crossUser := []struct {
+		name string
+		msg  interface{}
+	}{
+		{"EnrollTwoFactor", commands.EnrollTwoFactorCommand{UserID: users.alice}},
+		{"DisableTwoFactor", commands.DisableTwoFactorCommand{UserID: users.alice, Code: "123456"}},
+		{"BeginPasskeyRegistration", commands.BeginPasskeyRegistrationCommand{UserID: users.alice}},
+	}
+	for _, tt := range crossUser {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := dispatch(app, as(users.bob), tt.msg); !errors.IsForbiddenError(err) {
+				t.Errorf("%s for another user error = %v, want forbidden", tt.name, err)
+			}
+		})
+	}
+
+	if err := dispatch(app, as(users.admin), commands.EnrollTwoFactorCommand{UserID: users.bob}); err != nil {
+		t.Errorf("EnrollTwoFactor by admin error = %v", err)
+	}
+}
+
+func TestAccountOwnershipHoldsWithoutPolicyFile(t *testing.T) {
+	t.Setenv("POLICY_DRY_RUN", "true")
+	app, users := newTestAppWithPolicies(t, filepath.Join(t.TempDir(), "missing.json"))
+
+	registered, err := app.appService.Bus.Dispatch(as(users.alice), commands.RegisterDeviceCommand{UserID: users.alice, Name: "Laptop", PublicKey: devicePublicKey(t)})
+	if err != nil {
+		t.Fatalf("RegisterDevice() error = %v", err)
+	}
+	deviceID := registered.(*commands.RegisterDeviceResult).ID
+
+	crossUser := []struct {
+		name string
+		msg  interface{}
+	}{
+		{"UpdateUser", commands.UpdateUserCommand{ID: users.alice, Name: "Mallory"}},
+		{"RegisterDevice", commands.RegisterDeviceCommand{UserID: users.alice, Name: "Phone", PublicKey: devicePublicKey(t)}},
+		{"MatchDevice", commands.MatchDeviceCommand{UserID: users.alice, Fingerprint: map[string]string{"hostname": "a1b2c3"}}},
+		{"RevokeDevice", commands.RevokeDeviceCommand{DeviceID: deviceID}},
+		{"RecordDeviceHeartbeat", commands.RecordDeviceHeartbeatCommand{DeviceID: deviceID}},
+	}
+	for _, tt := range crossUser {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := dispatch(app, as(users.bob), tt.msg); !errors.IsForbiddenError(err) {
+				t.Errorf("%s for another user error = %v, want forbidden", tt.name, err)
+			}
+		})
+	}
+
+	// Owners and administrators are not affected
+	if err := dispatch(app, as(users.alice), commands.RecordDeviceHeartbeatCommand{DeviceID: deviceID}); err != nil {
+		t.Errorf("RecordDeviceHeartbeat by owner error = %v", err)
+	}
+	if err := dispatch(app, as(users.admin), commands.RevokeDeviceCommand{DeviceID: deviceID}); err != nil {
+		t.Errorf("RevokeDevice by admin error = %v", err)
+	}
+}
+
+func TestPasskeyCeremonyCannotBeFinishedByAnotherUser(t *testing.T) {
+	app, users := newTestApp(t)
+
+	begun, err := app.appService.Bus.Dispatch(as(users.alice), commands.BeginPasskeyRegistrationCommand{UserID: users.alice})
+	if err != nil {
+		t.Fatalf("BeginPasskeyRegistration() error = %v", err)
+	}
+	sessionID := begun.(*commands.BeginPasskeyRegistrationResult).SessionID
+
+	err = dispatch(app, as(users.bob), commands.FinishPasskeyRegistrationCommand{
+		SessionID:         sessionID,
+		ClientDataJSON:    "e30",
+		AttestationObject: "oA",
+	})
+	if !errors.IsForbiddenError(err) {
+		t.Fatalf("FinishPasskeyRegistration by another user error = %v, want forbidden", err)
+	}
+
+	// The denied attempt does not consume the owner's ceremony
+	err = dispatch(app, as(users.alice), commands.FinishPasskeyRegistrationCommand{
+		SessionID:         sessionID,
+		ClientDataJSON:    "e30",
+		AttestationObject: "oA",
+	})
+	if errors.IsNotFoundError(err) || errors.IsForbiddenError(err) {
+		t.Fatalf("FinishPasskeyRegistration by owner error = %v, want the ceremony to still exist", err)
+	}
+}
+
+func TestDevicesAreRevokedByOwnerOrAdministrator(t *testing.T) {
+	app, users := newTestApp(t)
+
+	register := func(name string) types.ID {
+		t.Helper()
+		result, err := app.appService.Bus.Dispatch(as(users.alice), commands.RegisterDeviceCommand{UserID: users.alice, Name: name, PublicKey: devicePublicKey(t)})
+		if err != nil {
+			t.Fatalf("RegisterDevice() error = %v", err)
+		}
+		return result.(*commands.RegisterDeviceResult).ID
+	}
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		allowed bool
+	}{
+		{"other user", as(users.bob), false},
+		{"owner", as(users.alice), true},
+		{"administrator", as(users.admin), true},
+		{"system", auth.WithPrincipal(context.Background(), auth.SystemPrincipal()), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := dispatch(app, tt.ctx, commands.RevokeDeviceCommand{DeviceID: register("Laptop")})
+			if tt.allowed && err != nil {
+				t.Errorf("RevokeDevice() error = %v", err)
+			}
+			if !tt.allowed && !errors.IsForbiddenError(err) {
+				t.Errorf("RevokeDevice() error = %v, want forbidden", err)
+			}
+		})
+	}
+}
+
+func TestActionsWithoutUserAreAttributedToTheirActor(t *testing.T) {
+	app, users := newTestApp(t)
+
+	system := auth.WithPrincipal(context.Background(), auth.SystemPrincipal())
+	if err := dispatch(app, system, commands.UpdateUserCommand{ID: users.alice, Name: "Alice Liddell"}); err != nil {
+		t.Fatalf("UpdateUser() error = %v", err)
+	}
+
+	tests := []struct {
+		actor types.ID
+		want  string
+	}{
+		{auth.ActorBootstrap, "CreateUser"},
+		{auth.ActorSystem, "UpdateUser"},
+	}
+	for _, tt := range tests {
+		result, err := app.appService.Bus.Dispatch(as(users.admin), queries.ListAuditLogQuery{ActorID: tt.actor})
+		if err != nil {
+			t.Fatalf("ListAuditLog() error = %v", err)
+		}
+		entries := result.(*queries.ListAuditLogResult).Entries
+		if len(entries) != 1 || entries[0].Action != tt.want {
+			t.Errorf("entries of %q = %+v, want one %s", tt.actor, entries, tt.want)
+		}
+	}
+}
+
+func TestDevicesAreMatchedForTheirOwner(t *testing.T) {
+	app, users := newTestApp(t)
+
+	fingerprint := map[string]string{"hostname": "a1b2c3"}
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		allowed bool
+	}{
+		{"other user", as(users.bob), false},
+		{"owner", as(users.alice), true},
+		{"administrator", as(users.admin), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := dispatch(app, tt.ctx, commands.MatchDeviceCommand{UserID: users.alice, Fingerprint: fingerprint})
+			if tt.allowed && err != nil {
+				t.Errorf("MatchDevice() error = %v", err)
+			}
+			if !tt.allowed && !errors.IsForbiddenError(err) {
+				t.Errorf("MatchDevice() error = %v, want forbidden", err)
+			}
+		})
+	}
+}
+
+func TestDevicesAreRegisteredByTheirOwner(t *testing.T) {
+	t.Setenv("DEVICE_QUOTA_DEFAULT_LIMIT", "1")
+	t.Setenv("DEVICE_QUOTA_EVICT_OLDEST_INACTIVE", "true")
+	t.Setenv("DEVICE_QUOTA_INACTIVE_AFTER", "1ns")
+	app, users := newTestApp(t)
+
+	register := func(ctx context.Context) (*commands.RegisterDeviceResult, error) {
+		result, err := app.appService.Bus.Dispatch(ctx, commands.RegisterDeviceCommand{UserID: users.alice, Name: "Laptop", PublicKey: devicePublicKey(t)})
+		if err != nil {
+			return nil, err
+		}
+		return result.(*commands.RegisterDeviceResult), nil
+	}
+	owned, err := register(as(users.alice))
+	if err != nil {
+		t.Fatalf("RegisterDevice() error = %v", err)
+	}
+
+	// Another user can neither register a device nor evict the owner's
+	if _, err := register(as(users.bob)); !errors.IsForbiddenError(err) {
+		t.Fatalf("RegisterDevice() for another user error = %v, want forbidden", err)
+	}
+	devices, err := app.appService.Bus.Dispatch(as(users.alice), queries.ListUserDevicesQuery{UserID: users.alice})
+	if err != nil {
+		t.Fatalf("ListUserDevices() error = %v", err)
+	}
+	if got := devices.(*queries.ListUserDevicesResult).Devices; len(got) != 1 || got[0].ID != owned.ID {
+		t.Errorf("devices after a denied registration = %+v, want only %s", got, owned.ID)
+	}
+
+	// Administrators may register on the user's behalf
+	if _, err := register(as(users.admin)); err != nil {
+		t.Errorf("RegisterDevice() by admin error = %v", err)
+	}
+}
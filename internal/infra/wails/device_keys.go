@@ -0,0 +1,173 @@
+package wails
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"sync"
+
+	"shadow-id/internal/domain/services"
+	"shadow-id/internal/infra/vault"
+	"shadow-id/pkg/types"
+)
+
+// deviceKeyring generates and holds the private keys of the devices
+// registered through this installation. Only public keys are sent with a
+// registration; private keys never leave the keyring and are only used to
+// answer device challenges. They are stored in the encrypted vault;
+// keys received while the vault is locked are held in memory and moved into
+// it once it is unlocked.
+type deviceKeyring struct {
+	mu         sync.RWMutex
+	vault      *vault.Vault
+	pending    map[types.ID][]byte
+	backend    vault.Backend
+	kdf        vault.KDFParams
+	keyService services.DeviceKeyService
+}
+
+// VaultStatus describes the vault holding device keys
+type VaultStatus struct {
+	Unlocked   bool `json:"unlocked"`
+	KeyVersion int  `json:"key_version,omitempty"`
+	Secrets    int  `json:"secrets"`
+
+	// PendingKeys counts the device keys waiting for the vault to be unlocked
+	PendingKeys int `json:"pending_keys"`
+}
+
+// newDeviceKeyring creates an empty keyring whose vault is locked
+func newDeviceKeyring(keyService services.DeviceKeyService, backend vault.Backend, kdf vault.KDFParams) *deviceKeyring {
+	return &deviceKeyring{
+		pending:    make(map[types.ID][]byte),
+		backend:    backend,
+		kdf:        kdf,
+		keyService: keyService,
+	}
+}
+
+// deviceKeyName is the vault name of a device's private key
+func deviceKeyName(deviceID types.ID) string {
+	return "device/" + deviceID.String()
+}
+
+// unlock opens the vault and moves the pending keys into it
+func (k *deviceKeyring) unlock(passphrase []byte) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.vault != nil {
+		return nil
+	}
+	v, err := vault.Open(k.backend, passphrase, k.kdf)
+	if err != nil {
+		return err
+	}
+	for deviceID, key := range k.pending {
+		if err := v.Put(deviceKeyName(deviceID), key); err != nil {
+			v.Close()
+			return err
+		}
+	}
+	clear(k.pending)
+	k.vault = v
+	return nil
+}
+
+// lock closes the vault
+func (k *deviceKeyring) lock() {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.vault != nil {
+		k.vault.Close()
+		k.vault = nil
+	}
+}
+
+// rotate re-encrypts the vault under a key derived from a new passphrase once
+// the current passphrase was confirmed
+func (k *deviceKeyring) rotate(current, next []byte) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.vault == nil {
+		return vault.ErrLocked
+	}
+	confirmed, err := vault.Open(k.backend, current, k.kdf)
+	if err != nil {
+		return err
+	}
+	confirmed.Close()
+	return k.vault.Rotate(next, k.kdf)
+}
+
+// status describes the vault
+func (k *deviceKeyring) status() VaultStatus {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	status := VaultStatus{PendingKeys: len(k.pending)}
+	if k.vault != nil {
+		status.Unlocked = true
+		status.KeyVersion = k.vault.KeyVersion()
+		status.Secrets = len(k.vault.Names())
+	}
+	return status
+}
+
+// generate creates the key pair of a device about to be registered. It
+// returns the base64url encoded public key to register and the private key
+// to store once the device has an ID.
+func (k *deviceKeyring) generate() (string, []byte, error) {
+	publicKey, privateKey, err := k.keyService.GenerateKeyPair()
+	if err != nil {
+		return "", nil, err
+	}
+	return base64.RawURLEncoding.EncodeToString(publicKey), privateKey, nil
+}
+
+// store keeps a registered device's private key
+func (k *deviceKeyring) store(deviceID types.ID, key []byte) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.vault != nil {
+		return k.vault.Put(deviceKeyName(deviceID), key)
+	}
+	k.pending[deviceID] = bytes.Clone(key)
+	return nil
+}
+
+// sign signs a message with a device's private key. It reports false if the
+// keyring does not hold the device's key and fails with vault.ErrLocked if
+// the key may be in the locked vault.
+func (k *deviceKeyring) sign(deviceID types.ID, message []byte) ([]byte, bool, error) {
+	key, err := k.key(deviceID)
+	if err != nil || key == nil {
+		return nil, false, err
+	}
+	defer clear(key)
+
+	signature, err := k.keyService.Sign(key, message)
+	return signature, true, err
+}
+
+// key returns a copy of a device's private key, or nil if it is not held.
+// Keys in the vault cannot be read while it is locked.
+func (k *deviceKeyring) key(deviceID types.ID) ([]byte, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	if key, exists := k.pending[deviceID]; exists {
+		return bytes.Clone(key), nil
+	}
+	if k.vault == nil {
+		return nil, vault.ErrLocked
+	}
+	key, err := k.vault.Get(deviceKeyName(deviceID))
+	if errors.Is(err, vault.ErrSecretNotFound) {
+		return nil, nil
+	}
+	return key, err
+}
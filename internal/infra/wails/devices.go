@@ -0,0 +1,358 @@
+package wails
+
+import (
+	"encoding/base64"
+	"net"
+
+	"shadow-id/internal/app/commands"
+	"shadow-id/internal/app/pipeline"
+	"shadow-id/internal/app/queries"
+	"shadow-id/internal/domain/entities"
+	"shadow-id/pkg/errors"
+	"shadow-id/pkg/types"
+)
+
+// GetCurrentDevice fingerprints the machine the application runs on
+func (a *App) GetCurrentDevice() (*queries.GetCurrentDeviceResult, error) {
+	a.logger.Info("GetCurrentDevice method called")
+
+	result, err := pipeline.Send[*queries.GetCurrentDeviceResult](a.requestContext(), a.appService.Bus, queries.GetCurrentDeviceQuery{})
+	if err != nil {
+		a.logger.Error("Failed to get current device", "error", err)
+		return nil, err
+	}
+
+	a.logger.Info("Current device fingerprinted", "components", len(result.Components))
+	return result, nil
+}
+
+// RegisterDevice registers a device to a user with a key pair generated
+// by this installation
+func (a *App) RegisterDevice(userID, name, platform string) (*commands.RegisterDeviceResult, error) {
+	a.logger.Info("RegisterDevice method called", "user_id", userID, "name", name)
+
+	publicKey, privateKey, err := a.newDeviceKey()
+	if err != nil {
+		return nil, err
+	}
+	defer clear(privateKey)
+
+	cmd := commands.RegisterDeviceCommand{
+		UserID:    types.ID(userID),
+		Name:      name,
+		Platform:  platform,
+		PublicKey: publicKey,
+	}
+
+	result, err := pipeline.Send[*commands.RegisterDeviceResult](a.requestContext(), a.appService.Bus, cmd)
+	if err != nil {
+		a.logger.Error("Failed to register device", "error", err)
+		return nil, err
+	}
+	if err := a.keepDeviceKey(result.ID, privateKey); err != nil {
+		return nil, err
+	}
+
+	a.logger.Info("Device registered successfully", "id", result.ID, "evicted_device_id", result.EvictedDeviceID)
+	return result, nil
+}
+
+// RegisterCurrentDevice registers the machine the application runs on to a
+// user, together with its fingerprint
+func (a *App) RegisterCurrentDevice(userID, name string) (*commands.RegisterDeviceResult, error) {
+	a.logger.Info("RegisterCurrentDevice method called", "user_id", userID, "name", name)
+
+	current, err := pipeline.Send[*queries.GetCurrentDeviceResult](a.requestContext(), a.appService.Bus, queries.GetCurrentDeviceQuery{})
+	if err != nil {
+		a.logger.Error("Failed to get current device", "error", err)
+		return nil, err
+	}
+
+	publicKey, privateKey, err := a.newDeviceKey()
+	if err != nil {
+		return nil, err
+	}
+	defer clear(privateKey)
+
+	cmd := commands.RegisterDeviceCommand{
+		UserID:      types.ID(userID),
+		Name:        name,
+		Platform:    current.Platform,
+		PublicKey:   publicKey,
+		Fingerprint: current.Components,
+	}
+
+	result, err := pipeline.Send[*commands.RegisterDeviceResult](a.requestContext(), a.appService.Bus, cmd)
+	if err != nil {
+		a.logger.Error("Failed to register device", "error", err)
+		return nil, err
+	}
+	if err := a.keepDeviceKey(result.ID, privateKey); err != nil {
+		return nil, err
+	}
+
+	a.logger.Info("Device registered successfully", "id", result.ID, "evicted_device_id", result.EvictedDeviceID)
+	return result, nil
+}
+
+// MatchCurrentDevice recognizes the machine the application runs on among a
+// user's devices, tolerating attributes that changed since it was registered
+func (a *App) MatchCurrentDevice(userID string) (*commands.MatchDeviceResult, error) {
+	a.logger.Info("MatchCurrentDevice method called", "user_id", userID)
+
+	current, err := pipeline.Send[*queries.GetCurrentDeviceResult](a.requestContext(), a.appService.Bus, queries.GetCurrentDeviceQuery{})
+	if err != nil {
+		a.logger.Error("Failed to get current device", "error", err)
+		return nil, err
+	}
+
+	cmd := commands.MatchDeviceCommand{
+		UserID:      types.ID(userID),
+		Fingerprint: current.Components,
+	}
+
+	result, err := pipeline.Send[*commands.MatchDeviceResult](a.requestContext(), a.appService.Bus, cmd)
+	if err != nil {
+		a.logger.Error("Failed to match device", "error", err)
+		return nil, err
+	}
+
+	a.logger.Info("Current device matched", "device_id", result.DeviceID, "verdict", result.Verdict, "confidence", result.Confidence)
+	return result, nil
+}
+
+// RevokeDevice revokes a device and the passkeys bound to it. A non-zero
+// version rejects the revocation if the device changed since.
+func (a *App) RevokeDevice(deviceID string, version int64) (*commands.RevokeDeviceResult, error) {
+	a.logger.Info("RevokeDevice method called", "device_id", deviceID, "version", version)
+
+	cmd := commands.RevokeDeviceCommand{
+		DeviceID: types.ID(deviceID),
+		Version:  version,
+	}
+
+	result, err := pipeline.Send[*commands.RevokeDeviceResult](a.requestContext(), a.appService.Bus, cmd)
+	if err != nil {
+		a.logger.Error("Failed to revoke device", "error", err)
+		return nil, err
+	}
+
+	a.logger.Info("Device revoked", "device_id", result.DeviceID, "revoked_credentials", result.RevokedCredentials)
+	return result, nil
+}
+
+// ApproveDevice trusts a pending or suspended device. A non-zero version
+// rejects the approval if the device changed since.
+func (a *App) ApproveDevice(deviceID string, version int64) (*commands.DeviceTrustResult, error) {
+	a.logger.Info("ApproveDevice method called", "device_id", deviceID, "version", version)
+
+	cmd := commands.ApproveDeviceCommand{
+		DeviceID: types.ID(deviceID),
+		Version:  version,
+	}
+
+	result, err := pipeline.Send[*commands.DeviceTrustResult](a.requestContext(), a.appService.Bus, cmd)
+	if err != nil {
+		a.logger.Error("Failed to approve device", "error", err)
+		return nil, err
+	}
+
+	a.logger.Info("Device approved", "device_id", result.DeviceID, "approved_by", result.ApprovedBy)
+	return result, nil
+}
+
+// SuspendDevice bars a pending or trusted device until it is approved again.
+// A non-zero version rejects the suspension if the device changed since.
+func (a *App) SuspendDevice(deviceID, reason string, version int64) (*commands.DeviceTrustResult, error) {
+	a.logger.Info("SuspendDevice method called", "device_id", deviceID, "version", version)
+
+	cmd := commands.SuspendDeviceCommand{
+		DeviceID: types.ID(deviceID),
+		Reason:   reason,
+		Version:  version,
+	}
+
+	result, err := pipeline.Send[*commands.DeviceTrustResult](a.requestContext(), a.appService.Bus, cmd)
+	if err != nil {
+		a.logger.Error("Failed to suspend device", "error", err)
+		return nil, err
+	}
+
+	a.logger.Info("Device suspended", "device_id", result.DeviceID)
+	return result, nil
+}
+
+// SendDeviceHeartbeat reports that a device is alive, running this version
+// of the application from the machine's outward facing address
+func (a *App) SendDeviceHeartbeat(deviceID string) (*commands.RecordDeviceHeartbeatResult, error) {
+	a.logger.Info("SendDeviceHeartbeat method called", "device_id", deviceID)
+
+	cmd := commands.RecordDeviceHeartbeatCommand{
+		DeviceID:   types.ID(deviceID),
+		AppVersion: a.config.Version,
+		IPAddress:  localIP(),
+	}
+
+	result, err := pipeline.Send[*commands.RecordDeviceHeartbeatResult](a.requestContext(), a.appService.Bus, cmd)
+	if err != nil {
+		a.logger.Error("Failed to record device heartbeat", "error", err)
+		return nil, err
+	}
+
+	if result.Resumed {
+		a.logger.Info("Stale device resumed", "device_id", result.DeviceID)
+	}
+	return result, nil
+}
+
+// ProveDevice proves that this installation holds a device's private key by
+// answering a challenge for it. A device awaiting approval can only prove
+// itself if allowPending is set.
+func (a *App) ProveDevice(deviceID string, allowPending bool) (*commands.VerifyDeviceChallengeResult, error) {
+	a.logger.Info("ProveDevice method called", "device_id", deviceID, "allow_pending", allowPending)
+
+	challenge, err := pipeline.Send[*commands.IssueDeviceChallengeResult](a.requestContext(), a.appService.Bus, commands.IssueDeviceChallengeCommand{
+		DeviceID:     types.ID(deviceID),
+		AllowPending: allowPending,
+	})
+	if err != nil {
+		a.logger.Error("Failed to issue device challenge", "error", err)
+		return nil, err
+	}
+
+	// Sign the challenge with the key kept at registration
+	nonce, err := base64.RawURLEncoding.DecodeString(challenge.Nonce)
+	if err != nil {
+		return nil, err
+	}
+	signature, held, err := a.deviceKeys.sign(challenge.DeviceID, entities.DeviceChallengeMessage(challenge.ChallengeID, challenge.DeviceID, nonce))
+	if err != nil {
+		a.logger.Error("Failed to sign device challenge", "error", err)
+		return nil, err
+	}
+	if !held {
+		return nil, errors.WrapWithType(entities.ErrDeviceKeyMissing, errors.ErrorTypeNotFound, "device key is not held by this installation")
+	}
+
+	result, err := pipeline.Send[*commands.VerifyDeviceChallengeResult](a.requestContext(), a.appService.Bus, commands.VerifyDeviceChallengeCommand{
+		ChallengeID: challenge.ChallengeID,
+		DeviceID:    challenge.DeviceID,
+		Signature:   base64.RawURLEncoding.EncodeToString(signature),
+	})
+	if err != nil {
+		a.logger.Error("Failed to verify device challenge", "error", err)
+		return nil, err
+	}
+
+	a.logger.Info("Device proved", "device_id", result.DeviceID, "trust_state", result.TrustState)
+	return result, nil
+}
+
+// ListUserDevices retrieves the devices registered to a user
+func (a *App) ListUserDevices(userID string) (*queries.ListUserDevicesResult, error) {
+	a.logger.Info("ListUserDevices method called", "user_id", userID)
+
+	query := queries.ListUserDevicesQuery{
+		UserID: types.ID(userID),
+	}
+
+	result, err := pipeline.Send[*queries.ListUserDevicesResult](a.requestContext(), a.appService.Bus, query)
+	if err != nil {
+		a.logger.Error("Failed to list devices", "error", err)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetDeviceQuota retrieves a user's device usage against their quota
+func (a *App) GetDeviceQuota(userID string) (*queries.GetDeviceQuotaResult, error) {
+	a.logger.Info("GetDeviceQuota method called", "user_id", userID)
+
+	query := queries.GetDeviceQuotaQuery{
+		UserID: types.ID(userID),
+	}
+
+	result, err := pipeline.Send[*queries.GetDeviceQuotaResult](a.requestContext(), a.appService.Bus, query)
+	if err != nil {
+		a.logger.Error("Failed to get device quota", "error", err)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetDeviceActivity retrieves the number of active and stale devices
+func (a *App) GetDeviceActivity() (*queries.GetDeviceActivityResult, error) {
+	a.logger.Info("GetDeviceActivity method called")
+
+	result, err := pipeline.Send[*queries.GetDeviceActivityResult](a.requestContext(), a.appService.Bus, queries.GetDeviceActivityQuery{})
+	if err != nil {
+		a.logger.Error("Failed to get device activity", "error", err)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ListPendingDevices retrieves a page of devices awaiting approval
+func (a *App) ListPendingDevices(limit, offset int) (*queries.ListPendingDevicesResult, error) {
+	a.logger.Info("ListPendingDevices method called", "limit", limit, "offset", offset)
+
+	query := queries.ListPendingDevicesQuery{
+		Limit:  limit,
+		Offset: offset,
+	}
+
+	result, err := pipeline.Send[*queries.ListPendingDevicesResult](a.requestContext(), a.appService.Bus, query)
+	if err != nil {
+		a.logger.Error("Failed to list pending devices", "error", err)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// newDeviceKey generates the key pair of a device about to be registered,
+// returning the public key to send and the private key to keep
+func (a *App) newDeviceKey() (string, []byte, error) {
+	publicKey, privateKey, err := a.deviceKeys.generate()
+	if err != nil {
+		a.logger.Error("Failed to generate device key", "error", err)
+		return "", nil, errors.Wrap(err, "failed to generate device key")
+	}
+	return publicKey, privateKey, nil
+}
+
+// keepDeviceKey stores a registered device's private key in the keyring
+func (a *App) keepDeviceKey(deviceID types.ID, privateKey []byte) error {
+	if err := a.deviceKeys.store(deviceID, privateKey); err != nil {
+		a.logger.Error("Failed to keep device key", "error", err)
+		return err
+	}
+	return nil
+}
+
+// localIP returns the machine's first non-loopback unicast address, preferring
+// IPv4, or an empty string if it has none
+func localIP() string {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return ""
+	}
+
+	var fallback string
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok || !ipNet.IP.IsGlobalUnicast() {
+			continue
+		}
+		if ipNet.IP.To4() != nil {
+			return ipNet.IP.String()
+		}
+		if fallback == "" {
+			fallback = ipNet.IP.String()
+		}
+	}
+	return fallback
+}
@@ -0,0 +1,111 @@
+package wails
+
+import (
+	stderrors "errors"
+	"testing"
+
+	"shadow-id/internal/app/commands"
+	"shadow-id/internal/domain/entities"
+	"shadow-id/pkg/errors"
+)
+
+func TestPendingDeviceProvesItselfOnlyWhenAllowed(t *testing.T) {
+	app, users := newTestApp(t)
+
+	app.session.signIn(users.alice)
+	device, err := app.RegisterDevice(users.alice.String(), "Laptop", "linux")
+	if err != nil {
+		t.Fatalf("RegisterDevice() error = %v", err)
+	}
+
+	_, err = app.ProveDevice(device.ID.String(), false)
+	if !errors.IsForbiddenError(err) || !stderrors.Is(err, entities.ErrDevicePending) {
+		t.Fatalf("ProveDevice() of a pending device error = %v, want %v", err, entities.ErrDevicePending)
+	}
+	if _, err := app.ProveDevice(device.ID.String(), true); err != nil {
+		t.Fatalf("ProveDevice() allowing pending error = %v", err)
+	}
+
+	// Once approved, the device proves itself without asking
+	if err := dispatch(app, as(users.admin), commands.ApproveDeviceCommand{DeviceID: device.ID}); err != nil {
+		t.Fatalf("ApproveDevice() error = %v", err)
+	}
+	if _, err := app.ProveDevice(device.ID.String(), false); err != nil {
+		t.Errorf("ProveDevice() of an approved device error = %v", err)
+	}
+}
+
+func TestDevicesAreRegisteredWithTheClientsPublicKey(t *testing.T) {
+	app, users := newTestApp(t)
+
+	publicKey := devicePublicKey(t)
+	result, err := app.appService.Bus.Dispatch(as(users.alice), commands.RegisterDeviceCommand{
+		UserID:    users.alice,
+		Name:      "Laptop",
+		PublicKey: publicKey,
+	})
+	if err != nil {
+		t.Fatalf("RegisterDevice() error = %v", err)
+	}
+	if got := result.(*commands.RegisterDeviceResult).PublicKey; got != publicKey {
+		t.Errorf("RegisterDevice() public key = %q, want %q", got, publicKey)
+	}
+
+	invalid := []struct {
+		name      string
+		publicKey string
+	}{
+		{"missing", ""},
+		{"not base64url", "not a key!"},
+		{"wrong length", "AAAA"},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			err := dispatch(app, as(users.alice), commands.RegisterDeviceCommand{UserID: users.alice, Name: "Phone", PublicKey: tt.publicKey})
+			if !errors.IsValidationError(err) {
+				t.Errorf("RegisterDevice() error = %v, want a validation error", err)
+			}
+		})
+	}
+
+	// A first device created along with its user proves itself with the
+	// private key this installation kept
+	app.session.signIn(users.admin)
+	created, err := app.CreateUser("Carol", "carol@example.com", "Desktop", "linux")
+	if err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+	app.session.signIn(created.ID)
+	if _, err := app.ProveDevice(created.DeviceID.String(), true); err != nil {
+		t.Errorf("ProveDevice() of the first device error = %v", err)
+	}
+}
+
+func TestDeviceActivityDoesNotOutdateTrustVersions(t *testing.T) {
+	app, users := newTestApp(t)
+
+	app.session.signIn(users.alice)
+	device, err := app.RegisterDevice(users.alice.String(), "Laptop", "linux")
+	if err != nil {
+		t.Fatalf("RegisterDevice() error = %v", err)
+	}
+	if err := dispatch(app, as(users.alice), commands.RecordDeviceHeartbeatCommand{DeviceID: device.ID, AppVersion: "1.0.0"}); err != nil {
+		t.Fatalf("RecordDeviceHeartbeat() error = %v", err)
+	}
+
+	// The version read at registration is still current after the heartbeat
+	approved, err := app.appService.Bus.Dispatch(as(users.admin), commands.ApproveDeviceCommand{DeviceID: device.ID, Version: device.Version})
+	if err != nil {
+		t.Fatalf("ApproveDevice() with the version read before a heartbeat error = %v", err)
+	}
+
+	// A trust change does outdate it
+	err = dispatch(app, as(users.admin), commands.SuspendDeviceCommand{DeviceID: device.ID, Version: device.Version})
+	if !errors.IsConflictError(err) {
+		t.Errorf("SuspendDevice() with the version read before approval error = %v, want conflict", err)
+	}
+	version := approved.(*commands.DeviceTrustResult).Version
+	if err := dispatch(app, as(users.admin), commands.SuspendDeviceCommand{DeviceID: device.ID, Version: version}); err != nil {
+		t.Errorf("SuspendDevice() with the approved version error = %v", err)
+	}
+}
@@ -0,0 +1,26 @@
+package wails
+
+import (
+	"context"
+
+	"shadow-id/internal/domain/events"
+	"shadow-id/internal/infra/eventbus"
+)
+
+// subscribeEvents registers the desktop application's event subscribers
+func (a *App) subscribeEvents() {
+	// Keep a trace of everything that happens in the domain
+	a.eventBus.SubscribeAsync("event-log", eventbus.AllEvents, func(ctx context.Context, event events.Event) error {
+		a.logger.Info("Domain event", "event", event.EventName(), "aggregate_id", event.AggregateID())
+		return nil
+	})
+
+	// Keep the read models in step with the write model
+	a.projections.Subscribe(a.eventBus)
+
+	// A deleted user cannot stay signed in
+	a.eventBus.Subscribe("session", events.UserDeletedEvent, func(ctx context.Context, event events.Event) error {
+		a.session.endFor(event.AggregateID())
+		return nil
+	})
+}
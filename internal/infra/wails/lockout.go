@@ -0,0 +1,44 @@
+package wails
+
+import (
+	"shadow-id/internal/app/commands"
+	"shadow-id/internal/app/pipeline"
+	"shadow-id/internal/app/queries"
+	"shadow-id/pkg/types"
+)
+
+// UnlockAccount clears a lockout on an account and, optionally, one of its devices
+func (a *App) UnlockAccount(userID, deviceID string) (*commands.UnlockAccountResult, error) {
+	a.logger.Info("UnlockAccount method called", "user_id", userID, "device_id", deviceID)
+
+	cmd := commands.UnlockAccountCommand{
+		UserID:   types.ID(userID),
+		DeviceID: types.ID(deviceID),
+	}
+
+	result, err := pipeline.Send[*commands.UnlockAccountResult](a.requestContext(), a.appService.Bus, cmd)
+	if err != nil {
+		a.logger.Error("Failed to unlock account", "error", err)
+		return nil, err
+	}
+
+	a.logger.Info("Account unlocked", "user_id", result.UserID)
+	return result, nil
+}
+
+// GetLockoutStatus retrieves the failed-attempt state of an account
+func (a *App) GetLockoutStatus(userID string) (*queries.GetLockoutStatusResult, error) {
+	a.logger.Info("GetLockoutStatus method called", "user_id", userID)
+
+	query := queries.GetLockoutStatusQuery{
+		UserID: types.ID(userID),
+	}
+
+	result, err := pipeline.Send[*queries.GetLockoutStatusResult](a.requestContext(), a.appService.Bus, query)
+	if err != nil {
+		a.logger.Error("Failed to get lockout status", "error", err)
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,102 @@
+package wails
+
+import (
+	"shadow-id/internal/app/commands"
+	"shadow-id/internal/app/pipeline"
+	"shadow-id/internal/app/queries"
+	"shadow-id/internal/domain/entities"
+	"shadow-id/pkg/errors"
+	"shadow-id/pkg/types"
+)
+
+// BeginPasskeyRegistration starts a WebAuthn registration ceremony for a user,
+// optionally binding the new passkey to one of the user's devices
+func (a *App) BeginPasskeyRegistration(userID, deviceID string) (*commands.BeginPasskeyRegistrationResult, error) {
+	a.logger.Info("BeginPasskeyRegistration method called", "user_id", userID, "device_id", deviceID)
+
+	cmd := commands.BeginPasskeyRegistrationCommand{
+		UserID:   types.ID(userID),
+		DeviceID: types.ID(deviceID),
+	}
+
+	result, err := pipeline.Send[*commands.BeginPasskeyRegistrationResult](a.requestContext(), a.appService.Bus, cmd)
+	if err != nil {
+		a.logger.Error("Failed to begin passkey registration", "error", err)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// FinishPasskeyRegistration completes a WebAuthn registration ceremony
+func (a *App) FinishPasskeyRegistration(cmd commands.FinishPasskeyRegistrationCommand) (*commands.FinishPasskeyRegistrationResult, error) {
+	a.logger.Info("FinishPasskeyRegistration method called", "session_id", cmd.SessionID)
+
+	result, err := pipeline.Send[*commands.FinishPasskeyRegistrationResult](a.requestContext(), a.appService.Bus, cmd)
+	if err != nil {
+		a.logger.Error("Failed to finish passkey registration", "error", err)
+		return nil, err
+	}
+
+	a.logger.Info("Passkey registered successfully", "id", result.ID, "user_id", result.UserID)
+	return result, nil
+}
+
+// BeginPasskeyLogin starts a WebAuthn authentication ceremony
+func (a *App) BeginPasskeyLogin(userID string) (*commands.BeginPasskeyLoginResult, error) {
+	a.logger.Info("BeginPasskeyLogin method called", "user_id", userID)
+
+	cmd := commands.BeginPasskeyLoginCommand{
+		UserID: types.ID(userID),
+	}
+
+	result, err := pipeline.Send[*commands.BeginPasskeyLoginResult](a.requestContext(), a.appService.Bus, cmd)
+	if err != nil {
+		a.logger.Error("Failed to begin passkey login", "error", err)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// FinishPasskeyLogin completes a WebAuthn authentication ceremony
+func (a *App) FinishPasskeyLogin(cmd commands.FinishPasskeyLoginCommand) (*commands.FinishPasskeyLoginResult, error) {
+	a.logger.Info("FinishPasskeyLogin method called", "session_id", cmd.SessionID)
+
+	result, err := pipeline.Send[*commands.FinishPasskeyLoginResult](a.requestContext(), a.appService.Bus, cmd)
+	if err != nil {
+		a.logger.Warn("Passkey login failed", "error", err)
+		return nil, err
+	}
+	if result.CloneDetected {
+		a.logger.Warn("Passkey login rejected, authenticator may be cloned", "user_id", result.UserID, "credential_id", result.CredentialID)
+		return nil, errors.WrapWithType(entities.ErrCredentialCloned, errors.ErrorTypeConflict, "passkey login failed")
+	}
+
+	// Sign in, or hold the session until the second factor is verified
+	if result.SecondFactorRequired {
+		a.session.awaitSecondFactor(result.UserID)
+	} else {
+		a.session.signIn(result.UserID)
+	}
+
+	a.logger.Info("Passkey login succeeded", "user_id", result.UserID, "second_factor_required", result.SecondFactorRequired)
+	return result, nil
+}
+
+// ListPasskeys retrieves the passkeys registered to a user
+func (a *App) ListPasskeys(userID string) (*queries.ListPasskeysResult, error) {
+	a.logger.Info("ListPasskeys method called", "user_id", userID)
+
+	query := queries.ListPasskeysQuery{
+		UserID: types.ID(userID),
+	}
+
+	result, err := pipeline.Send[*queries.ListPasskeysResult](a.requestContext(), a.appService.Bus, query)
+	if err != nil {
+		a.logger.Error("Failed to list passkeys", "error", err)
+		return nil, err
+	}
+
+	return result, nil
+}
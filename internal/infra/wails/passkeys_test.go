@@ -0,0 +1,104 @@
+package wails
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"shadow-id/internal/app/commands"
+	"shadow-id/internal/app/queries"
+	"shadow-id/internal/infra/webauthn"
+	"shadow-id/pkg/types"
+)
+
+const (
+	testRPID   = "wails.localhost"
+	testOrigin = "http://wails.localhost"
+)
+
+// registerPasskey registers a passkey held by the software authenticator for
+// the user and returns its credential ID
+func registerPasskey(t *testing.T, app *App, authenticator *webauthn.SoftwareAuthenticator, userID types.ID) []byte {
+	t.Helper()
+
+	begun, err := app.appService.Bus.Dispatch(as(userID), commands.BeginPasskeyRegistrationCommand{UserID: userID})
+	if err != nil {
+		t.Fatalf("BeginPasskeyRegistration() error = %v", err)
+	}
+	options := begun.(*commands.BeginPasskeyRegistrationResult)
+	challenge, err := base64.RawURLEncoding.DecodeString(options.PublicKey.Challenge)
+	if err != nil {
+		t.Fatalf("DecodeString() error = %v", err)
+	}
+
+	credentialID, clientData, attestation, err := authenticator.Register(testRPID, testOrigin, challenge)
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if err := dispatch(app, as(userID), commands.FinishPasskeyRegistrationCommand{
+		SessionID:         options.SessionID,
+		ClientDataJSON:    base64.RawURLEncoding.EncodeToString(clientData),
+		AttestationObject: base64.RawURLEncoding.EncodeToString(attestation),
+	}); err != nil {
+		t.Fatalf("FinishPasskeyRegistration() error = %v", err)
+	}
+	return credentialID
+}
+
+// loginWithPasskey runs an authentication ceremony for the credential as an
+// anonymous caller
+func loginWithPasskey(t *testing.T, app *App, authenticator *webauthn.SoftwareAuthenticator, userID types.ID, credentialID []byte) *commands.FinishPasskeyLoginResult {
+	t.Helper()
+	ctx := context.Background()
+
+	begun, err := app.appService.Bus.Dispatch(ctx, commands.BeginPasskeyLoginCommand{UserID: userID})
+	if err != nil {
+		t.Fatalf("BeginPasskeyLogin() error = %v", err)
+	}
+	options := begun.(*commands.BeginPasskeyLoginResult)
+	challenge, err := base64.RawURLEncoding.DecodeString(options.PublicKey.Challenge)
+	if err != nil {
+		t.Fatalf("DecodeString() error = %v", err)
+	}
+
+	clientData, authData, signature, err := authenticator.Assert(testRPID, testOrigin, credentialID, challenge)
+	if err != nil {
+		t.Fatalf("Assert() error = %v", err)
+	}
+	finished, err := app.appService.Bus.Dispatch(ctx, commands.FinishPasskeyLoginCommand{
+		SessionID:         options.SessionID,
+		CredentialID:      base64.RawURLEncoding.EncodeToString(credentialID),
+		ClientDataJSON:    base64.RawURLEncoding.EncodeToString(clientData),
+		AuthenticatorData: base64.RawURLEncoding.EncodeToString(authData),
+		Signature:         base64.RawURLEncoding.EncodeToString(signature),
+	})
+	if err != nil {
+		t.Fatalf("FinishPasskeyLogin() error = %v", err)
+	}
+	return finished.(*commands.FinishPasskeyLoginResult)
+}
+
+func TestClonedPasskeyIsFlagged(t *testing.T) {
+	app, users := newTestApp(t)
+	authenticator := webauthn.NewSoftwareAuthenticator(webauthn.FormatNone)
+	credentialID := registerPasskey(t, app, authenticator, users.alice)
+
+	if result := loginWithPasskey(t, app, authenticator, users.alice, credentialID); result.CloneDetected {
+		t.Fatal("first login was reported as a clone")
+	}
+
+	// A copy of the authenticator replays an earlier counter
+	authenticator.SetSignCount(credentialID, 0)
+	if result := loginWithPasskey(t, app, authenticator, users.alice, credentialID); !result.CloneDetected {
+		t.Fatal("FinishPasskeyLogin() accepted a counter that went backwards")
+	}
+
+	listed, err := app.appService.Bus.Dispatch(as(users.alice), queries.ListPasskeysQuery{UserID: users.alice})
+	if err != nil {
+		t.Fatalf("ListPasskeys() error = %v", err)
+	}
+	passkeys := listed.(*queries.ListPasskeysResult).Passkeys
+	if len(passkeys) != 1 || !passkeys[0].CloneWarning {
+		t.Errorf("stored passkeys = %+v, want the credential flagged", passkeys)
+	}
+}
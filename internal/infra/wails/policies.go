@@ -0,0 +1,20 @@
+package wails
+
+import (
+	"shadow-id/internal/app/pipeline"
+	"shadow-id/internal/app/queries"
+)
+
+// EvaluatePolicy explains how the access policies decide a request without enforcing it
+func (a *App) EvaluatePolicy(query queries.EvaluatePolicyQuery) (*queries.EvaluatePolicyResult, error) {
+	a.logger.Info("EvaluatePolicy method called", "subject_id", query.SubjectID, "action", query.Action)
+
+	result, err := pipeline.Send[*queries.EvaluatePolicyResult](a.requestContext(), a.appService.Bus, query)
+	if err != nil {
+		a.logger.Error("Failed to evaluate policy", "error", err)
+		return nil, err
+	}
+
+	a.logger.Info("Policy evaluated", "action", result.Action, "effect", result.Effect, "policy", result.PolicyID)
+	return result, nil
+}
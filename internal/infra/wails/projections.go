@@ -0,0 +1,47 @@
+package wails
+
+import (
+	"shadow-id/internal/app/commands"
+	"shadow-id/internal/app/pipeline"
+	"shadow-id/internal/app/queries"
+)
+
+// ListUsers lists users with their device counts and last sign-in.
+// Soft-deleted users are only listed if includeDeleted is set.
+func (a *App) ListUsers(limit, offset int, includeDeleted bool) (*queries.ListUsersResult, error) {
+	a.logger.Info("ListUsers method called", "limit", limit, "offset", offset, "include_deleted", includeDeleted)
+
+	query := queries.ListUsersQuery{
+		Limit:          limit,
+		Offset:         offset,
+		IncludeDeleted: includeDeleted,
+	}
+
+	result, err := pipeline.Send[*queries.ListUsersResult](a.requestContext(), a.appService.Bus, query)
+	if err != nil {
+		a.logger.Error("Failed to list users", "error", err)
+		return nil, err
+	}
+
+	a.logger.Info("Users listed", "count", len(result.Users), "total", result.Total)
+	return result, nil
+}
+
+// RebuildProjections regenerates the named read model, or all of them if the
+// name is empty, from the event history
+func (a *App) RebuildProjections(name string) (*commands.RebuildProjectionsResult, error) {
+	a.logger.Info("RebuildProjections method called", "name", name)
+
+	cmd := commands.RebuildProjectionsCommand{
+		Name: name,
+	}
+
+	result, err := pipeline.Send[*commands.RebuildProjectionsResult](a.requestContext(), a.appService.Bus, cmd)
+	if err != nil {
+		a.logger.Error("Failed to rebuild projections", "error", err)
+		return nil, err
+	}
+
+	a.logger.Info("Projections rebuilt", "count", len(result.Projections))
+	return result, nil
+}
@@ -0,0 +1,59 @@
+package wails
+
+import (
+	"shadow-id/internal/app/commands"
+	"shadow-id/internal/app/pipeline"
+	"shadow-id/internal/app/queries"
+	"shadow-id/pkg/types"
+)
+
+// AssignRole grants a role to a user
+func (a *App) AssignRole(userID, role string) (*commands.AssignRoleResult, error) {
+	a.logger.Info("AssignRole method called", "user_id", userID, "role", role)
+
+	cmd := commands.AssignRoleCommand{
+		UserID: types.ID(userID),
+		Role:   role,
+	}
+
+	result, err := pipeline.Send[*commands.AssignRoleResult](a.requestContext(), a.appService.Bus, cmd)
+	if err != nil {
+		a.logger.Error("Failed to assign role", "error", err)
+		return nil, err
+	}
+
+	a.logger.Info("Role assigned", "user_id", result.UserID, "role", role)
+	return result, nil
+}
+
+// RevokeRole removes a role from a user
+func (a *App) RevokeRole(userID, role string) (*commands.AssignRoleResult, error) {
+	a.logger.Info("RevokeRole method called", "user_id", userID, "role", role)
+
+	cmd := commands.RevokeRoleCommand{
+		UserID: types.ID(userID),
+		Role:   role,
+	}
+
+	result, err := pipeline.Send[*commands.AssignRoleResult](a.requestContext(), a.appService.Bus, cmd)
+	if err != nil {
+		a.logger.Error("Failed to revoke role", "error", err)
+		return nil, err
+	}
+
+	a.logger.Info("Role revoked", "user_id", result.UserID, "role", role)
+	return result, nil
+}
+
+// ListRoles lists the available roles and their permissions
+func (a *App) ListRoles() (*queries.ListRolesResult, error) {
+	a.logger.Info("ListRoles method called")
+
+	result, err := pipeline.Send[*queries.ListRolesResult](a.requestContext(), a.appService.Bus, queries.ListRolesQuery{})
+	if err != nil {
+		a.logger.Error("Failed to list roles", "error", err)
+		return nil, err
+	}
+
+	return result, nil
+}
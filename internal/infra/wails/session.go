@@ -0,0 +1,157 @@
+package wails
+
+import (
+	"context"
+	"sync"
+
+	"shadow-id/internal/app/auth"
+	"shadow-id/internal/app/commands"
+	"shadow-id/internal/app/pipeline"
+	"shadow-id/internal/domain/repositories"
+	"shadow-id/pkg/types"
+)
+
+// SessionInfo describes the signed-in principal of the desktop session
+type SessionInfo struct {
+	Authenticated bool     `json:"authenticated"`
+	UserID        types.ID `json:"user_id,omitempty"`
+
+	// PendingUserID is set when a user has passed the first factor and must
+	// still complete two-factor verification
+	PendingUserID types.ID `json:"pending_user_id,omitempty"`
+
+	// Bootstrap reports that no user exists yet and the caller acts as the
+	// initial administrator
+	Bootstrap bool `json:"bootstrap"`
+}
+
+// session tracks the principal the desktop frontend acts as
+type session struct {
+	mu        sync.RWMutex
+	principal *auth.Principal
+	pending   types.ID
+	userRepo  repositories.UserRepository
+}
+
+// newSession creates an empty, signed-out session
+func newSession(userRepo repositories.UserRepository) *session {
+	return &session{
+		userRepo: userRepo,
+	}
+}
+
+// signIn makes the user the current principal
+func (s *session) signIn(userID types.ID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.principal = &auth.Principal{UserID: userID}
+	s.pending = ""
+}
+
+// awaitSecondFactor records a user that must still pass two-factor verification
+func (s *session) awaitSecondFactor(userID types.ID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.principal = nil
+	s.pending = userID
+}
+
+// completeSecondFactor signs in the pending user if it matches
+func (s *session) completeSecondFactor(userID types.ID) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.pending.IsEmpty() || s.pending != userID {
+		return false
+	}
+	s.principal = &auth.Principal{UserID: userID}
+	s.pending = ""
+	return true
+}
+
+// signOut clears the current principal
+func (s *session) signOut() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.principal = nil
+	s.pending = ""
+}
+
+// endFor signs out the session if it belongs to the user
+func (s *session) endFor(userID types.ID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.pending == userID {
+		s.pending = ""
+	}
+	if s.principal != nil && s.principal.UserID == userID {
+		s.principal = nil
+	}
+}
+
+// bootstrapping checks if no user exists yet
+func (s *session) bootstrapping(ctx context.Context) bool {
+	count, err := s.userRepo.Count(ctx)
+	return err == nil && count == 0
+}
+
+// context returns a context carrying the current principal. Before the first
+// user exists the bootstrap principal is used so an administrator can be created.
+func (s *session) context(ctx context.Context) context.Context {
+	s.mu.RLock()
+	principal := s.principal
+	s.mu.RUnlock()
+
+	if principal != nil {
+		return auth.WithPrincipal(ctx, *principal)
+	}
+	if s.bootstrapping(ctx) {
+		return auth.WithPrincipal(ctx, auth.BootstrapPrincipal())
+	}
+	return ctx
+}
+
+// info describes the current session
+func (s *session) info(ctx context.Context) SessionInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	info := SessionInfo{PendingUserID: s.pending}
+	if s.principal != nil {
+		info.Authenticated = true
+		info.UserID = s.principal.UserID
+		return info
+	}
+	info.Bootstrap = s.bootstrapping(ctx)
+	return info
+}
+
+// requestContext returns the context application handlers are called with
+func (a *App) requestContext() context.Context {
+	return a.session.context(a.ctx)
+}
+
+// GetSession describes the signed-in principal
+func (a *App) GetSession() SessionInfo {
+	return a.session.info(a.ctx)
+}
+
+// SignOut ends the current session, on a device if it was bound to one. The
+// session ends even if the sign-out cannot be recorded.
+func (a *App) SignOut(deviceID string) {
+	a.logger.Info("SignOut method called", "device_id", deviceID)
+
+	cmd := commands.SignOutCommand{
+		DeviceID: types.ID(deviceID),
+	}
+	if a.session.info(a.ctx).Authenticated {
+		if _, err := pipeline.Send[*commands.SignOutResult](a.requestContext(), a.appService.Bus, cmd); err != nil {
+			a.logger.Error("Failed to record sign-out", "error", err)
+		}
+	}
+	a.session.signOut()
+}
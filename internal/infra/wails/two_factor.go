@@ -0,0 +1,145 @@
+package wails
+
+import (
+	"shadow-id/internal/app/commands"
+	"shadow-id/internal/app/pipeline"
+	"shadow-id/internal/app/queries"
+	"shadow-id/pkg/types"
+)
+
+// EnrollTwoFactor starts TOTP enrollment for a user
+func (a *App) EnrollTwoFactor(userID string) (*commands.EnrollTwoFactorResult, error) {
+	a.logger.Info("EnrollTwoFactor method called", "user_id", userID)
+
+	cmd := commands.EnrollTwoFactorCommand{
+		UserID: types.ID(userID),
+	}
+
+	result, err := pipeline.Send[*commands.EnrollTwoFactorResult](a.requestContext(), a.appService.Bus, cmd)
+	if err != nil {
+		a.logger.Error("Failed to enroll two-factor", "error", err)
+		return nil, err
+	}
+
+	a.logger.Info("Two-factor enrollment started", "user_id", result.UserID)
+	return result, nil
+}
+
+// ConfirmTwoFactor confirms a pending TOTP enrollment with a code from the authenticator
+func (a *App) ConfirmTwoFactor(userID, code string) (*commands.ConfirmTwoFactorResult, error) {
+	a.logger.Info("ConfirmTwoFactor method called", "user_id", userID)
+
+	cmd := commands.ConfirmTwoFactorCommand{
+		UserID: types.ID(userID),
+		Code:   code,
+	}
+
+	result, err := pipeline.Send[*commands.ConfirmTwoFactorResult](a.requestContext(), a.appService.Bus, cmd)
+	if err != nil {
+		a.logger.Error("Failed to confirm two-factor", "error", err)
+		return nil, err
+	}
+
+	a.logger.Info("Two-factor enabled", "user_id", result.UserID)
+	return result, nil
+}
+
+// VerifyTwoFactor verifies a TOTP or recovery code for a user, optionally
+// attributing the attempt to a device for throttling
+func (a *App) VerifyTwoFactor(userID, deviceID, code string) (*commands.VerifyTwoFactorResult, error) {
+	a.logger.Info("VerifyTwoFactor method called", "user_id", userID, "device_id", deviceID)
+
+	cmd := commands.VerifyTwoFactorCommand{
+		UserID:   types.ID(userID),
+		DeviceID: types.ID(deviceID),
+		Code:     code,
+	}
+
+	result, err := pipeline.Send[*commands.VerifyTwoFactorResult](a.requestContext(), a.appService.Bus, cmd)
+	if err != nil {
+		a.logger.Warn("Two-factor verification failed", "user_id", userID, "error", err)
+		return nil, err
+	}
+
+	// Complete a login that was waiting on the second factor
+	if a.session.completeSecondFactor(result.UserID) {
+		a.logger.Info("Login completed with second factor", "user_id", result.UserID)
+	}
+
+	a.logger.Info("Two-factor verified", "user_id", result.UserID, "method", result.Method)
+	return result, nil
+}
+
+// RegenerateRecoveryCodes replaces a user's recovery codes
+func (a *App) RegenerateRecoveryCodes(userID, code string) (*commands.RegenerateRecoveryCodesResult, error) {
+	a.logger.Info("RegenerateRecoveryCodes method called", "user_id", userID)
+
+	cmd := commands.RegenerateRecoveryCodesCommand{
+		UserID: types.ID(userID),
+		Code:   code,
+	}
+
+	result, err := pipeline.Send[*commands.RegenerateRecoveryCodesResult](a.requestContext(), a.appService.Bus, cmd)
+	if err != nil {
+		a.logger.Error("Failed to regenerate recovery codes", "error", err)
+		return nil, err
+	}
+
+	a.logger.Info("Recovery codes regenerated", "user_id", result.UserID)
+	return result, nil
+}
+
+// DisableTwoFactor removes a user's TOTP enrollment
+func (a *App) DisableTwoFactor(userID, code string) (*commands.DisableTwoFactorResult, error) {
+	a.logger.Info("DisableTwoFactor method called", "user_id", userID)
+
+	cmd := commands.DisableTwoFactorCommand{
+		UserID: types.ID(userID),
+		Code:   code,
+	}
+
+	result, err := pipeline.Send[*commands.DisableTwoFactorResult](a.requestContext(), a.appService.Bus, cmd)
+	if err != nil {
+		a.logger.Error("Failed to disable two-factor", "error", err)
+		return nil, err
+	}
+
+	a.logger.Info("Two-factor disabled", "user_id", result.UserID)
+	return result, nil
+}
+
+// SetTwoFactorRequirement sets whether a user must use two-factor authentication
+func (a *App) SetTwoFactorRequirement(userID string, required bool) (*commands.SetTwoFactorRequirementResult, error) {
+	a.logger.Info("SetTwoFactorRequirement method called", "user_id", userID, "required", required)
+
+	cmd := commands.SetTwoFactorRequirementCommand{
+		UserID:   types.ID(userID),
+		Required: required,
+	}
+
+	result, err := pipeline.Send[*commands.SetTwoFactorRequirementResult](a.requestContext(), a.appService.Bus, cmd)
+	if err != nil {
+		a.logger.Error("Failed to set two-factor requirement", "error", err)
+		return nil, err
+	}
+
+	a.logger.Info("Two-factor requirement updated", "user_id", result.UserID, "required", result.TwoFactorRequired)
+	return result, nil
+}
+
+// GetTwoFactorStatus retrieves a user's two-factor status
+func (a *App) GetTwoFactorStatus(userID string) (*queries.GetTwoFactorStatusResult, error) {
+	a.logger.Info("GetTwoFactorStatus method called", "user_id", userID)
+
+	query := queries.GetTwoFactorStatusQuery{
+		UserID: types.ID(userID),
+	}
+
+	result, err := pipeline.Send[*queries.GetTwoFactorStatusResult](a.requestContext(), a.appService.Bus, query)
+	if err != nil {
+		a.logger.Error("Failed to get two-factor status", "error", err)
+		return nil, err
+	}
+
+	return result, nil
+}
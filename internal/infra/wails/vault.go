@@ -0,0 +1,41 @@
+package wails
+
+// GetVaultStatus describes the vault holding device keys
+func (a *App) GetVaultStatus() VaultStatus {
+	return a.deviceKeys.status()
+}
+
+// UnlockVault opens the vault with the passphrase and moves the device keys
+// received while it was locked into it
+func (a *App) UnlockVault(passphrase string) error {
+	a.logger.Info("UnlockVault method called")
+
+	if err := a.deviceKeys.unlock([]byte(passphrase)); err != nil {
+		a.logger.Error("Failed to unlock vault", "error", err)
+		return err
+	}
+
+	a.logger.Info("Vault unlocked")
+	return nil
+}
+
+// LockVault closes the vault; device keys cannot be used until it is
+// unlocked again
+func (a *App) LockVault() {
+	a.logger.Info("LockVault method called")
+	a.deviceKeys.lock()
+}
+
+// ChangeVaultPassphrase rotates the vault key to one derived from a new
+// passphrase and re-encrypts every stored secret under it
+func (a *App) ChangeVaultPassphrase(currentPassphrase, newPassphrase string) error {
+	a.logger.Info("ChangeVaultPassphrase method called")
+
+	if err := a.deviceKeys.rotate([]byte(currentPassphrase), []byte(newPassphrase)); err != nil {
+		a.logger.Error("Failed to change vault passphrase", "error", err)
+		return err
+	}
+
+	a.logger.Info("Vault key rotated", "key_version", a.deviceKeys.status().KeyVersion)
+	return nil
+}
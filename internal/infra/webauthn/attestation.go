@@ -0,0 +1,143 @@
+package webauthn
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+)
+
+// Attestation statement formats
+const (
+	FormatNone   = "none"
+	FormatPacked = "packed"
+)
+
+// oidFIDOAAGUID is the certificate extension carrying the authenticator AAGUID
+var oidFIDOAAGUID = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 45724, 1, 1, 4}
+
+// attestationObject is the decoded attestation object
+type attestationObject struct {
+	format   string
+	stmt     map[any]any
+	authData []byte
+}
+
+// parseAttestationObject decodes the CBOR attestation object
+func parseAttestationObject(raw []byte) (*attestationObject, error) {
+	decoded, rest, err := decodeCBOR(raw)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) != 0 {
+		return nil, errors.New("trailing bytes in attestation object")
+	}
+
+	entries, ok := decoded.(map[any]any)
+	if !ok {
+		return nil, errors.New("attestation object is not a map")
+	}
+
+	format, _ := entries["fmt"].(string)
+	stmt, _ := entries["attStmt"].(map[any]any)
+	authData, _ := entries["authData"].([]byte)
+	if format == "" || stmt == nil || authData == nil {
+		return nil, errors.New("attestation object is missing fields")
+	}
+
+	return &attestationObject{
+		format:   format,
+		stmt:     stmt,
+		authData: authData,
+	}, nil
+}
+
+// verifyAttestation checks the attestation statement for its format
+func verifyAttestation(object *attestationObject, authData *authenticatorData, clientDataHash []byte) error {
+	switch object.format {
+	case FormatNone:
+		if len(object.stmt) != 0 {
+			return errors.New("none attestation must have an empty statement")
+		}
+		return nil
+	case FormatPacked:
+		return verifyPackedAttestation(object, authData, clientDataHash)
+	default:
+		return fmt.Errorf("unsupported attestation format %q", object.format)
+	}
+}
+
+// verifyPackedAttestation verifies a packed attestation statement, either
+// self attestation or full attestation with an x5c certificate chain
+func verifyPackedAttestation(object *attestationObject, authData *authenticatorData, clientDataHash []byte) error {
+	algorithm, ok := object.stmt["alg"].(int64)
+	if !ok {
+		return errors.New("packed attestation is missing alg")
+	}
+	signature, ok := object.stmt["sig"].([]byte)
+	if !ok {
+		return errors.New("packed attestation is missing sig")
+	}
+	signed := append(append([]byte(nil), object.authData...), clientDataHash...)
+
+	chain, hasChain := object.stmt["x5c"].([]any)
+	if !hasChain {
+		// Self attestation: signed by the credential key itself
+		credentialKey, err := parseCOSEKey(authData.publicKey)
+		if err != nil {
+			return err
+		}
+		if credentialKey.algorithm != algorithm {
+			return errors.New("self attestation algorithm does not match credential key")
+		}
+		return credentialKey.verify(signed, signature)
+	}
+
+	if len(chain) == 0 {
+		return errors.New("packed attestation has an empty x5c")
+	}
+	leafDER, ok := chain[0].([]byte)
+	if !ok {
+		return errors.New("packed attestation x5c is malformed")
+	}
+	leaf, err := x509.ParseCertificate(leafDER)
+	if err != nil {
+		return fmt.Errorf("invalid attestation certificate: %w", err)
+	}
+	if err := checkPackedCertificate(leaf, authData.aaguid); err != nil {
+		return err
+	}
+	return verifySignature(algorithm, leaf.PublicKey, signed, signature)
+}
+
+// checkPackedCertificate enforces the packed attestation certificate requirements
+func checkPackedCertificate(cert *x509.Certificate, aaguid []byte) error {
+	if cert.Version != 3 {
+		return errors.New("attestation certificate must be version 3")
+	}
+	if cert.IsCA {
+		return errors.New("attestation certificate must not be a CA")
+	}
+	organizationalUnit := cert.Subject.OrganizationalUnit
+	if len(organizationalUnit) != 1 || organizationalUnit[0] != "Authenticator Attestation" {
+		return errors.New("attestation certificate has an invalid subject OU")
+	}
+
+	for _, extension := range cert.Extensions {
+		if !extension.Id.Equal(oidFIDOAAGUID) {
+			continue
+		}
+		if extension.Critical {
+			return errors.New("AAGUID extension must not be critical")
+		}
+		var certAAGUID []byte
+		if _, err := asn1.Unmarshal(extension.Value, &certAAGUID); err != nil {
+			return errors.New("malformed AAGUID extension")
+		}
+		if !bytes.Equal(certAAGUID, aaguid) {
+			return errors.New("attestation certificate AAGUID mismatch")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package webauthn
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// Authenticator data flags
+const (
+	flagUserPresent     = 0x01
+	flagUserVerified    = 0x04
+	flagAttestedData    = 0x40
+	flagExtensionData   = 0x80
+	authDataMinLength   = 37
+	aaguidLength        = 16
+	credentialIDLenSize = 2
+)
+
+// authenticatorData is the parsed authenticator data structure
+type authenticatorData struct {
+	rpIDHash     []byte
+	flags        byte
+	signCount    uint32
+	aaguid       []byte
+	credentialID []byte
+	publicKey    []byte
+}
+
+func (d *authenticatorData) userPresent() bool  { return d.flags&flagUserPresent != 0 }
+func (d *authenticatorData) userVerified() bool { return d.flags&flagUserVerified != 0 }
+
+// parseAuthenticatorData parses raw authenticator data, including attested
+// credential data when the AT flag is set
+func parseAuthenticatorData(raw []byte) (*authenticatorData, error) {
+	if len(raw) < authDataMinLength {
+		return nil, errors.New("authenticator data too short")
+	}
+
+	data := &authenticatorData{
+		rpIDHash:  raw[:32],
+		flags:     raw[32],
+		signCount: binary.BigEndian.Uint32(raw[33:37]),
+	}
+	rest := raw[authDataMinLength:]
+
+	if data.flags&flagAttestedData != 0 {
+		if len(rest) < aaguidLength+credentialIDLenSize {
+			return nil, errors.New("attested credential data too short")
+		}
+		data.aaguid = rest[:aaguidLength]
+		idLength := int(binary.BigEndian.Uint16(rest[aaguidLength:]))
+		rest = rest[aaguidLength+credentialIDLenSize:]
+		if idLength == 0 || len(rest) < idLength {
+			return nil, errors.New("invalid credential ID length")
+		}
+		data.credentialID = rest[:idLength]
+		rest = rest[idLength:]
+
+		// The public key is a single CBOR item; its encoded length is whatever
+		// the decoder consumed
+		_, remaining, err := decodeCBOR(rest)
+		if err != nil {
+			return nil, errors.New("invalid credential public key")
+		}
+		data.publicKey = rest[:len(rest)-len(remaining)]
+		rest = remaining
+	}
+
+	if data.flags&flagExtensionData != 0 {
+		_, remaining, err := decodeCBOR(rest)
+		if err != nil {
+			return nil, errors.New("invalid extension data")
+		}
+		rest = remaining
+	}
+
+	if len(rest) != 0 {
+		return nil, errors.New("trailing bytes in authenticator data")
+	}
+	return data, nil
+}
+
+// buildAuthenticatorData serializes authenticator data
+func buildAuthenticatorData(rpIDHash []byte, flags byte, signCount uint32, aaguid, credentialID, publicKey []byte) []byte {
+	raw := make([]byte, 0, authDataMinLength+len(credentialID)+len(publicKey)+aaguidLength+credentialIDLenSize)
+	raw = append(raw, rpIDHash...)
+	raw = append(raw, flags)
+	raw = binary.BigEndian.AppendUint32(raw, signCount)
+	if flags&flagAttestedData != 0 {
+		raw = append(raw, aaguid...)
+		raw = binary.BigEndian.AppendUint16(raw, uint16(len(credentialID)))
+		raw = append(raw, credentialID...)
+		raw = append(raw, publicKey...)
+	}
+	return raw
+}
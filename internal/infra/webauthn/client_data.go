@@ -0,0 +1,46 @@
+package webauthn
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+// Client data ceremony types
+const (
+	ceremonyCreate = "webauthn.create"
+	ceremonyGet    = "webauthn.get"
+)
+
+// clientData is the collected client data passed to the authenticator
+type clientData struct {
+	Type        string `json:"type"`
+	Challenge   string `json:"challenge"`
+	Origin      string `json:"origin"`
+	CrossOrigin bool   `json:"crossOrigin,omitempty"`
+}
+
+// verifyClientData checks the ceremony type, challenge and origin of the client data
+func verifyClientData(raw []byte, ceremony string, challenge []byte, origins []string) error {
+	var data clientData
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return errors.New("malformed client data")
+	}
+
+	if data.Type != ceremony {
+		return errors.New("unexpected ceremony type")
+	}
+
+	received, err := base64.RawURLEncoding.DecodeString(data.Challenge)
+	if err != nil || subtle.ConstantTimeCompare(received, challenge) != 1 {
+		return errors.New("challenge mismatch")
+	}
+
+	for _, origin := range origins {
+		if data.Origin == origin {
+			return nil
+		}
+	}
+	return errors.New("origin not allowed")
+}
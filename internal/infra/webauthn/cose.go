@@ -0,0 +1,173 @@
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// COSE key parameters (RFC 8152)
+const (
+	coseKeyType   = 1
+	coseAlgorithm = 3
+	coseCurve     = -1
+	coseX         = -2
+	coseY         = -3
+	coseRSAN      = -1
+	coseRSAE      = -2
+
+	coseKeyTypeOKP = 1
+	coseKeyTypeEC2 = 2
+	coseKeyTypeRSA = 3
+
+	coseCurveP256    = 1
+	coseCurveEd25519 = 6
+)
+
+// COSE algorithm identifiers supported by the relying party
+const (
+	AlgES256 int64 = -7
+	AlgEdDSA int64 = -8
+	AlgRS256 int64 = -257
+)
+
+// SupportedAlgorithms lists the algorithms offered in creation options, in preference order
+var SupportedAlgorithms = []int64{AlgES256, AlgEdDSA, AlgRS256}
+
+// coseKey is a parsed COSE public key
+type coseKey struct {
+	algorithm int64
+	publicKey crypto.PublicKey
+}
+
+// parseCOSEKey parses a CBOR-encoded COSE public key
+func parseCOSEKey(data []byte) (*coseKey, error) {
+	decoded, rest, err := decodeCBOR(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) != 0 {
+		return nil, errors.New("cose: trailing data after key")
+	}
+	return coseKeyFromMap(decoded)
+}
+
+func coseKeyFromMap(decoded any) (*coseKey, error) {
+	params, ok := decoded.(map[any]any)
+	if !ok {
+		return nil, errors.New("cose: key is not a map")
+	}
+
+	keyType, _ := params[int64(coseKeyType)].(int64)
+	algorithm, ok := params[int64(coseAlgorithm)].(int64)
+	if !ok {
+		return nil, errors.New("cose: missing algorithm")
+	}
+
+	switch keyType {
+	case coseKeyTypeEC2:
+		curve, _ := params[int64(coseCurve)].(int64)
+		x, _ := params[int64(coseX)].([]byte)
+		y, _ := params[int64(coseY)].([]byte)
+		if algorithm != AlgES256 || curve != coseCurveP256 || len(x) != 32 || len(y) != 32 {
+			return nil, errors.New("cose: unsupported EC2 key")
+		}
+		publicKey := &ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}
+		if !publicKey.Curve.IsOnCurve(publicKey.X, publicKey.Y) {
+			return nil, errors.New("cose: EC2 point is not on curve")
+		}
+		return &coseKey{algorithm: algorithm, publicKey: publicKey}, nil
+	case coseKeyTypeOKP:
+		curve, _ := params[int64(coseCurve)].(int64)
+		x, _ := params[int64(coseX)].([]byte)
+		if algorithm != AlgEdDSA || curve != coseCurveEd25519 || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("cose: unsupported OKP key")
+		}
+		return &coseKey{algorithm: algorithm, publicKey: ed25519.PublicKey(x)}, nil
+	case coseKeyTypeRSA:
+		n, _ := params[int64(coseRSAN)].([]byte)
+		e, _ := params[int64(coseRSAE)].([]byte)
+		if algorithm != AlgRS256 || len(n) < 256 || len(e) == 0 || len(e) > 4 {
+			return nil, errors.New("cose: unsupported RSA key")
+		}
+		publicKey := &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+		return &coseKey{algorithm: algorithm, publicKey: publicKey}, nil
+	default:
+		return nil, fmt.Errorf("cose: unsupported key type %d", keyType)
+	}
+}
+
+// verify checks a WebAuthn signature over the signed data
+func (k *coseKey) verify(signed, signature []byte) error {
+	return verifySignature(k.algorithm, k.publicKey, signed, signature)
+}
+
+// verifySignature checks a signature produced with the given COSE algorithm
+func verifySignature(algorithm int64, publicKey crypto.PublicKey, signed, signature []byte) error {
+	switch algorithm {
+	case AlgES256:
+		key, ok := publicKey.(*ecdsa.PublicKey)
+		if !ok {
+			return errors.New("signature: key does not match ES256")
+		}
+		digest := sha256.Sum256(signed)
+		if !ecdsa.VerifyASN1(key, digest[:], signature) {
+			return errors.New("signature: ES256 verification failed")
+		}
+		return nil
+	case AlgEdDSA:
+		key, ok := publicKey.(ed25519.PublicKey)
+		if !ok {
+			return errors.New("signature: key does not match EdDSA")
+		}
+		if !ed25519.Verify(key, signed, signature) {
+			return errors.New("signature: EdDSA verification failed")
+		}
+		return nil
+	case AlgRS256:
+		key, ok := publicKey.(*rsa.PublicKey)
+		if !ok {
+			return errors.New("signature: key does not match RS256")
+		}
+		digest := sha256.Sum256(signed)
+		return rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature)
+	default:
+		return fmt.Errorf("signature: unsupported algorithm %d", algorithm)
+	}
+}
+
+// encodeCOSEKey encodes an ECDSA P-256 or Ed25519 public key in COSE form
+func encodeCOSEKey(publicKey crypto.PublicKey) ([]byte, error) {
+	switch key := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		return encodeCBOR(map[any]any{
+			int64(coseKeyType):   int64(coseKeyTypeEC2),
+			int64(coseAlgorithm): AlgES256,
+			int64(coseCurve):     int64(coseCurveP256),
+			int64(coseX):         key.X.FillBytes(make([]byte, 32)),
+			int64(coseY):         key.Y.FillBytes(make([]byte, 32)),
+		})
+	case ed25519.PublicKey:
+		return encodeCBOR(map[any]any{
+			int64(coseKeyType):   int64(coseKeyTypeOKP),
+			int64(coseAlgorithm): AlgEdDSA,
+			int64(coseCurve):     int64(coseCurveEd25519),
+			int64(coseX):         []byte(key),
+		})
+	default:
+		return nil, fmt.Errorf("cose: unsupported public key %T", publicKey)
+	}
+}
@@ -0,0 +1,143 @@
+package webauthn
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+
+	"shadow-id/internal/domain/services"
+)
+
+const challengeSize = 32
+
+// Config holds relying party configuration
+type Config struct {
+	RPID                    string
+	RPName                  string
+	Origins                 []string
+	TimeoutSeconds          int
+	RequireUserVerification bool
+}
+
+// RelyingParty implements the WebAuthn relying party ceremonies
+type RelyingParty struct {
+	config   Config
+	rpIDHash [32]byte
+}
+
+// NewRelyingParty creates a new relying party
+func NewRelyingParty(config Config) *RelyingParty {
+	if config.TimeoutSeconds <= 0 {
+		config.TimeoutSeconds = 300
+	}
+	return &RelyingParty{
+		config:   config,
+		rpIDHash: sha256.Sum256([]byte(config.RPID)),
+	}
+}
+
+// RelyingParty returns the relying party description
+func (rp *RelyingParty) RelyingParty() services.WebAuthnRelyingParty {
+	return services.WebAuthnRelyingParty{
+		ID:             rp.config.RPID,
+		Name:           rp.config.RPName,
+		Algorithms:     SupportedAlgorithms,
+		TimeoutSeconds: rp.config.TimeoutSeconds,
+	}
+}
+
+// NewChallenge generates a random ceremony challenge
+func (rp *RelyingParty) NewChallenge() ([]byte, error) {
+	challenge := make([]byte, challengeSize)
+	if _, err := rand.Read(challenge); err != nil {
+		return nil, err
+	}
+	return challenge, nil
+}
+
+// VerifyRegistration verifies an attestation response against the issued challenge
+func (rp *RelyingParty) VerifyRegistration(challenge []byte, response services.WebAuthnRegistrationResponse) (*services.WebAuthnAttestedCredential, error) {
+	if err := verifyClientData(response.ClientDataJSON, ceremonyCreate, challenge, rp.config.Origins); err != nil {
+		return nil, fmt.Errorf("registration: %w", err)
+	}
+
+	object, err := parseAttestationObject(response.AttestationObject)
+	if err != nil {
+		return nil, fmt.Errorf("registration: %w", err)
+	}
+	authData, err := parseAuthenticatorData(object.authData)
+	if err != nil {
+		return nil, fmt.Errorf("registration: %w", err)
+	}
+	if err := rp.checkAuthenticatorData(authData); err != nil {
+		return nil, fmt.Errorf("registration: %w", err)
+	}
+	if authData.credentialID == nil {
+		return nil, fmt.Errorf("registration: attested credential data missing")
+	}
+
+	// The credential key must be one we can verify assertions with
+	if _, err := parseCOSEKey(authData.publicKey); err != nil {
+		return nil, fmt.Errorf("registration: %w", err)
+	}
+
+	clientDataHash := sha256.Sum256(response.ClientDataJSON)
+	if err := verifyAttestation(object, authData, clientDataHash[:]); err != nil {
+		return nil, fmt.Errorf("registration: %w", err)
+	}
+
+	return &services.WebAuthnAttestedCredential{
+		CredentialID:      append([]byte(nil), authData.credentialID...),
+		PublicKey:         append([]byte(nil), authData.publicKey...),
+		AAGUID:            append([]byte(nil), authData.aaguid...),
+		AttestationFormat: object.format,
+		SignCount:         authData.signCount,
+		UserVerified:      authData.userVerified(),
+	}, nil
+}
+
+// VerifyAssertion verifies an assertion response against the issued challenge
+// and the stored COSE public key
+func (rp *RelyingParty) VerifyAssertion(challenge, publicKey []byte, response services.WebAuthnAssertionResponse) (*services.WebAuthnAssertion, error) {
+	if err := verifyClientData(response.ClientDataJSON, ceremonyGet, challenge, rp.config.Origins); err != nil {
+		return nil, fmt.Errorf("assertion: %w", err)
+	}
+
+	authData, err := parseAuthenticatorData(response.AuthenticatorData)
+	if err != nil {
+		return nil, fmt.Errorf("assertion: %w", err)
+	}
+	if err := rp.checkAuthenticatorData(authData); err != nil {
+		return nil, fmt.Errorf("assertion: %w", err)
+	}
+
+	key, err := parseCOSEKey(publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("assertion: %w", err)
+	}
+	clientDataHash := sha256.Sum256(response.ClientDataJSON)
+	signed := append(append([]byte(nil), response.AuthenticatorData...), clientDataHash[:]...)
+	if err := key.verify(signed, response.Signature); err != nil {
+		return nil, fmt.Errorf("assertion: %w", err)
+	}
+
+	return &services.WebAuthnAssertion{
+		SignCount:    authData.signCount,
+		UserVerified: authData.userVerified(),
+	}, nil
+}
+
+// checkAuthenticatorData checks the RP ID hash and user presence/verification flags
+func (rp *RelyingParty) checkAuthenticatorData(authData *authenticatorData) error {
+	if subtle.ConstantTimeCompare(authData.rpIDHash, rp.rpIDHash[:]) != 1 {
+		return fmt.Errorf("RP ID hash mismatch")
+	}
+	if !authData.userPresent() {
+		return fmt.Errorf("user presence flag not set")
+	}
+	if rp.config.RequireUserVerification && !authData.userVerified() {
+		return fmt.Errorf("user verification required")
+	}
+	return nil
+}
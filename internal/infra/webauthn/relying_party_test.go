@@ -0,0 +1,170 @@
+package webauthn
+
+import (
+	"testing"
+
+	"shadow-id/internal/domain/services"
+)
+
+const (
+	testRPID   = "localhost"
+	testOrigin = "http://localhost:34115"
+)
+
+func newTestRelyingParty() *RelyingParty {
+	return NewRelyingParty(Config{
+		RPID:    testRPID,
+		RPName:  "Shadow ID",
+		Origins: []string{testOrigin},
+	})
+}
+
+func TestRegistrationAndAssertion(t *testing.T) {
+	tests := []struct {
+		name        string
+		format      string
+		certificate bool
+	}{
+		{name: "none", format: FormatNone},
+		{name: "packed self attestation", format: FormatPacked},
+		{name: "packed full attestation", format: FormatPacked, certificate: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rp := newTestRelyingParty()
+			authenticator := NewSoftwareAuthenticator(tt.format)
+			authenticator.AttestationCertificate = tt.certificate
+
+			challenge, err := rp.NewChallenge()
+			if err != nil {
+				t.Fatalf("NewChallenge() error = %v", err)
+			}
+			credentialID, clientDataJSON, attestationObject, err := authenticator.Register(testRPID, testOrigin, challenge)
+			if err != nil {
+				t.Fatalf("Register() error = %v", err)
+			}
+
+			credential, err := rp.VerifyRegistration(challenge, services.WebAuthnRegistrationResponse{
+				ClientDataJSON:    clientDataJSON,
+				AttestationObject: attestationObject,
+			})
+			if err != nil {
+				t.Fatalf("VerifyRegistration() error = %v", err)
+			}
+			if string(credential.CredentialID) != string(credentialID) {
+				t.Errorf("CredentialID mismatch")
+			}
+			if credential.AttestationFormat != tt.format {
+				t.Errorf("AttestationFormat = %q, want %q", credential.AttestationFormat, tt.format)
+			}
+
+			challenge, _ = rp.NewChallenge()
+			clientDataJSON, authData, signature, err := authenticator.Assert(testRPID, testOrigin, credentialID, challenge)
+			if err != nil {
+				t.Fatalf("Assert() error = %v", err)
+			}
+			assertion, err := rp.VerifyAssertion(challenge, credential.PublicKey, services.WebAuthnAssertionResponse{
+				ClientDataJSON:    clientDataJSON,
+				AuthenticatorData: authData,
+				Signature:         signature,
+			})
+			if err != nil {
+				t.Fatalf("VerifyAssertion() error = %v", err)
+			}
+			if assertion.SignCount != 1 {
+				t.Errorf("SignCount = %d, want 1", assertion.SignCount)
+			}
+		})
+	}
+}
+
+func TestVerifyRegistrationRejectsMismatches(t *testing.T) {
+	rp := newTestRelyingParty()
+	authenticator := NewSoftwareAuthenticator(FormatNone)
+	challenge, _ := rp.NewChallenge()
+
+	tests := []struct {
+		name      string
+		rpID      string
+		origin    string
+		challenge []byte
+	}{
+		{name: "wrong challenge", rpID: testRPID, origin: testOrigin, challenge: []byte("not-the-issued-challenge")},
+		{name: "wrong origin", rpID: testRPID, origin: "https://evil.example", challenge: challenge},
+		{name: "wrong rp id", rpID: "evil.example", origin: testOrigin, challenge: challenge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, clientDataJSON, attestationObject, err := authenticator.Register(tt.rpID, tt.origin, tt.challenge)
+			if err != nil {
+				t.Fatalf("Register() error = %v", err)
+			}
+			_, err = rp.VerifyRegistration(challenge, services.WebAuthnRegistrationResponse{
+				ClientDataJSON:    clientDataJSON,
+				AttestationObject: attestationObject,
+			})
+			if err == nil {
+				t.Fatal("VerifyRegistration() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestVerifyAssertionRejectsTamperedSignature(t *testing.T) {
+	rp := newTestRelyingParty()
+	authenticator := NewSoftwareAuthenticator(FormatNone)
+
+	challenge, _ := rp.NewChallenge()
+	credentialID, clientDataJSON, attestationObject, _ := authenticator.Register(testRPID, testOrigin, challenge)
+	credential, err := rp.VerifyRegistration(challenge, services.WebAuthnRegistrationResponse{
+		ClientDataJSON:    clientDataJSON,
+		AttestationObject: attestationObject,
+	})
+	if err != nil {
+		t.Fatalf("VerifyRegistration() error = %v", err)
+	}
+
+	challenge, _ = rp.NewChallenge()
+	clientDataJSON, authData, signature, _ := authenticator.Assert(testRPID, testOrigin, credentialID, challenge)
+	signature[len(signature)-1] ^= 0xff
+
+	_, err = rp.VerifyAssertion(challenge, credential.PublicKey, services.WebAuthnAssertionResponse{
+		ClientDataJSON:    clientDataJSON,
+		AuthenticatorData: authData,
+		Signature:         signature,
+	})
+	if err == nil {
+		t.Fatal("VerifyAssertion() error = nil, want error")
+	}
+}
+
+func TestCBORRoundTrip(t *testing.T) {
+	value := map[any]any{
+		int64(1):  int64(2),
+		int64(-1): []byte{0x01, 0x02},
+		"fmt":     "packed",
+		"list":    []any{true, false, nil, int64(-300)},
+	}
+
+	encoded, err := encodeCBOR(value)
+	if err != nil {
+		t.Fatalf("encodeCBOR() error = %v", err)
+	}
+	decoded, rest, err := decodeCBOR(encoded)
+	if err != nil {
+		t.Fatalf("decodeCBOR() error = %v", err)
+	}
+	if len(rest) != 0 {
+		t.Fatalf("decodeCBOR() left %d trailing bytes", len(rest))
+	}
+
+	reencoded, err := encodeCBOR(decoded)
+	if err != nil {
+		t.Fatalf("encodeCBOR() error = %v", err)
+	}
+	if string(reencoded) != string(encoded) {
+		t.Errorf("round trip mismatch: %x != %x", reencoded, encoded)
+	}
+}
@@ -0,0 +1,194 @@
+package webauthn
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"time"
+)
+
+// SoftwareAuthenticator is an in-process authenticator that produces real
+// WebAuthn attestation and assertion responses. It exists so ceremonies can be
+// exercised end to end in unit tests and local tooling without hardware.
+type SoftwareAuthenticator struct {
+	AAGUID []byte
+
+	// Format selects the attestation format produced on registration
+	// (FormatNone or FormatPacked)
+	Format string
+
+	// AttestationCertificate switches packed attestation from self
+	// attestation to full attestation with a generated x5c certificate
+	AttestationCertificate bool
+
+	credentials map[string]*softwareCredential
+}
+
+// softwareCredential is a key pair held by the software authenticator
+type softwareCredential struct {
+	privateKey *ecdsa.PrivateKey
+	signCount  uint32
+}
+
+// NewSoftwareAuthenticator creates a software authenticator producing the given attestation format
+func NewSoftwareAuthenticator(format string) *SoftwareAuthenticator {
+	aaguid := make([]byte, aaguidLength)
+	_, _ = rand.Read(aaguid)
+	return &SoftwareAuthenticator{
+		AAGUID:      aaguid,
+		Format:      format,
+		credentials: make(map[string]*softwareCredential),
+	}
+}
+
+// Register performs the authenticator side of a registration ceremony and
+// returns the credential ID, client data JSON and attestation object
+func (a *SoftwareAuthenticator) Register(rpID, origin string, challenge []byte) ([]byte, []byte, []byte, error) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	credentialID := make([]byte, 32)
+	if _, err := rand.Read(credentialID); err != nil {
+		return nil, nil, nil, err
+	}
+	publicKey, err := encodeCOSEKey(&privateKey.PublicKey)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	credential := &softwareCredential{privateKey: privateKey}
+	a.credentials[string(credentialID)] = credential
+
+	clientDataJSON, err := softwareClientData(ceremonyCreate, origin, challenge)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	rpIDHash := sha256.Sum256([]byte(rpID))
+	flags := byte(flagUserPresent | flagUserVerified | flagAttestedData)
+	authData := buildAuthenticatorData(rpIDHash[:], flags, credential.signCount, a.AAGUID, credentialID, publicKey)
+
+	stmt, err := a.attestationStatement(privateKey, authData, clientDataJSON)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	attestationObject, err := encodeCBOR(map[any]any{
+		"fmt":      a.Format,
+		"attStmt":  stmt,
+		"authData": authData,
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return credentialID, clientDataJSON, attestationObject, nil
+}
+
+// Assert performs the authenticator side of an authentication ceremony and
+// returns the client data JSON, authenticator data and signature
+func (a *SoftwareAuthenticator) Assert(rpID, origin string, credentialID, challenge []byte) ([]byte, []byte, []byte, error) {
+	credential, ok := a.credentials[string(credentialID)]
+	if !ok {
+		return nil, nil, nil, errors.New("unknown credential")
+	}
+	credential.signCount++
+
+	clientDataJSON, err := softwareClientData(ceremonyGet, origin, challenge)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	rpIDHash := sha256.Sum256([]byte(rpID))
+	authData := buildAuthenticatorData(rpIDHash[:], flagUserPresent|flagUserVerified, credential.signCount, nil, nil, nil)
+
+	signature, err := signES256(credential.privateKey, authData, clientDataJSON)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return clientDataJSON, authData, signature, nil
+}
+
+// SetSignCount overrides the stored sign counter for a credential, which
+// lets callers simulate a cloned authenticator
+func (a *SoftwareAuthenticator) SetSignCount(credentialID []byte, signCount uint32) {
+	if credential, ok := a.credentials[string(credentialID)]; ok {
+		credential.signCount = signCount
+	}
+}
+
+// attestationStatement builds the attStmt map for the configured format
+func (a *SoftwareAuthenticator) attestationStatement(credentialKey *ecdsa.PrivateKey, authData, clientDataJSON []byte) (map[any]any, error) {
+	if a.Format != FormatPacked {
+		return map[any]any{}, nil
+	}
+
+	if !a.AttestationCertificate {
+		signature, err := signES256(credentialKey, authData, clientDataJSON)
+		if err != nil {
+			return nil, err
+		}
+		return map[any]any{"alg": AlgES256, "sig": signature}, nil
+	}
+
+	attestationKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	certificate, err := a.attestationCertificate(attestationKey)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := signES256(attestationKey, authData, clientDataJSON)
+	if err != nil {
+		return nil, err
+	}
+	return map[any]any{"alg": AlgES256, "sig": signature, "x5c": []any{certificate}}, nil
+}
+
+// attestationCertificate creates a self-signed certificate meeting the packed
+// attestation certificate requirements
+func (a *SoftwareAuthenticator) attestationCertificate(key *ecdsa.PrivateKey) ([]byte, error) {
+	aaguidExtension, err := asn1.Marshal(a.AAGUID)
+	if err != nil {
+		return nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject: pkix.Name{
+			Country:            []string{"US"},
+			Organization:       []string{"Shadow ID Software Authenticator"},
+			OrganizationalUnit: []string{"Authenticator Attestation"},
+			CommonName:         "Shadow ID Software Authenticator",
+		},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		BasicConstraintsValid: true,
+		IsCA:                  false,
+		ExtraExtensions: []pkix.Extension{
+			{Id: oidFIDOAAGUID, Value: aaguidExtension},
+		},
+	}
+	return x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+}
+
+// softwareClientData builds client data JSON as a browser would
+func softwareClientData(ceremony, origin string, challenge []byte) ([]byte, error) {
+	return json.Marshal(clientData{
+		Type:      ceremony,
+		Challenge: base64.RawURLEncoding.EncodeToString(challenge),
+		Origin:    origin,
+	})
+}
+
+// signES256 signs authenticator data concatenated with the client data hash
+func signES256(key *ecdsa.PrivateKey, authData, clientDataJSON []byte) ([]byte, error) {
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	digest := sha256.Sum256(append(append([]byte(nil), authData...), clientDataHash[:]...))
+	return ecdsa.SignASN1(rand.Reader, key, digest[:])
+}
@@ -8,19 +8,24 @@ import (
 type ErrorType string
 
 const (
-	ErrorTypeValidation ErrorType = "validation"
-	ErrorTypeNotFound   ErrorType = "not_found"
-	ErrorTypeConflict   ErrorType = "conflict"
-	ErrorTypeInternal   ErrorType = "internal"
-	ErrorTypeExternal   ErrorType = "external"
+	ErrorTypeValidation   ErrorType = "validation"
+	ErrorTypeNotFound     ErrorType = "not_found"
+	ErrorTypeConflict     ErrorType = "conflict"
+	ErrorTypeInternal     ErrorType = "internal"
+	ErrorTypeExternal     ErrorType = "external"
+	ErrorTypeRateLimited  ErrorType = "rate_limited"
+	ErrorTypeUnauthorized ErrorType = "unauthorized"
+	ErrorTypeForbidden    ErrorType = "forbidden"
+	ErrorTypeTimeout      ErrorType = "timeout"
 )
 
 // AppError represents an application error with additional context
 type AppError struct {
-	Type    ErrorType `json:"type"`
-	Message string    `json:"message"`
-	Code    string    `json:"code,omitempty"`
-	Cause   error     `json:"-"`
+	Type    ErrorType              `json:"type"`
+	Message string                 `json:"message"`
+	Code    string                 `json:"code,omitempty"`
+	Details map[string]interface{} `json:"details,omitempty"`
+	Cause   error                  `json:"-"`
 }
 
 // Error implements the error interface
@@ -31,6 +36,15 @@ func (e *AppError) Error() string {
 	return e.Message
 }
 
+// WithDetail attaches a detail value to the error and returns it for chaining
+func (e *AppError) WithDetail(key string, value interface{}) *AppError {
+	if e.Details == nil {
+		e.Details = make(map[string]interface{})
+	}
+	e.Details[key] = value
+	return e
+}
+
 // Unwrap returns the underlying error
 func (e *AppError) Unwrap() error {
 	return e.Cause
@@ -49,16 +63,20 @@ func Wrap(err error, message string) *AppError {
 	if err == nil {
 		return nil
 	}
-	
-	// If it's already an AppError, wrap it
+
+	// If it's already an AppError, wrap it keeping its type and details
 	if appErr, ok := err.(*AppError); ok {
-		return &AppError{
+		wrapped := &AppError{
 			Type:    appErr.Type,
 			Message: message,
 			Cause:   appErr,
 		}
+		for key, value := range appErr.Details {
+			wrapped.WithDetail(key, value)
+		}
+		return wrapped
 	}
-	
+
 	return &AppError{
 		Type:    ErrorTypeInternal,
 		Message: message,
@@ -66,6 +84,19 @@ func Wrap(err error, message string) *AppError {
 	}
 }
 
+// WrapWithType wraps an existing error with additional context and an explicit error type
+func WrapWithType(err error, errorType ErrorType, message string) *AppError {
+	if err == nil {
+		return nil
+	}
+
+	return &AppError{
+		Type:    errorType,
+		Message: message,
+		Cause:   err,
+	}
+}
+
 // NewValidationError creates a new validation error
 func NewValidationError(message string) *AppError {
 	return &AppError{
@@ -90,6 +121,38 @@ func NewConflictError(message string) *AppError {
 	}
 }
 
+// NewRateLimitedError creates a new rate limited error
+func NewRateLimitedError(message string) *AppError {
+	return &AppError{
+		Type:    ErrorTypeRateLimited,
+		Message: message,
+	}
+}
+
+// NewUnauthorizedError creates a new unauthorized error
+func NewUnauthorizedError(message string) *AppError {
+	return &AppError{
+		Type:    ErrorTypeUnauthorized,
+		Message: message,
+	}
+}
+
+// NewForbiddenError creates a new forbidden error
+func NewForbiddenError(message string) *AppError {
+	return &AppError{
+		Type:    ErrorTypeForbidden,
+		Message: message,
+	}
+}
+
+// NewTimeoutError creates a new timeout error
+func NewTimeoutError(message string) *AppError {
+	return &AppError{
+		Type:    ErrorTypeTimeout,
+		Message: message,
+	}
+}
+
 // NewInternalError creates a new internal error
 func NewInternalError(message string) *AppError {
 	return &AppError{
@@ -121,6 +184,26 @@ func IsConflictError(err error) bool {
 	return IsType(err, ErrorTypeConflict)
 }
 
+// IsRateLimitedError checks if the error is a rate limited error
+func IsRateLimitedError(err error) bool {
+	return IsType(err, ErrorTypeRateLimited)
+}
+
+// IsUnauthorizedError checks if the error is an unauthorized error
+func IsUnauthorizedError(err error) bool {
+	return IsType(err, ErrorTypeUnauthorized)
+}
+
+// IsForbiddenError checks if the error is a forbidden error
+func IsForbiddenError(err error) bool {
+	return IsType(err, ErrorTypeForbidden)
+}
+
+// IsTimeoutError checks if the error is a timeout error
+func IsTimeoutError(err error) bool {
+	return IsType(err, ErrorTypeTimeout)
+}
+
 // IsInternalError checks if the error is an internal error
 func IsInternalError(err error) bool {
 	return IsType(err, ErrorTypeInternal)
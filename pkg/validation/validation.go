@@ -0,0 +1,133 @@
+package validation
+
+import (
+	"fmt"
+	"net/mail"
+	"net/netip"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"shadow-id/pkg/errors"
+)
+
+// Struct validates a struct against its `validate` tags and returns a
+// validation error describing every failing field. Supported rules are
+// required, omitempty, min=N, max=N, email and ip; min and max bound string
+// length in characters, collection length or numeric value.
+func Struct(v interface{}) error {
+	value := reflect.ValueOf(v)
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+
+	failures := make(map[string]interface{})
+	var first string
+	valueType := value.Type()
+	for i := 0; i < valueType.NumField(); i++ {
+		field := valueType.Field(i)
+		tag := field.Tag.Get("validate")
+		if tag == "" || !field.IsExported() {
+			continue
+		}
+
+		name := fieldName(field)
+		if message := validateField(value.Field(i), tag); message != "" {
+			failures[name] = message
+			if first == "" {
+				first = name + " " + message
+			}
+		}
+	}
+
+	if len(failures) == 0 {
+		return nil
+	}
+	err := errors.NewValidationError("invalid request: " + first)
+	for name, message := range failures {
+		err.WithDetail(name, message)
+	}
+	return err
+}
+
+// validateField applies the comma separated rules of a tag to a field value
+func validateField(field reflect.Value, tag string) string {
+	rules := strings.Split(tag, ",")
+	empty := field.IsZero()
+	for _, rule := range rules {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "omitempty":
+			if empty {
+				return ""
+			}
+		case "required":
+			if empty {
+				return "is required"
+			}
+		case "min", "max":
+			limit, err := strconv.ParseFloat(param, 64)
+			if err != nil {
+				return fmt.Sprintf("has an invalid %s rule", name)
+			}
+			size, ok := measure(field)
+			if !ok {
+				continue
+			}
+			if name == "min" && size < limit {
+				return fmt.Sprintf("must be at least %s", param)
+			}
+			if name == "max" && size > limit {
+				return fmt.Sprintf("must be at most %s", param)
+			}
+		case "email":
+			if field.Kind() != reflect.String {
+				continue
+			}
+			address, err := mail.ParseAddress(field.String())
+			if err != nil || address.Address != field.String() {
+				return "must be a valid email address"
+			}
+		case "ip":
+			if field.Kind() != reflect.String {
+				continue
+			}
+			if _, err := netip.ParseAddr(field.String()); err != nil {
+				return "must be a valid IP address"
+			}
+		}
+	}
+	return ""
+}
+
+// measure returns the length or numeric value min and max rules compare against
+func measure(field reflect.Value) (float64, bool) {
+	switch field.Kind() {
+	case reflect.String:
+		return float64(utf8.RuneCountInString(field.String())), true
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return float64(field.Len()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(field.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(field.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return field.Float(), true
+	}
+	return 0, false
+}
+
+// fieldName returns the JSON name of a field, falling back to the Go name
+func fieldName(field reflect.StructField) string {
+	if name, _, _ := strings.Cut(field.Tag.Get("json"), ","); name != "" && name != "-" {
+		return name
+	}
+	return field.Name
+}